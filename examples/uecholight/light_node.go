@@ -13,7 +13,7 @@ import (
 
 // NewLightNode returns a new light device.
 func NewLightNode() echonet.LocalNode {
-	onRequest := func(obj echonet.Object, esv protocol.ESV, reqProp protocol.Property) error {
+	onRequest := func(ctx echonet.RequestContext, obj echonet.Object, esv protocol.ESV, reqProp protocol.Property) error {
 		// Only handle write requests.
 		if !esv.IsWriteRequest() {
 			return nil
@@ -0,0 +1,298 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+// AccessAction represents an action of the access rule.
+type AccessAction int
+
+const (
+	// AccessAllow allows the matched requests.
+	AccessAllow AccessAction = iota
+	// AccessDeny denies the matched requests.
+	AccessDeny
+)
+
+// AccessService represents a set of services of the request messages.
+type AccessService int
+
+const (
+	// AccessServiceRead represents the read requests such as ESV = 0x62 and 0x6E.
+	AccessServiceRead AccessService = 1 << iota
+	// AccessServiceWrite represents the write requests such as ESV = 0x60, 0x61 and 0x6E.
+	AccessServiceWrite
+	// AccessServiceNotification represents the notification requests such as ESV = 0x63.
+	AccessServiceNotification
+	// AccessServiceAll represents all services of the request messages.
+	AccessServiceAll = AccessServiceRead | AccessServiceWrite | AccessServiceNotification
+)
+
+const (
+	errAccessInvalidSource = "%w: access source (%s)"
+)
+
+// AccessAuditHandler is called when a request message is denied by the access policy.
+// The denied property codes are empty when the denied request has no property.
+type AccessAuditHandler func(ctx RequestContext, deniedProps []PropertyCode)
+
+// AccessRuleOption is a function that configures an access rule.
+type AccessRuleOption func(*AccessRule)
+
+// AccessRule represents an access rule for the request messages.
+type AccessRule struct {
+	action   AccessAction
+	sources  []string
+	networks []*net.IPNet
+	services AccessService
+	props    []PropertyCode
+}
+
+// WithAccessRuleSources sets the source addresses of the rule. The address is an IP address or a CIDR such as "192.168.1.0/24".
+// The rule matches all sources when no source is specified.
+func WithAccessRuleSources(srcs ...string) AccessRuleOption {
+	return func(rule *AccessRule) {
+		rule.sources = append(rule.sources, srcs...)
+	}
+}
+
+// WithAccessRuleServices sets the services of the rule. The rule matches all services by default.
+func WithAccessRuleServices(services AccessService) AccessRuleOption {
+	return func(rule *AccessRule) {
+		rule.services = services
+	}
+}
+
+// WithAccessRuleProperties sets the property codes of the rule.
+// The rule matches all properties when no property is specified.
+func WithAccessRuleProperties(codes ...PropertyCode) AccessRuleOption {
+	return func(rule *AccessRule) {
+		rule.props = append(rule.props, codes...)
+	}
+}
+
+// NewAccessRule returns a new access rule with the specified action and options.
+func NewAccessRule(action AccessAction, opts ...AccessRuleOption) (*AccessRule, error) {
+	rule := &AccessRule{
+		action:   action,
+		sources:  []string{},
+		networks: []*net.IPNet{},
+		services: AccessServiceAll,
+		props:    []PropertyCode{},
+	}
+	for _, opt := range opts {
+		opt(rule)
+	}
+	for _, src := range rule.sources {
+		network, err := parseAccessSource(src)
+		if err != nil {
+			return nil, err
+		}
+		rule.networks = append(rule.networks, network)
+	}
+	return rule, nil
+}
+
+// parseAccessSource parses the specified IP address or CIDR.
+func parseAccessSource(src string) (*net.IPNet, error) {
+	if strings.Contains(src, "/") {
+		_, network, err := net.ParseCIDR(src)
+		if err != nil {
+			return nil, fmt.Errorf(errAccessInvalidSource, ErrInvalid, src)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(src)
+	if ip == nil {
+		return nil, fmt.Errorf(errAccessInvalidSource, ErrInvalid, src)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Action returns the action of the rule.
+func (rule *AccessRule) Action() AccessAction {
+	return rule.action
+}
+
+// matchesSource returns true when the rule matches the specified source address, otherwise false.
+func (rule *AccessRule) matchesSource(ip net.IP) bool {
+	if len(rule.networks) == 0 {
+		return true
+	}
+	for _, network := range rule.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesService returns true when the rule matches the specified services, otherwise false.
+func (rule *AccessRule) matchesService(services AccessService) bool {
+	return (rule.services & services) != 0
+}
+
+// matchesProperty returns true when the rule matches the specified property, otherwise false.
+func (rule *AccessRule) matchesProperty(code PropertyCode) bool {
+	if len(rule.props) == 0 {
+		return true
+	}
+	return slices.Contains(rule.props, code)
+}
+
+// AccessPolicyOption is a function that configures an access policy.
+type AccessPolicyOption func(*AccessPolicy)
+
+// AccessPolicy represents an access control policy for the request messages from other nodes.
+// The rules are evaluated in order for each requested property, and the first matched rule decides the action.
+// The default action is applied when no rule matches.
+// The source-specific rules never match the requests from unknown sources, and the requests without properties
+// are decided by the rules which are not limited to any property.
+// The arbitrary message format (Format 2) messages are decided as the requests of all services without properties.
+type AccessPolicy struct {
+	defaultAction AccessAction
+	rules         []*AccessRule
+	auditHandler  AccessAuditHandler
+}
+
+// WithAccessPolicyDefaultAction sets the action when no rule matches. The default action is AccessAllow.
+func WithAccessPolicyDefaultAction(action AccessAction) AccessPolicyOption {
+	return func(policy *AccessPolicy) {
+		policy.defaultAction = action
+	}
+}
+
+// WithAccessPolicyRules adds the specified rules to the policy.
+func WithAccessPolicyRules(rules ...*AccessRule) AccessPolicyOption {
+	return func(policy *AccessPolicy) {
+		policy.rules = append(policy.rules, rules...)
+	}
+}
+
+// WithAccessPolicyAuditHandler sets a handler which is called when a request message is denied.
+func WithAccessPolicyAuditHandler(h AccessAuditHandler) AccessPolicyOption {
+	return func(policy *AccessPolicy) {
+		policy.auditHandler = h
+	}
+}
+
+// NewAccessPolicy returns a new access policy with the specified options.
+func NewAccessPolicy(opts ...AccessPolicyOption) *AccessPolicy {
+	policy := &AccessPolicy{
+		defaultAction: AccessAllow,
+		rules:         []*AccessRule{},
+		auditHandler:  nil,
+	}
+	for _, opt := range opts {
+		opt(policy)
+	}
+	return policy
+}
+
+// accessServicesOf returns the services of the specified service code.
+func accessServicesOf(esv protocol.ESV) AccessService {
+	var services AccessService
+	if esv.IsReadRequest() {
+		services |= AccessServiceRead
+	}
+	if esv.IsWriteRequest() {
+		services |= AccessServiceWrite
+	}
+	if esv.IsNotificationRequest() {
+		services |= AccessServiceNotification
+	}
+	return services
+}
+
+// action returns the action of the first matched rule for the specified source, services and property.
+func (policy *AccessPolicy) action(ip net.IP, services AccessService, code PropertyCode) AccessAction {
+	for _, rule := range policy.rules {
+		if !rule.matchesSource(ip) || !rule.matchesService(services) || !rule.matchesProperty(code) {
+			continue
+		}
+		return rule.action
+	}
+	return policy.defaultAction
+}
+
+// requestAction returns the action of the first matched rule which is not limited to any property for the specified source and services.
+// The action is undecided when a rule limited to the properties matches before it, and then the action depends on each property.
+func (policy *AccessPolicy) requestAction(ip net.IP, services AccessService) (AccessAction, bool) {
+	decided := true
+	for _, rule := range policy.rules {
+		if !rule.matchesSource(ip) || !rule.matchesService(services) {
+			continue
+		}
+		if len(rule.props) != 0 {
+			decided = false
+			continue
+		}
+		return rule.action, decided
+	}
+	return policy.defaultAction, decided
+}
+
+// evaluate returns the action and the denied property codes of the specified request message.
+func (policy *AccessPolicy) evaluate(msg *protocol.Message) (AccessAction, []PropertyCode) {
+	deniedProps := []PropertyCode{}
+	services := accessServicesOf(msg.ESV())
+	if msg.IsFormat2() {
+		services = AccessServiceAll
+	}
+	if services == 0 {
+		return AccessAllow, deniedProps
+	}
+
+	var ip net.IP
+	if msg.From != nil {
+		ip = msg.From.IP
+	}
+	props := msg.Properties()
+
+	// The source and services decide the action first to deny the request regardless of the properties.
+
+	action, decided := policy.requestAction(ip, services)
+	if decided || len(props) == 0 {
+		if action == AccessDeny {
+			for _, prop := range props {
+				deniedProps = append(deniedProps, prop.Code())
+			}
+		}
+		return action, deniedProps
+	}
+
+	action = AccessAllow
+	for _, prop := range props {
+		if policy.action(ip, services, prop.Code()) == AccessDeny {
+			deniedProps = append(deniedProps, prop.Code())
+			action = AccessDeny
+		}
+	}
+	return action, deniedProps
+}
+
+// DeniedProperties returns the denied property codes of the specified request message.
+// The other messages such as responses and notifications are always allowed.
+// Use IsAllowed to check the requests without properties.
+func (policy *AccessPolicy) DeniedProperties(msg *protocol.Message) []PropertyCode {
+	_, deniedProps := policy.evaluate(msg)
+	return deniedProps
+}
+
+// IsAllowed returns true when the specified request message and all its properties are allowed, otherwise false.
+func (policy *AccessPolicy) IsAllowed(msg *protocol.Message) bool {
+	action, _ := policy.evaluate(msg)
+	return action == AccessAllow
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestAccessPolicyRules(t *testing.T) {
+	newTestRule := func(t *testing.T, action AccessAction, opts ...AccessRuleOption) *AccessRule {
+		t.Helper()
+		rule, err := NewAccessRule(action, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rule
+	}
+
+	newTestMessage := func(src string, esv protocol.ESV, codes ...PropertyCode) *protocol.Message {
+		msg := protocol.NewMessage()
+		msg.From.IP = net.ParseIP(src)
+		msg.SetESV(esv)
+		for _, code := range codes {
+			msg.AddProperty(protocol.NewPropertyWithCode(code))
+		}
+		return msg
+	}
+
+	policy := NewAccessPolicy(
+		WithAccessPolicyDefaultAction(AccessDeny),
+		WithAccessPolicyRules(
+			newTestRule(t, AccessDeny, WithAccessRuleSources("192.168.1.10"), WithAccessRuleServices(AccessServiceWrite)),
+			newTestRule(t, AccessDeny, WithAccessRuleServices(AccessServiceWrite), WithAccessRuleProperties(DeviceInstallationLocation)),
+			newTestRule(t, AccessAllow, WithAccessRuleSources("192.168.1.0/24", "fe80::/10")),
+		),
+	)
+
+	tests := []struct {
+		src         string
+		esv         protocol.ESV
+		codes       []PropertyCode
+		deniedProps []PropertyCode
+	}{
+		{"192.168.1.20", protocol.ESVWriteRequest, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{}},
+		{"192.168.1.20", protocol.ESVReadRequest, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{}},
+		{"192.168.1.10", protocol.ESVReadRequest, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{}},
+		{"192.168.1.10", protocol.ESVWriteRequestResponseRequired, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{DeviceOperatingStatus}},
+		{"192.168.1.20", protocol.ESVWriteRequest, []PropertyCode{DeviceOperatingStatus, DeviceInstallationLocation}, []PropertyCode{DeviceInstallationLocation}},
+		{"192.168.1.20", protocol.ESVReadRequest, []PropertyCode{DeviceInstallationLocation}, []PropertyCode{}},
+		{"fe80::1", protocol.ESVNotificationRequest, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{}},
+		{"192.168.2.20", protocol.ESVReadRequest, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{DeviceOperatingStatus}},
+		{"192.168.2.20", protocol.ESVNotification, []PropertyCode{DeviceOperatingStatus}, []PropertyCode{}},
+	}
+
+	for _, test := range tests {
+		msg := newTestMessage(test.src, test.esv, test.codes...)
+		deniedProps := policy.DeniedProperties(msg)
+		if !slices.Equal(deniedProps, test.deniedProps) {
+			t.Errorf("%s %02X %v : %v != %v", test.src, test.esv, test.codes, deniedProps, test.deniedProps)
+		}
+	}
+
+	// The requests without properties or from unknown sources are decided by the rules which are not limited to any property.
+
+	unknownSrcMsg := newTestMessage("192.168.1.20", protocol.ESVReadRequest, DeviceOperatingStatus)
+	unknownSrcMsg.From = nil
+
+	allowTests := []struct {
+		msg     *protocol.Message
+		allowed bool
+	}{
+		{newTestMessage("192.168.1.20", protocol.ESVReadRequest), true},
+		{newTestMessage("192.168.1.10", protocol.ESVWriteRequest), false},
+		{newTestMessage("192.168.2.20", protocol.ESVReadRequest), false},
+		{newTestMessage("192.168.2.20", protocol.ESVNotification), true},
+		{unknownSrcMsg, false},
+	}
+
+	for _, test := range allowTests {
+		if policy.IsAllowed(test.msg) != test.allowed {
+			t.Errorf("%s %02X %v : %t != %t", test.msg.From, test.msg.ESV(), test.msg.Properties(), !test.allowed, test.allowed)
+		}
+	}
+
+	if deniedProps := policy.DeniedProperties(unknownSrcMsg); !slices.Equal(deniedProps, []PropertyCode{DeviceOperatingStatus}) {
+		t.Errorf("%v != %v", deniedProps, []PropertyCode{DeviceOperatingStatus})
+	}
+
+	for _, src := range []string{"192.168.1", "192.168.1.0/33", ""} {
+		if _, err := NewAccessRule(AccessAllow, WithAccessRuleSources(src)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s is accepted (%v)", src, err)
+		}
+	}
+}
+
+func TestLocalNodeAccessPolicy(t *testing.T) {
+	var mutex sync.Mutex
+	auditEvents := []PropertyCode{}
+
+	rule, err := NewAccessRule(AccessDeny,
+		WithAccessRuleServices(AccessServiceWrite),
+		WithAccessRuleProperties(DeviceOperatingStatus),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := NewAccessPolicy(
+		WithAccessPolicyRules(rule),
+		WithAccessPolicyAuditHandler(func(ctx RequestContext, deniedProps []PropertyCode) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			auditEvents = append(auditEvents, deniedProps...)
+		}),
+	)
+
+	dev, err := NewDevice(
+		WithDeviceCode(testLightDeviceCode),
+		WithDeviceRequestHandler(func(ctx RequestContext, obj Object, esv protocol.ESV, prop protocol.Property) error {
+			if esv.IsWriteRequest() {
+				t.Errorf("denied request is handled : %s", ctx.Message())
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(
+		WithLocalNodeConfig(conf),
+		WithLocalNodeDevices(dev),
+		WithLocalNodeAccessPolicy(policy),
+	)
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	postMessage := func(t *testing.T, esv protocol.ESV, prop Property) Message {
+		t.Helper()
+		reqMsg := NewMessage(
+			WithMessageDEOJ(testLightDeviceCode),
+			WithMessageESV(esv),
+			WithMessageProperties(prop),
+		)
+		resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resMsg
+	}
+
+	resMsg := postMessage(t, protocol.ESVWriteRequestResponseRequired,
+		NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOff})),
+	)
+	if resMsg.ESV() != protocol.ESVWriteRequestResponseRequiredError {
+		t.Errorf("%s", resMsg)
+	}
+
+	resMsg = postMessage(t, protocol.ESVReadRequest, NewProperty(WithPropertyCode(DeviceOperatingStatus)))
+	if resMsg.ESV() != protocol.ESVReadResponse {
+		t.Errorf("%s", resMsg)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !slices.Equal(auditEvents, []PropertyCode{DeviceOperatingStatus}) {
+		t.Errorf("%v != %v", auditEvents, []PropertyCode{DeviceOperatingStatus})
+	}
+}
@@ -6,6 +6,8 @@ package echonet
 
 import (
 	"fmt"
+	"slices"
+	"sync"
 )
 
 const (
@@ -15,7 +17,10 @@ const (
 )
 
 // baseNode is an instance for Echonet node.
+// The object lists are replaced with new copies when they are changed, so that the returned lists are stable snapshots
+// which are never modified by other goroutines.
 type baseNode struct {
+	mutex    *sync.RWMutex
 	devices  []Device
 	profiles []Profile
 }
@@ -23,6 +28,7 @@ type baseNode struct {
 // NewbaseNode returns a new node.
 func newBaseNode() *baseNode {
 	node := &baseNode{
+		mutex:    &sync.RWMutex{},
 		devices:  make([]Device, 0),
 		profiles: make([]Profile, 0),
 	}
@@ -31,17 +37,35 @@ func newBaseNode() *baseNode {
 
 // AddDevice adds a new device into the node.
 func (node *baseNode) AddDevice(dev Device) {
-	node.devices = append(node.devices, dev)
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	node.devices = slices.Concat(node.devices, []Device{dev})
+}
+
+// RemoveDevice removes the specified device from the node.
+func (node *baseNode) RemoveDevice(dev Device) error {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	for n, nodeDev := range node.devices {
+		if nodeDev.Code() != dev.Code() {
+			continue
+		}
+		node.devices = slices.Delete(slices.Clone(node.devices), n, n+1)
+		return nil
+	}
+	return fmt.Errorf(errObjectNotFound, ErrNotFound, dev.Code())
 }
 
 // Devices returns all device objects.
 func (node *baseNode) Devices() []Device {
+	node.mutex.RLock()
+	defer node.mutex.RUnlock()
 	return node.devices
 }
 
 // LookupDevice returns a specified device object.
 func (node *baseNode) LookupDevice(code ObjectCode) (Device, error) {
-	for _, dev := range node.devices {
+	for _, dev := range node.Devices() {
 		objCode := dev.Code()
 		if objCode == code {
 			return dev, nil
@@ -52,17 +76,21 @@ func (node *baseNode) LookupDevice(code ObjectCode) (Device, error) {
 
 // AddProfile adds a new profile object into the node.
 func (node *baseNode) AddProfile(prof Profile) {
-	node.profiles = append(node.profiles, prof)
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	node.profiles = slices.Concat(node.profiles, []Profile{prof})
 }
 
 // Profiles returns all profile objects.
 func (node *baseNode) Profiles() []Profile {
+	node.mutex.RLock()
+	defer node.mutex.RUnlock()
 	return node.profiles
 }
 
 // LookupProfile returns a specified profile object.
 func (node *baseNode) LookupProfile(code ObjectCode) (Profile, error) {
-	for _, prof := range node.profiles {
+	for _, prof := range node.Profiles() {
 		objCode := prof.Code()
 		if objCode == code {
 			return prof, nil
@@ -0,0 +1,24 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"errors"
+)
+
+// ErrInvalid is returned when the capture file is invalid.
+var ErrInvalid = errors.New("invalid")
+
+const (
+	errInvalidMagic      = "%w: capture file magic (%08X)"
+	errInvalidByteOrder  = "%w: pcapng byte order magic (%08X)"
+	errInvalidBlockSize  = "%w: pcapng block (%08X) size : %d"
+	errNoSectionHeader   = "%w: pcapng block (%08X) before section header"
+	errUnknownInterface  = "%w: pcapng interface (%d)"
+	errInvalidRecordSize = "%w: capture record size : %d > %d"
+	errTruncatedRecord   = "%w: truncated capture record : %w"
+	errTruncatedBlock    = "%w: pcapng block (%08X) : %d < %d"
+	errPacketTooLarge    = "%w: packet size : %d > %d"
+)
@@ -0,0 +1,206 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+// LinkType represents a link-layer header type of the captured packets.
+type LinkType uint32
+
+const (
+	// LinkTypeNull represents the BSD loopback encapsulation.
+	LinkTypeNull LinkType = 0
+	// LinkTypeEthernet represents the Ethernet frames.
+	LinkTypeEthernet LinkType = 1
+	// LinkTypeRaw represents the raw IPv4 or IPv6 packets.
+	LinkTypeRaw LinkType = 101
+	// LinkTypeLinuxSLL represents the Linux cooked capture encapsulation.
+	LinkTypeLinuxSLL LinkType = 113
+	// LinkTypeIPv4 represents the raw IPv4 packets.
+	LinkTypeIPv4 LinkType = 228
+	// LinkTypeIPv6 represents the raw IPv6 packets.
+	LinkTypeIPv6 LinkType = 229
+	// LinkTypeLinuxSLL2 represents the Linux cooked capture encapsulation version 2.
+	LinkTypeLinuxSLL2 LinkType = 276
+)
+
+const (
+	etherTypeIPv4     = 0x0800
+	etherTypeIPv6     = 0x86DD
+	etherTypeVLAN     = 0x8100
+	etherTypeQinQ     = 0x88A8
+	ipProtocolTCP     = 6
+	ipProtocolUDP     = 17
+	ipv4HeaderSize    = 20
+	ipv6HeaderSize    = 40
+	udpHeaderSize     = 8
+	tcpHeaderSize     = 20
+	ethernetHeaderLen = 14
+	vlanTagSize       = 4
+	sllHeaderSize     = 16
+	sll2HeaderSize    = 20
+	nullHeaderSize    = 4
+)
+
+// Packet represents an ECHONET Lite message frame in the captured packets.
+type Packet struct {
+	Timestamp time.Time
+	Network   string
+	From      *protocol.Address
+	To        *protocol.Address
+	// Payload is the UDP or TCP payload of the captured packet.
+	Payload []byte
+	// Message is the parsed message, and is nil when the payload could not be parsed at all.
+	Message *protocol.Message
+	// Err is the parse error of the payload in the parse mode of the reader.
+	Err error
+}
+
+// String returns the string representation of the packet.
+func (pkt *Packet) String() string {
+	msg := fmt.Sprintf("%X", pkt.Payload)
+	if pkt.Message != nil {
+		msg = pkt.Message.String()
+	}
+	return fmt.Sprintf("%s %s %s -> %s %s", pkt.Timestamp.Format(time.RFC3339Nano), pkt.Network, pkt.From, pkt.To, msg)
+}
+
+// segment represents a UDP datagram or TCP segment in the captured packet.
+type segment struct {
+	network string
+	from    *protocol.Address
+	to      *protocol.Address
+	payload []byte
+}
+
+// ipPacketOf returns the IP packet in the specified link-layer frame.
+func ipPacketOf(linkType LinkType, data []byte) ([]byte, bool) {
+	switch linkType {
+	case LinkTypeRaw, LinkTypeIPv4, LinkTypeIPv6:
+		return data, true
+	case LinkTypeNull:
+		if len(data) < nullHeaderSize {
+			return nil, false
+		}
+		return data[nullHeaderSize:], true
+	case LinkTypeEthernet:
+		if len(data) < ethernetHeaderLen {
+			return nil, false
+		}
+		offset := ethernetHeaderLen
+		etherType := binary.BigEndian.Uint16(data[12:14])
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(data) < offset+vlanTagSize {
+				return nil, false
+			}
+			etherType = binary.BigEndian.Uint16(data[offset+2 : offset+4])
+			offset += vlanTagSize
+		}
+		if etherType != etherTypeIPv4 && etherType != etherTypeIPv6 {
+			return nil, false
+		}
+		return data[offset:], true
+	case LinkTypeLinuxSLL:
+		if len(data) < sllHeaderSize {
+			return nil, false
+		}
+		return data[sllHeaderSize:], true
+	case LinkTypeLinuxSLL2:
+		if len(data) < sll2HeaderSize {
+			return nil, false
+		}
+		return data[sll2HeaderSize:], true
+	}
+	return nil, false
+}
+
+// segmentOf returns the UDP or TCP segment in the specified IP packet.
+func segmentOf(data []byte) (*segment, bool) {
+	if len(data) < 1 {
+		return nil, false
+	}
+	var srcIP, dstIP net.IP
+	var proto byte
+	var payload []byte
+	switch data[0] >> 4 {
+	case 4:
+		if len(data) < ipv4HeaderSize {
+			return nil, false
+		}
+		headerSize := int(data[0]&0x0F) * 4
+		totalSize := int(binary.BigEndian.Uint16(data[2:4]))
+		if headerSize < ipv4HeaderSize || totalSize < headerSize || len(data) < headerSize {
+			return nil, false
+		}
+		// Fragmented packets are not reassembled.
+		if binary.BigEndian.Uint16(data[6:8])&0x3FFF != 0 {
+			return nil, false
+		}
+		proto = data[9]
+		srcIP = net.IP(data[12:16])
+		dstIP = net.IP(data[16:20])
+		payload = data[headerSize:min(totalSize, len(data))]
+	case 6:
+		if len(data) < ipv6HeaderSize {
+			return nil, false
+		}
+		proto = data[6]
+		srcIP = net.IP(data[8:24])
+		dstIP = net.IP(data[24:40])
+		payload = data[ipv6HeaderSize:min(ipv6HeaderSize+int(binary.BigEndian.Uint16(data[4:6])), len(data))]
+		// Skips the hop-by-hop, routing and destination options headers.
+		for proto == 0 || proto == 43 || proto == 60 {
+			if len(payload) < 2 {
+				return nil, false
+			}
+			extSize := (int(payload[1]) + 1) * 8
+			if len(payload) < extSize {
+				return nil, false
+			}
+			proto = payload[0]
+			payload = payload[extSize:]
+		}
+	default:
+		return nil, false
+	}
+
+	seg := &segment{
+		network: "",
+		from:    &protocol.Address{IP: srcIP, Port: 0, Zone: ""},
+		to:      &protocol.Address{IP: dstIP, Port: 0, Zone: ""},
+		payload: nil,
+	}
+	switch proto {
+	case ipProtocolUDP:
+		if len(payload) < udpHeaderSize {
+			return nil, false
+		}
+		seg.network = transport.NetworkUDP
+		seg.payload = payload[udpHeaderSize:]
+	case ipProtocolTCP:
+		if len(payload) < tcpHeaderSize {
+			return nil, false
+		}
+		headerSize := int(payload[12]>>4) * 4
+		if headerSize < tcpHeaderSize || len(payload) < headerSize {
+			return nil, false
+		}
+		seg.network = transport.NetworkTCP
+		seg.payload = payload[headerSize:]
+	default:
+		return nil, false
+	}
+	seg.from.Port = int(binary.BigEndian.Uint16(payload[0:2]))
+	seg.to.Port = int(binary.BigEndian.Uint16(payload[2:4]))
+	return seg, true
+}
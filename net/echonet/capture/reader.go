@@ -0,0 +1,442 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"slices"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+const (
+	pcapMagicMicroseconds = 0xA1B2C3D4
+	pcapMagicNanoseconds  = 0xA1B23C4D
+	pcapHeaderSize        = 24
+	pcapRecordHeaderSize  = 16
+	pcapMaxRecordSize     = 0x40000
+
+	pcapngSectionHeaderBlock     = 0x0A0D0D0A
+	pcapngInterfaceBlock         = 0x00000001
+	pcapngObsoletePacketBlock    = 0x00000002
+	pcapngSimplePacketBlock      = 0x00000003
+	pcapngEnhancedPacketBlock    = 0x00000006
+	pcapngByteOrderMagic         = 0x1A2B3C4D
+	pcapngMaxBlockSize           = 0x1000000
+	pcapngOptionEnd              = 0
+	pcapngOptionTimestampRes     = 9
+	pcapngDefaultTimestampRes    = 6
+	pcapngMinBlockSize           = 12
+	pcapngSectionHeaderMinSize   = 28
+	pcapngInterfaceBodySize      = 8
+	pcapngPacketBodySize         = 20
+	pcapngSimplePacketBodySize   = 4
+	pcapngTimestampResPowerOfTwo = 0x80
+)
+
+// record represents a captured link-layer frame.
+type record struct {
+	timestamp time.Time
+	linkType  LinkType
+	data      []byte
+}
+
+// recordReader is an interface to read the captured frames in a capture file format.
+type recordReader interface {
+	readRecord() (*record, error)
+}
+
+// ReaderOption is a function that configures a capture reader.
+type ReaderOption func(*Reader)
+
+// WithReaderPorts sets the UDP and TCP ports of the ECHONET Lite messages. The default port is 3610.
+func WithReaderPorts(ports ...int) ReaderOption {
+	return func(reader *Reader) {
+		reader.ports = ports
+	}
+}
+
+// WithReaderParseMode sets the parse mode of the ECHONET Lite messages. The default mode is the strict mode.
+func WithReaderParseMode(mode protocol.ParseMode) ReaderOption {
+	return func(reader *Reader) {
+		reader.parseMode = mode
+	}
+}
+
+// Reader reads the ECHONET Lite messages from a pcap or pcapng capture file.
+// The Ethernet, BSD loopback, raw IP and Linux cooked capture frames are supported.
+// The fragmented IP packets are skipped, and each TCP segment is decoded as a message frame without the stream reassembly.
+type Reader struct {
+	records   recordReader
+	ports     []int
+	parseMode protocol.ParseMode
+}
+
+// NewReader returns a new capture reader of the specified pcap or pcapng stream.
+func NewReader(r io.Reader, opts ...ReaderOption) (*Reader, error) {
+	reader := &Reader{
+		records:   nil,
+		ports:     []int{transport.Port},
+		parseMode: protocol.ParseModeStrict,
+	}
+	for _, opt := range opts {
+		opt(reader)
+	}
+
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf(errTruncatedRecord, ErrInvalid, err)
+	}
+	switch binary.BigEndian.Uint32(magic) {
+	case pcapngSectionHeaderBlock:
+		reader.records = newPcapngReader(br)
+	default:
+		reader.records, err = newPcapReader(br)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reader, nil
+}
+
+// Next returns the next ECHONET Lite message in the capture file, and returns io.EOF at the end of the file.
+// The packets of the other ports and protocols are skipped.
+func (reader *Reader) Next() (*Packet, error) {
+	for {
+		rec, err := reader.records.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		ipPkt, ok := ipPacketOf(rec.linkType, rec.data)
+		if !ok {
+			continue
+		}
+		seg, ok := segmentOf(ipPkt)
+		if !ok || len(seg.payload) == 0 {
+			continue
+		}
+		if !slices.Contains(reader.ports, seg.from.Port) && !slices.Contains(reader.ports, seg.to.Port) {
+			continue
+		}
+		return reader.newPacket(rec.timestamp, seg), nil
+	}
+}
+
+// newPacket returns a new packet of the specified segment.
+// The message is parsed leniently again for the annotation when the payload is rejected in the strict mode.
+func (reader *Reader) newPacket(ts time.Time, seg *segment) *Packet {
+	pkt := &Packet{
+		Timestamp: ts,
+		Network:   seg.network,
+		From:      seg.from,
+		To:        seg.to,
+		Payload:   seg.payload,
+		Message:   nil,
+		Err:       nil,
+	}
+	msg := protocol.NewMessage()
+	msg.SetParseMode(reader.parseMode)
+	pkt.Err = msg.ParseBytes(seg.payload)
+	if pkt.Err == nil {
+		pkt.Message = msg
+		return pkt
+	}
+	if reader.parseMode != protocol.ParseModeLenient {
+		msg = protocol.NewMessage()
+		msg.SetParseMode(protocol.ParseModeLenient)
+		if msg.ParseBytes(seg.payload) == nil {
+			pkt.Message = msg
+		}
+	}
+	return pkt
+}
+
+// readFull reads the specified buffer, and returns io.EOF only when no byte is read.
+func readFull(r io.Reader, buf []byte) error {
+	_, err := io.ReadFull(r, buf)
+	switch {
+	case err == nil, errors.Is(err, io.EOF):
+		return err
+	default:
+		return fmt.Errorf(errTruncatedRecord, ErrInvalid, err)
+	}
+}
+
+// readRemaining reads the specified buffer in the middle of a record, and never returns io.EOF.
+func readRemaining(r io.Reader, buf []byte) error {
+	_, err := io.ReadFull(r, buf)
+	if err != nil {
+		return fmt.Errorf(errTruncatedRecord, ErrInvalid, err)
+	}
+	return nil
+}
+
+// pcapReader represents a reader of the pcap file format.
+type pcapReader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nanosec  bool
+	linkType LinkType
+	header   [pcapRecordHeaderSize]byte
+}
+
+// newPcapReader returns a new pcap reader after reading the global header.
+func newPcapReader(r io.Reader) (*pcapReader, error) {
+	var header [pcapHeaderSize]byte
+	if err := readRemaining(r, header[:]); err != nil {
+		return nil, err
+	}
+	reader := &pcapReader{
+		r:        r,
+		order:    nil,
+		nanosec:  false,
+		linkType: 0,
+		header:   [pcapRecordHeaderSize]byte{},
+	}
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		switch order.Uint32(header[0:4]) {
+		case pcapMagicMicroseconds:
+			reader.order = order
+		case pcapMagicNanoseconds:
+			reader.order = order
+			reader.nanosec = true
+		}
+		if reader.order != nil {
+			break
+		}
+	}
+	if reader.order == nil {
+		return nil, fmt.Errorf(errInvalidMagic, ErrInvalid, binary.BigEndian.Uint32(header[0:4]))
+	}
+	// The upper bits of the link type field are the FCS length and flags.
+	reader.linkType = LinkType(reader.order.Uint32(header[20:24]) & 0x0FFFFFFF)
+	return reader, nil
+}
+
+// readRecord reads the next captured frame.
+func (reader *pcapReader) readRecord() (*record, error) {
+	if err := readFull(reader.r, reader.header[:]); err != nil {
+		return nil, err
+	}
+	sec := reader.order.Uint32(reader.header[0:4])
+	frac := reader.order.Uint32(reader.header[4:8])
+	size := reader.order.Uint32(reader.header[8:12])
+	if pcapMaxRecordSize < size {
+		return nil, fmt.Errorf(errInvalidRecordSize, ErrInvalid, size, pcapMaxRecordSize)
+	}
+	data := make([]byte, size)
+	if err := readRemaining(reader.r, data); err != nil {
+		return nil, err
+	}
+	nsec := int64(frac)
+	if !reader.nanosec {
+		nsec *= int64(time.Microsecond)
+	}
+	return &record{
+		timestamp: time.Unix(int64(sec), nsec),
+		linkType:  reader.linkType,
+		data:      data,
+	}, nil
+}
+
+// pcapngInterface represents an interface description in the pcapng file format.
+type pcapngInterface struct {
+	linkType LinkType
+	// units is the number of the timestamp units per second.
+	units uint64
+}
+
+// timestamp returns the time of the specified timestamp units.
+func (iface *pcapngInterface) timestamp(ts uint64) time.Time {
+	sec := ts / iface.units
+	hi, lo := bits.Mul64(ts%iface.units, uint64(time.Second))
+	nsec, _ := bits.Div64(hi, lo, iface.units)
+	return time.Unix(int64(sec), int64(nsec))
+}
+
+// pcapngReader represents a reader of the pcapng file format.
+type pcapngReader struct {
+	r          io.Reader
+	order      binary.ByteOrder
+	interfaces []*pcapngInterface
+}
+
+// newPcapngReader returns a new pcapng reader.
+func newPcapngReader(r io.Reader) *pcapngReader {
+	return &pcapngReader{
+		r:          r,
+		order:      nil,
+		interfaces: []*pcapngInterface{},
+	}
+}
+
+// readBlock reads the next block, and returns the block type and body.
+func (reader *pcapngReader) readBlock() (uint32, []byte, error) {
+	var header [8]byte
+	if err := readFull(reader.r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	blockType := binary.BigEndian.Uint32(header[0:4])
+	var bom [4]byte
+	if blockType == pcapngSectionHeaderBlock {
+		// Each section has its own byte order.
+		if err := readRemaining(reader.r, bom[:]); err != nil {
+			return 0, nil, err
+		}
+		switch {
+		case binary.BigEndian.Uint32(bom[:]) == pcapngByteOrderMagic:
+			reader.order = binary.BigEndian
+		case binary.LittleEndian.Uint32(bom[:]) == pcapngByteOrderMagic:
+			reader.order = binary.LittleEndian
+		default:
+			return 0, nil, fmt.Errorf(errInvalidByteOrder, ErrInvalid, binary.BigEndian.Uint32(bom[:]))
+		}
+	}
+	if reader.order == nil {
+		return 0, nil, fmt.Errorf(errNoSectionHeader, ErrInvalid, blockType)
+	}
+	blockType = reader.order.Uint32(header[0:4])
+
+	blockSize := reader.order.Uint32(header[4:8])
+	minSize := uint32(pcapngMinBlockSize)
+	if blockType == pcapngSectionHeaderBlock {
+		minSize = pcapngSectionHeaderMinSize
+	}
+	if blockSize < minSize || pcapngMaxBlockSize < blockSize || blockSize%4 != 0 {
+		return 0, nil, fmt.Errorf(errInvalidBlockSize, ErrInvalid, blockType, blockSize)
+	}
+
+	// Reads the body and trailing block size.
+	body := make([]byte, blockSize-8)
+	offset := 0
+	if blockType == pcapngSectionHeaderBlock {
+		offset = copy(body, bom[:])
+	}
+	if err := readRemaining(reader.r, body[offset:]); err != nil {
+		return 0, nil, err
+	}
+
+	return blockType, body[:len(body)-4], nil
+}
+
+// readRecord reads the next captured frame.
+func (reader *pcapngReader) readRecord() (*record, error) {
+	for {
+		blockType, body, err := reader.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch blockType {
+		case pcapngSectionHeaderBlock:
+			reader.interfaces = []*pcapngInterface{}
+		case pcapngInterfaceBlock:
+			iface, err := reader.parseInterface(body)
+			if err != nil {
+				return nil, err
+			}
+			reader.interfaces = append(reader.interfaces, iface)
+		case pcapngEnhancedPacketBlock, pcapngObsoletePacketBlock:
+			return reader.parsePacket(blockType, body)
+		case pcapngSimplePacketBlock:
+			return reader.parseSimplePacket(body)
+		}
+	}
+}
+
+// parseInterface parses the specified interface description block body.
+func (reader *pcapngReader) parseInterface(body []byte) (*pcapngInterface, error) {
+	if len(body) < pcapngInterfaceBodySize {
+		return nil, fmt.Errorf(errTruncatedBlock, ErrInvalid, pcapngInterfaceBlock, len(body), pcapngInterfaceBodySize)
+	}
+	iface := &pcapngInterface{
+		linkType: LinkType(reader.order.Uint16(body[0:2])),
+		units:    0,
+	}
+	tsres := byte(pcapngDefaultTimestampRes)
+	opts := body[pcapngInterfaceBodySize:]
+	for 4 <= len(opts) {
+		code := reader.order.Uint16(opts[0:2])
+		size := int(reader.order.Uint16(opts[2:4]))
+		if code == pcapngOptionEnd || len(opts) < 4+size {
+			break
+		}
+		if code == pcapngOptionTimestampRes && size == 1 {
+			tsres = opts[4]
+		}
+		opts = opts[min(4+((size+3)&^3), len(opts)):]
+	}
+	switch {
+	case tsres&pcapngTimestampResPowerOfTwo != 0 && tsres&^pcapngTimestampResPowerOfTwo < 64:
+		iface.units = 1 << (tsres &^ pcapngTimestampResPowerOfTwo)
+	case tsres&pcapngTimestampResPowerOfTwo == 0 && tsres <= 19:
+		iface.units = 1
+		for range tsres {
+			iface.units *= 10
+		}
+	default:
+		iface.units = uint64(time.Second / time.Microsecond)
+	}
+	return iface, nil
+}
+
+// lookupInterface returns the interface of the specified index.
+func (reader *pcapngReader) lookupInterface(idx uint32) (*pcapngInterface, error) {
+	if len(reader.interfaces) <= int(idx) {
+		return nil, fmt.Errorf(errUnknownInterface, ErrInvalid, idx)
+	}
+	return reader.interfaces[idx], nil
+}
+
+// parsePacket parses the specified enhanced or obsolete packet block body.
+func (reader *pcapngReader) parsePacket(blockType uint32, body []byte) (*record, error) {
+	if len(body) < pcapngPacketBodySize {
+		return nil, fmt.Errorf(errTruncatedBlock, ErrInvalid, blockType, len(body), pcapngPacketBodySize)
+	}
+	idx := reader.order.Uint32(body[0:4])
+	if blockType == pcapngObsoletePacketBlock {
+		idx = uint32(reader.order.Uint16(body[0:2]))
+	}
+	iface, err := reader.lookupInterface(idx)
+	if err != nil {
+		return nil, err
+	}
+	ts := uint64(reader.order.Uint32(body[4:8]))<<32 | uint64(reader.order.Uint32(body[8:12]))
+	size := int(reader.order.Uint32(body[12:16]))
+	if len(body) < pcapngPacketBodySize+size {
+		return nil, fmt.Errorf(errTruncatedBlock, ErrInvalid, blockType, len(body), pcapngPacketBodySize+size)
+	}
+	return &record{
+		timestamp: iface.timestamp(ts),
+		linkType:  iface.linkType,
+		data:      body[pcapngPacketBodySize : pcapngPacketBodySize+size],
+	}, nil
+}
+
+// parseSimplePacket parses the specified simple packet block body which has no timestamp.
+func (reader *pcapngReader) parseSimplePacket(body []byte) (*record, error) {
+	if len(body) < pcapngSimplePacketBodySize {
+		return nil, fmt.Errorf(errTruncatedBlock, ErrInvalid, pcapngSimplePacketBlock, len(body), pcapngSimplePacketBodySize)
+	}
+	iface, err := reader.lookupInterface(0)
+	if err != nil {
+		return nil, err
+	}
+	size := min(int(reader.order.Uint32(body[0:4])), len(body)-pcapngSimplePacketBodySize)
+	return &record{
+		timestamp: time.Time{},
+		linkType:  iface.linkType,
+		data:      body[pcapngSimplePacketBodySize : pcapngSimplePacketBodySize+size],
+	}, nil
+}
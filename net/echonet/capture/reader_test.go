@@ -0,0 +1,187 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+func newTestIPPacket(t *testing.T, network string, src string, dst string, payload []byte) []byte {
+	t.Helper()
+	writer := &Writer{seqs: map[string]uint32{}}
+	from := &protocol.Address{IP: net.ParseIP(src), Port: transport.Port, Zone: ""}
+	to := &protocol.Address{IP: net.ParseIP(dst), Port: transport.Port, Zone: ""}
+	ipPkt, err := writer.appendIPPacket(nil, network, from, to, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ipPkt
+}
+
+type testByteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+func appendTestPcapngBlock(order testByteOrder, dst []byte, blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0x00)
+	}
+	blockSize := uint32(12 + len(body))
+	dst = order.AppendUint32(dst, blockType)
+	dst = order.AppendUint32(dst, blockSize)
+	dst = append(dst, body...)
+	return order.AppendUint32(dst, blockSize)
+}
+
+func TestPcapngReader(t *testing.T) {
+	for _, order := range []testByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			ts := time.Unix(1700000000, 123456789)
+			msgBytes := newTestMessageBytes(t, 1)
+
+			// Section header block
+			shb := order.AppendUint32(nil, pcapngByteOrderMagic)
+			shb = order.AppendUint16(shb, 1)
+			shb = order.AppendUint16(shb, 0)
+			shb = order.AppendUint64(shb, ^uint64(0))
+			file := appendTestPcapngBlock(order, nil, pcapngSectionHeaderBlock, shb)
+
+			// Interface description block with the nanosecond resolution
+			idb := order.AppendUint16(nil, uint16(LinkTypeEthernet))
+			idb = order.AppendUint16(idb, 0)
+			idb = order.AppendUint32(idb, pcapSnapLen)
+			idb = order.AppendUint16(idb, pcapngOptionTimestampRes)
+			idb = order.AppendUint16(idb, 1)
+			idb = append(idb, 9, 0, 0, 0)
+			idb = order.AppendUint16(idb, pcapngOptionEnd)
+			idb = order.AppendUint16(idb, 0)
+			file = appendTestPcapngBlock(order, file, pcapngInterfaceBlock, idb)
+
+			// Unknown block
+			file = appendTestPcapngBlock(order, file, 0x00000BAD, []byte{0x01, 0x02})
+
+			// Enhanced packet blocks with the VLAN tagged Ethernet frames
+			appendPacket := func(file []byte, etherType uint16, ipPkt []byte) []byte {
+				frame := make([]byte, 12)
+				frame = binary.BigEndian.AppendUint16(frame, etherTypeVLAN)
+				frame = binary.BigEndian.AppendUint16(frame, 0x0001)
+				frame = binary.BigEndian.AppendUint16(frame, etherType)
+				frame = append(frame, ipPkt...)
+				tsUnits := uint64(ts.UnixNano())
+				epb := order.AppendUint32(nil, 0)
+				epb = order.AppendUint32(epb, uint32(tsUnits>>32))
+				epb = order.AppendUint32(epb, uint32(tsUnits))
+				epb = order.AppendUint32(epb, uint32(len(frame)))
+				epb = order.AppendUint32(epb, uint32(len(frame)))
+				epb = append(epb, frame...)
+				return appendTestPcapngBlock(order, file, pcapngEnhancedPacketBlock, epb)
+			}
+			file = appendPacket(file, 0x0806, make([]byte, 28))
+			file = appendPacket(file, etherTypeIPv4, newTestIPPacket(t, transport.NetworkUDP, "192.168.1.10", "192.168.1.20", msgBytes))
+			file = appendPacket(file, etherTypeIPv6, newTestIPPacket(t, transport.NetworkTCP, "fe80::1", "fe80::2", msgBytes[:len(msgBytes)-1]))
+
+			reader, err := NewReader(bytes.NewReader(file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pkt, err := reader.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pkt.Timestamp.Equal(ts) || pkt.Network != transport.NetworkUDP || pkt.From.IP.String() != "192.168.1.10" {
+				t.Errorf("%s", pkt)
+			}
+			if pkt.Err != nil || pkt.Message == nil || !bytes.Equal(pkt.Message.Bytes(), msgBytes) {
+				t.Errorf("%s (%v)", pkt, pkt.Err)
+			}
+
+			// The truncated message is rejected in the strict mode, and is parsed leniently for the annotation.
+			pkt, err = reader.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pkt.Network != transport.NetworkTCP || pkt.To.IP.String() != "fe80::2" {
+				t.Errorf("%s", pkt)
+			}
+			if !errors.Is(pkt.Err, protocol.ErrTruncated) || pkt.Message == nil {
+				t.Errorf("%s (%v)", pkt, pkt.Err)
+			}
+
+			if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+				t.Errorf("%v != %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestPcapReader(t *testing.T) {
+	msgBytes := newTestMessageBytes(t, 1)
+
+	// Big-endian pcap file of the Linux cooked capture frames with the microsecond resolution
+	file := binary.BigEndian.AppendUint32(nil, pcapMagicMicroseconds)
+	file = binary.BigEndian.AppendUint16(file, pcapVersionMajor)
+	file = binary.BigEndian.AppendUint16(file, pcapVersionMinor)
+	file = append(file, make([]byte, 8)...)
+	file = binary.BigEndian.AppendUint32(file, pcapSnapLen)
+	file = binary.BigEndian.AppendUint32(file, uint32(LinkTypeLinuxSLL))
+
+	frame := make([]byte, sllHeaderSize-2)
+	frame = binary.BigEndian.AppendUint16(frame, etherTypeIPv4)
+	frame = append(frame, newTestIPPacket(t, transport.NetworkUDP, "192.168.1.10", transport.MulticastIPv4Address, msgBytes)...)
+	file = binary.BigEndian.AppendUint32(file, 1700000000)
+	file = binary.BigEndian.AppendUint32(file, 123456)
+	file = binary.BigEndian.AppendUint32(file, uint32(len(frame)))
+	file = binary.BigEndian.AppendUint32(file, uint32(len(frame)))
+	file = append(file, frame...)
+
+	reader, err := NewReader(bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt, err := reader.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pkt.Timestamp.Equal(time.Unix(1700000000, 123456000)) || pkt.To.IP.String() != transport.MulticastIPv4Address {
+		t.Errorf("%s", pkt)
+	}
+	if pkt.Err != nil || pkt.Message == nil {
+		t.Errorf("%s (%v)", pkt, pkt.Err)
+	}
+
+	// Truncated record
+	reader, err = NewReader(bytes.NewReader(file[:len(file)-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Next(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("%v != %v", err, ErrInvalid)
+	}
+
+	// Other ports
+	reader, err = NewReader(bytes.NewReader(file), WithReaderPorts(5353))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("%v != %v", err, io.EOF)
+	}
+
+	// Invalid magic
+	if _, err := NewReader(bytes.NewReader(make([]byte, pcapHeaderSize))); !errors.Is(err, ErrInvalid) {
+		t.Errorf("%v != %v", err, ErrInvalid)
+	}
+}
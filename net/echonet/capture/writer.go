@@ -0,0 +1,227 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+const (
+	pcapVersionMajor = 2
+	pcapVersionMinor = 4
+	pcapSnapLen      = math.MaxUint16
+	ipHopLimit       = 64
+	ipv4DontFragment = 0x4000
+	tcpFlagsPSHACK   = 0x18
+	tcpWindowSize    = math.MaxUint16
+)
+
+// Writer writes the ECHONET Lite messages into a pcap capture file as raw IP packets with the synthesized IP, UDP and TCP headers.
+// Writer implements transport.MessageObserver to capture the live traffic of the local nodes and controllers.
+type Writer struct {
+	sync.Mutex
+	w    io.Writer
+	err  error
+	seqs map[string]uint32
+	buf  []byte
+}
+
+// NewWriter returns a new capture writer after writing the pcap global header into the specified writer.
+func NewWriter(w io.Writer) (*Writer, error) {
+	writer := &Writer{
+		Mutex: sync.Mutex{},
+		w:     w,
+		err:   nil,
+		seqs:  map[string]uint32{},
+		buf:   make([]byte, 0, pcapRecordHeaderSize+ipv6HeaderSize+tcpHeaderSize+protocol.MaxMessageSize),
+	}
+	var header [pcapHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[0:4], pcapMagicNanoseconds)
+	binary.LittleEndian.PutUint16(header[4:6], pcapVersionMajor)
+	binary.LittleEndian.PutUint16(header[6:8], pcapVersionMinor)
+	binary.LittleEndian.PutUint32(header[16:20], pcapSnapLen)
+	binary.LittleEndian.PutUint32(header[20:24], uint32(LinkTypeRaw))
+	if _, err := w.Write(header[:]); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WritePacket writes the specified packet. The payload is encoded from the message when the packet has no payload.
+func (writer *Writer) WritePacket(pkt *Packet) error {
+	payload := pkt.Payload
+	if payload == nil && pkt.Message != nil {
+		payload = pkt.Message.Bytes()
+	}
+	return writer.write(pkt.Timestamp, pkt.Network, pkt.From, pkt.To, payload)
+}
+
+// MessageObserved writes the observed message. The first write error is returned by Err.
+func (writer *Writer) MessageObserved(msg *transport.ObservedMessage) {
+	err := writer.write(msg.Time, msg.Network, msg.From, msg.To, msg.Bytes)
+	if err == nil {
+		return
+	}
+	writer.Lock()
+	defer writer.Unlock()
+	if writer.err == nil {
+		writer.err = err
+	}
+}
+
+// Err returns the first error of the observed messages.
+func (writer *Writer) Err() error {
+	writer.Lock()
+	defer writer.Unlock()
+	return writer.err
+}
+
+// write writes a pcap record of the specified payload.
+func (writer *Writer) write(ts time.Time, network string, from *protocol.Address, to *protocol.Address, payload []byte) error {
+	writer.Lock()
+	defer writer.Unlock()
+
+	buf := writer.buf[:pcapRecordHeaderSize]
+	buf, err := writer.appendIPPacket(buf, network, from, to, payload)
+	if err != nil {
+		return err
+	}
+	size := uint32(len(buf) - pcapRecordHeaderSize)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(ts.Nanosecond()))
+	binary.LittleEndian.PutUint32(buf[8:12], size)
+	binary.LittleEndian.PutUint32(buf[12:16], size)
+	writer.buf = buf[:0]
+
+	_, err = writer.w.Write(buf)
+	return err
+}
+
+// ipOf returns the IP address of the specified address, or the unspecified address.
+func ipOf(addr *protocol.Address) net.IP {
+	if addr == nil || addr.IP == nil {
+		return net.IPv4zero
+	}
+	return addr.IP
+}
+
+// portOf returns the port of the specified address.
+func portOf(addr *protocol.Address) uint16 {
+	if addr == nil {
+		return 0
+	}
+	return uint16(addr.Port)
+}
+
+// appendIPPacket appends an IPv4 or IPv6 packet of the specified payload.
+// IPv6 is used when either address is an IPv6 address.
+func (writer *Writer) appendIPPacket(dst []byte, network string, from *protocol.Address, to *protocol.Address, payload []byte) ([]byte, error) {
+	srcIP, dstIP := ipOf(from).To4(), ipOf(to).To4()
+	isIPv6 := srcIP == nil || dstIP == nil
+	if isIPv6 {
+		srcIP, dstIP = ipOf(from).To16(), ipOf(to).To16()
+	}
+
+	proto := byte(ipProtocolUDP)
+	segmentHeaderSize := udpHeaderSize
+	if network == transport.NetworkTCP {
+		proto = ipProtocolTCP
+		segmentHeaderSize = tcpHeaderSize
+	}
+	segmentSize := segmentHeaderSize + len(payload)
+
+	ipHeaderSize := ipv4HeaderSize
+	if isIPv6 {
+		ipHeaderSize = ipv6HeaderSize
+	}
+	if pcapSnapLen < ipHeaderSize+segmentSize {
+		return nil, fmt.Errorf(errPacketTooLarge, ErrInvalid, ipHeaderSize+segmentSize, pcapSnapLen)
+	}
+
+	// IP header
+	ipOffset := len(dst)
+	dst = append(dst, make([]byte, ipHeaderSize)...)
+	ipHeader := dst[ipOffset:]
+	if isIPv6 {
+		binary.BigEndian.PutUint32(ipHeader[0:4], 6<<28)
+		binary.BigEndian.PutUint16(ipHeader[4:6], uint16(segmentSize))
+		ipHeader[6] = proto
+		ipHeader[7] = ipHopLimit
+		copy(ipHeader[8:24], srcIP)
+		copy(ipHeader[24:40], dstIP)
+	} else {
+		ipHeader[0] = 4<<4 | ipv4HeaderSize/4
+		binary.BigEndian.PutUint16(ipHeader[2:4], uint16(ipv4HeaderSize+segmentSize))
+		binary.BigEndian.PutUint16(ipHeader[6:8], ipv4DontFragment)
+		ipHeader[8] = ipHopLimit
+		ipHeader[9] = proto
+		copy(ipHeader[12:16], srcIP)
+		copy(ipHeader[16:20], dstIP)
+		binary.BigEndian.PutUint16(ipHeader[10:12], ^checksumOf(0, ipHeader))
+	}
+
+	// UDP or TCP header
+	segmentOffset := len(dst)
+	dst = append(dst, make([]byte, segmentHeaderSize)...)
+	dst = append(dst, payload...)
+	seg := dst[segmentOffset:]
+	binary.BigEndian.PutUint16(seg[0:2], portOf(from))
+	binary.BigEndian.PutUint16(seg[2:4], portOf(to))
+	checksumOffset := 6
+	if proto == ipProtocolTCP {
+		// The sequence numbers are continued for each flow not to be treated as retransmissions.
+		flow := fmt.Sprintf("%s-%d-%s-%d", srcIP, portOf(from), dstIP, portOf(to))
+		binary.BigEndian.PutUint32(seg[4:8], writer.seqs[flow])
+		writer.seqs[flow] += uint32(len(payload))
+		seg[12] = (tcpHeaderSize / 4) << 4
+		seg[13] = tcpFlagsPSHACK
+		binary.BigEndian.PutUint16(seg[14:16], tcpWindowSize)
+		checksumOffset = 16
+	} else {
+		binary.BigEndian.PutUint16(seg[4:6], uint16(segmentSize))
+	}
+
+	// Pseudo header checksum
+	var sum uint32
+	sum = checksumAdd(sum, srcIP)
+	sum = checksumAdd(sum, dstIP)
+	sum += uint32(proto) + uint32(segmentSize)
+	checksum := ^checksumOf(sum, seg)
+	if checksum == 0 && proto == ipProtocolUDP {
+		checksum = math.MaxUint16
+	}
+	binary.BigEndian.PutUint16(seg[checksumOffset:checksumOffset+2], checksum)
+
+	return dst, nil
+}
+
+// checksumAdd adds the specified bytes as the 16-bit words into the specified sum.
+func checksumAdd(sum uint32, b []byte) uint32 {
+	for n := 0; n+1 < len(b); n += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[n : n+2]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	return sum
+}
+
+// checksumOf returns the one's complement sum of the specified sum and bytes.
+func checksumOf(sum uint32, b []byte) uint16 {
+	sum = checksumAdd(sum, b)
+	for 0xFFFF < sum {
+		sum = (sum >> 16) + (sum & 0xFFFF)
+	}
+	return uint16(sum)
+}
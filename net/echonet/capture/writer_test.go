@@ -0,0 +1,139 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+func newTestMessageBytes(t *testing.T, tid uint) []byte {
+	t.Helper()
+	msg := protocol.NewMessage()
+	if err := msg.SetTID(tid); err != nil {
+		t.Fatal(err)
+	}
+	msg.SetSEOJ(0x05FF01)
+	msg.SetDEOJ(0x029101)
+	msg.SetESV(protocol.ESVReadRequest)
+	msg.AddProperty(protocol.NewPropertyWithCode(0x80))
+	return msg.Bytes()
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	ts := time.Unix(1700000000, 123456789)
+	pkts := []*Packet{
+		{
+			Timestamp: ts,
+			Network:   transport.NetworkUDP,
+			From:      &protocol.Address{IP: net.ParseIP("192.168.1.10"), Port: transport.Port, Zone: ""},
+			To:        &protocol.Address{IP: net.ParseIP(transport.MulticastIPv4Address), Port: transport.Port, Zone: ""},
+			Payload:   newTestMessageBytes(t, 1),
+			Message:   nil,
+			Err:       nil,
+		},
+		{
+			Timestamp: ts.Add(time.Millisecond),
+			Network:   transport.NetworkTCP,
+			From:      &protocol.Address{IP: net.ParseIP("fe80::1"), Port: 50000, Zone: ""},
+			To:        &protocol.Address{IP: net.ParseIP("fe80::2"), Port: transport.Port, Zone: ""},
+			Payload:   newTestMessageBytes(t, 2),
+			Message:   nil,
+			Err:       nil,
+		},
+		{
+			Timestamp: ts.Add(time.Second),
+			Network:   transport.NetworkUDP,
+			From:      &protocol.Address{IP: net.ParseIP("192.168.1.10"), Port: 5353, Zone: ""},
+			To:        &protocol.Address{IP: net.ParseIP("192.168.1.20"), Port: 5353, Zone: ""},
+			Payload:   []byte{0x00},
+			Message:   nil,
+			Err:       nil,
+		},
+		{
+			Timestamp: ts.Add(time.Minute),
+			Network:   transport.NetworkUDP,
+			From:      &protocol.Address{IP: net.ParseIP("192.168.1.20"), Port: transport.Port, Zone: ""},
+			To:        &protocol.Address{IP: net.ParseIP("192.168.1.10"), Port: transport.Port, Zone: ""},
+			Payload:   []byte{0x10, 0x81, 0x00},
+			Message:   nil,
+			Err:       nil,
+		},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkt := range pkts {
+		if err := writer.WritePacket(pkt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []*Packet{pkts[0], pkts[1], pkts[3]} {
+		pkt, err := reader.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pkt.Timestamp.Equal(expected.Timestamp) {
+			t.Errorf("%s != %s", pkt.Timestamp, expected.Timestamp)
+		}
+		if pkt.Network != expected.Network || pkt.From.String() != expected.From.String() || pkt.To.String() != expected.To.String() {
+			t.Errorf("%s != %s", pkt, expected)
+		}
+		if !bytes.Equal(pkt.Payload, expected.Payload) {
+			t.Errorf("%X != %X", pkt.Payload, expected.Payload)
+		}
+	}
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("%v != %v", err, io.EOF)
+	}
+}
+
+func TestWriterObserver(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var observer transport.MessageObserver = writer
+	observer.MessageObserved(&transport.ObservedMessage{
+		Time:      time.Now(),
+		Direction: transport.MessageSent,
+		Network:   transport.NetworkUDP,
+		From:      &protocol.Address{IP: net.ParseIP("192.168.1.10"), Port: transport.Port, Zone: ""},
+		To:        &protocol.Address{IP: net.ParseIP("192.168.1.20"), Port: transport.Port, Zone: ""},
+		Bytes:     newTestMessageBytes(t, 3),
+	})
+	if err := writer.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt, err := reader.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkt.Err != nil || pkt.Message == nil || pkt.Message.TID() != 3 {
+		t.Errorf("%s (%v)", pkt, pkt.Err)
+	}
+}
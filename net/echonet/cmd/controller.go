@@ -23,7 +23,7 @@ type Controller struct {
 // NewController returns a new controller.
 func NewController() *Controller {
 	c := &Controller{
-		Controller: echonet.NewController(),
+		Controller: echonet.NewController(echonet.WithControllerConfig(newNodeConfig())),
 	}
 	return c
 }
@@ -0,0 +1,169 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet"
+	"github.com/cybergarage/uecho-go/net/echonet/capture"
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	DecodePortParamStr    = "port"
+	DecodeLenientParamStr = "lenient"
+)
+
+func init() {
+	viper.SetDefault(DecodePortParamStr, []int{transport.Port})
+	decodeCmd.Flags().IntSlice(DecodePortParamStr, []int{transport.Port}, "UDP and TCP ports of the Echonet Lite messages")
+	viper.BindPFlag(DecodePortParamStr, decodeCmd.Flags().Lookup(DecodePortParamStr))
+
+	viper.SetDefault(DecodeLenientParamStr, false)
+	decodeCmd.Flags().Bool(DecodeLenientParamStr, false, "parse the malformed messages leniently without the errors")
+	viper.BindPFlag(DecodeLenientParamStr, decodeCmd.Flags().Lookup(DecodeLenientParamStr))
+
+	rootCmd.AddCommand(decodeCmd)
+}
+
+var decodeCmd = &cobra.Command{ // nolint:exhaustruct
+	Use:     "decode <capture-file>",
+	Short:   "Decode Echonet Lite messages in a pcap or pcapng capture file.",
+	Long:    "Decode Echonet Lite messages in a pcap or pcapng capture file. The messages are annotated with the object, service and property names, and the malformed messages are annotated with the parse errors.",
+	Example: "  uechoctl decode capture.pcap\n  uechoctl decode --port 3610,3611 --format json capture.pcapng",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := NewFormatFromString(viper.GetString(FormatParamStr))
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		opts := []capture.ReaderOption{}
+		if ports := viper.GetIntSlice(DecodePortParamStr); 0 < len(ports) {
+			opts = append(opts, capture.WithReaderPorts(ports...))
+		}
+		if viper.GetBool(DecodeLenientParamStr) {
+			opts = append(opts, capture.WithReaderParseMode(protocol.ParseModeLenient))
+		}
+
+		reader, err := capture.NewReader(file, opts...)
+		if err != nil {
+			return err
+		}
+
+		pkts := []*capture.Packet{}
+		for {
+			pkt, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			pkts = append(pkts, pkt)
+		}
+
+		table := NewCaptureTable(pkts...)
+		switch format {
+		case FormatJSON:
+			table.OutputJSON()
+		case FormatCSV:
+			table.OutputCSV()
+		default:
+			formatter := NewTableFormatter(table)
+			table = formatter.HideDuplicateColumns(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+			table.Output()
+		}
+
+		return nil
+	},
+}
+
+// NewCaptureTable returns a new table of the specified captured messages with a row for each property.
+func NewCaptureTable(pkts ...*capture.Packet) Table {
+	dissector := echonet.NewDissector()
+
+	cols := []string{
+		"time",
+		"network",
+		"from",
+		"to",
+		"tid",
+		"seoj",
+		"seoj_name",
+		"deoj",
+		"deoj_name",
+		"esv",
+		"property_code",
+		"property_name",
+		"property_data",
+		"property_value",
+		"error",
+	}
+
+	rows := [][]string{}
+	for _, pkt := range pkts {
+		errStr := ""
+		if pkt.Err != nil {
+			errStr = pkt.Err.Error()
+		}
+
+		row := []string{
+			pkt.Timestamp.Format(time.RFC3339Nano),
+			pkt.Network,
+			pkt.From.String(),
+			pkt.To.String(),
+		}
+
+		if pkt.Message == nil || pkt.Message.IsFormat2() {
+			row = append(row, "", "", "", "", "", "", "", "", fmt.Sprintf("%X", pkt.Payload), "", errStr)
+			rows = append(rows, row)
+			continue
+		}
+
+		msg := dissector.Dissect(pkt.Message)
+		row = append(row,
+			msg.TID,
+			msg.SEOJ.Code,
+			msg.SEOJ.Name,
+			msg.DEOJ.Code,
+			msg.DEOJ.Name,
+			msg.ESV.String(),
+		)
+
+		if len(msg.Properties) == 0 {
+			rows = append(rows, append(row, "", "", "", "", errStr))
+			continue
+		}
+		for _, prop := range msg.Properties {
+			propRow := append([]string{}, row...)
+			propRow = append(propRow,
+				prop.EPC.Code,
+				prop.EPC.Name,
+				prop.EDT,
+				strings.TrimSpace(prop.Value+" "+prop.Unit),
+				errStr,
+			)
+			rows = append(rows, propRow)
+		}
+	}
+
+	return NewTable(cols, rows)
+}
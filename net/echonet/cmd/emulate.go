@@ -0,0 +1,101 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	EmulateFileParamStr     = "file"
+	EmulateAutoPortParamStr = "auto-port"
+)
+
+func init() {
+	emulateCmd.Flags().StringP(EmulateFileParamStr, "f", "", "YAML file of the emulated devices")
+	viper.BindPFlag(EmulateFileParamStr, emulateCmd.Flags().Lookup(EmulateFileParamStr))
+
+	viper.SetDefault(EmulateAutoPortParamStr, false)
+	emulateCmd.Flags().Bool(EmulateAutoPortParamStr, false, "bind to the next available port when the standard port is in use")
+	viper.BindPFlag(EmulateAutoPortParamStr, emulateCmd.Flags().Lookup(EmulateAutoPortParamStr))
+	// The environment variable is named explicitly, because this file is initialized before the prefix is set in root.go.
+	viper.BindEnv(EmulateAutoPortParamStr, "UECHO_AUTO_PORT")
+
+	rootCmd.AddCommand(emulateCmd)
+}
+
+var emulateCmd = &cobra.Command{ // nolint:exhaustruct
+	Use:     "emulate [<class-code>|<object-code>]...",
+	Short:   "Emulate Echonet Lite devices.",
+	Long:    "Emulate Echonet Lite devices until interrupted. The devices are specified by class or object codes in hexadecimal format, or by a YAML file.",
+	Example: "  uechoctl emulate 0130 029101\n  uechoctl emulate --auto-port 028801\n  uechoctl emulate -f devices.yaml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		verbose := viper.GetBool(VerboseParamStr)
+		if verbose {
+			enableStdoutVerbose(true)
+		}
+
+		// Loads the emulated devices
+
+		var conf *EmulatorConfig
+		filename := viper.GetString(EmulateFileParamStr)
+		switch {
+		case 0 < len(filename):
+			var err error
+			conf, err = NewEmulatorConfigFromFile(filename)
+			if err != nil {
+				return err
+			}
+		case 0 < len(args):
+			conf = NewEmulatorConfig(args...)
+		default:
+			return fmt.Errorf("no devices: specify class codes or a YAML file")
+		}
+
+		nodeConf := newNodeConfig()
+		nodeConf.TransportConfig().SetAutoPortBindingEnabled(viper.GetBool(EmulateAutoPortParamStr))
+
+		emu, err := NewEmulator(conf, nodeConf)
+		if err != nil {
+			return err
+		}
+
+		// Starts the emulator
+
+		err = emu.Start()
+		if err != nil {
+			return err
+		}
+
+		outputf("%s\n", net.JoinHostPort(emu.Address(), strconv.Itoa(emu.Port())))
+		for _, dev := range emu.Devices() {
+			outputf("%s %s\n", dev.Code(), dev.ClassName())
+		}
+
+		// Runs until interrupted
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+
+		// Stops the emulator
+
+		err = emu.Stop()
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
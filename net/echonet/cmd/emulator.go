@@ -0,0 +1,120 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cybergarage/uecho-go/net/echonet"
+	"github.com/cybergarage/uecho-go/net/echonet/encoding"
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+const (
+	emulatorClassCodeSize  = 2
+	emulatorObjectCodeSize = 3
+)
+
+// Emulator represents an Echonet Lite node which emulates standard devices.
+type Emulator struct {
+	echonet.LocalNode
+}
+
+// NewEmulator returns a new emulator with the specified configurations.
+func NewEmulator(conf *EmulatorConfig, nodeConf echonet.Config) (*Emulator, error) {
+	manufacturerCode, err := conf.ManufacturerCode()
+	if err != nil {
+		return nil, err
+	}
+
+	emu := &Emulator{
+		LocalNode: nil,
+	}
+
+	emu.LocalNode = echonet.NewLocalNode(
+		echonet.WithLocalNodeConfig(nodeConf),
+		echonet.WithLocalNodeManufacturerCode(manufacturerCode),
+		echonet.WithLocalNodeListener(emu),
+	)
+
+	for _, devConf := range conf.Devices {
+		dev, err := emu.newDevice(devConf)
+		if err != nil {
+			return nil, err
+		}
+		if err := emu.AddDevice(dev); err != nil {
+			return nil, err
+		}
+	}
+
+	return emu, nil
+}
+
+// newDevice returns a new standard device with the specified configuration.
+// The instance code of the device is assigned by the node when only the class code is specified.
+func (emu *Emulator) newDevice(devConf EmulatorDeviceConfig) (echonet.Device, error) {
+	codeBytes, err := hexStringToByte(devConf.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	var objCode echonet.ObjectCode
+	switch len(codeBytes) {
+	case emulatorClassCodeSize:
+		objCode = echonet.ObjectCode(encoding.ByteToInteger(append(codeBytes, echonet.ObjectInstanceCodeAll)))
+	case emulatorObjectCodeSize:
+		objCode = echonet.ObjectCode(encoding.ByteToInteger(codeBytes))
+	default:
+		return nil, fmt.Errorf("invalid class or object code: %s", devConf.Code)
+	}
+
+	dev, err := echonet.NewDevice(
+		echonet.WithDeviceCode(objCode),
+		echonet.WithDeviceRequestHandler(emu.onRequest),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for propCodeStr, propDataStr := range devConf.Properties {
+		propCode, err := hexStringToInt(propCodeStr)
+		if err != nil {
+			return nil, err
+		}
+		propData, err := hexStringToByte(propDataStr)
+		if err != nil {
+			return nil, err
+		}
+		if err := dev.SetPropertyData(echonet.PropertyCode(propCode), propData); err != nil {
+			return nil, fmt.Errorf("%s: %w", dev.Code(), err)
+		}
+	}
+
+	return dev, nil
+}
+
+// onRequest stores the requested data into the property when a write request is received.
+func (emu *Emulator) onRequest(ctx echonet.RequestContext, obj echonet.Object, esv protocol.ESV, prop protocol.Property) error {
+	if !esv.IsWriteRequest() {
+		return nil
+	}
+	outputf("%s:%d %s %02X : %X -> %X\n", ctx.SourceAddress(), ctx.SourcePort(), obj.Code(), prop.Code(), lookupEmulatorPropertyData(obj, prop.Code()), prop.Data())
+	return obj.SetPropertyData(prop.Code(), prop.Data())
+}
+
+// lookupEmulatorPropertyData returns the current data of the specified property.
+func lookupEmulatorPropertyData(obj echonet.Object, code echonet.PropertyCode) []byte {
+	data, err := obj.LookupPropertyData(code)
+	if err != nil {
+		return []byte{}
+	}
+	return data
+}
+
+// OnMessage outputs the received message.
+func (emu *Emulator) OnMessage(msg *protocol.Message) error {
+	outputf("%s : %s\n", msg.From.String(), msg.String())
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cybergarage/uecho-go/net/echonet"
+	"github.com/spf13/viper"
+)
+
+// EmulatorConfig represents a configuration of the emulated devices.
+//
+// The configuration file is a YAML file as the following example. All codes and values must be specified in hexadecimal format.
+//
+//	manufacturer: "FFFFFF"
+//	devices:
+//	  - code: "0130"      # Home air conditioner (the instance code is assigned automatically)
+//	    properties:
+//	      "B0": "41"      # Operation mode setting
+//	  - code: "029101"    # Mono functional lighting
+type EmulatorConfig struct {
+	Manufacturer string                 `mapstructure:"manufacturer"`
+	Devices      []EmulatorDeviceConfig `mapstructure:"devices"`
+}
+
+// EmulatorDeviceConfig represents a configuration of an emulated device.
+type EmulatorDeviceConfig struct {
+	Code       string            `mapstructure:"code"`
+	Properties map[string]string `mapstructure:"properties"`
+}
+
+// NewEmulatorConfig returns a new emulator configuration for the specified class or object codes.
+func NewEmulatorConfig(codes ...string) *EmulatorConfig {
+	conf := &EmulatorConfig{
+		Manufacturer: "",
+		Devices:      make([]EmulatorDeviceConfig, 0, len(codes)),
+	}
+	for _, code := range codes {
+		conf.Devices = append(conf.Devices, EmulatorDeviceConfig{
+			Code:       code,
+			Properties: map[string]string{},
+		})
+	}
+	return conf
+}
+
+// NewEmulatorConfigFromFile returns a new emulator configuration from the specified YAML file.
+func NewEmulatorConfigFromFile(filename string) (*EmulatorConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(filename)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	conf := NewEmulatorConfig()
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, err
+	}
+	if len(conf.Devices) == 0 {
+		return nil, fmt.Errorf("no devices: %s", filename)
+	}
+	return conf, nil
+}
+
+// ManufacturerCode returns the manufacturer code of the configuration.
+func (conf *EmulatorConfig) ManufacturerCode() (uint, error) {
+	if len(conf.Manufacturer) == 0 {
+		return echonet.DeviceManufacturerExperimental, nil
+	}
+	code, err := hexStringToInt(conf.Manufacturer)
+	if err != nil {
+		return 0, err
+	}
+	return uint(code), nil
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/cybergarage/go-logger/log"
+	"github.com/cybergarage/uecho-go/net/echonet"
+	"github.com/spf13/viper"
 )
 
 func outputf(format string, args ...any) {
@@ -20,12 +22,22 @@ func errorf(format string, args ...any) {
 	fmt.Printf(format, args...)
 }
 
+// enableStdoutVerbose outputs the dissected message frames of the socket trace logs to the standard output.
 func enableStdoutVerbose(flag bool) {
 	if flag {
-		log.SetSharedLogger(log.NewStdoutLogger(log.LevelInfo))
+		log.SetSharedLogger(log.NewStdoutLogger(log.LevelTrace))
 	} else {
 		log.SetSharedLogger(nil)
 	}
+	echonet.SetDissectedLogEnabled(flag)
+}
+
+// newNodeConfig returns a new node configuration with the interface flags.
+func newNodeConfig() echonet.Config {
+	return echonet.NewDefaultConfig(
+		echonet.WithConfigInterfaceIncludes(viper.GetStringSlice(InterfaceParamStr)...),
+		echonet.WithConfigInterfaceExcludes(viper.GetStringSlice(ExcludeInterfaceParamStr)...),
+	)
 }
 
 func hexStringToByte(hexStr string) ([]byte, error) {
@@ -0,0 +1,64 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestVerboseDissectedOutput(t *testing.T) {
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+
+	outCh := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		outCh <- buf.String()
+	}()
+
+	rootCmd.SetArgs([]string{"scan", "--" + VerboseParamStr, "--" + FormatParamStr, FormatJSONStr})
+	err = rootCmd.Execute()
+
+	enableStdoutVerbose(false)
+	rootCmd.PersistentFlags().Set(VerboseParamStr, "false")
+	os.Stdout = stdout
+	w.Close()
+	out := <-outCh
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The search request to the node profile object is output as the dissected frame in the socket trace logs.
+
+	for _, name := range []string{"0EF001 (Node profile)", "62 (Get)"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("%s is not found in the verbose output:\n%s", name, out)
+		}
+	}
+}
+
+func TestEnvironmentVariables(t *testing.T) {
+	t.Setenv("UECHO_EXCLUDE_INTERFACE", "wg*")
+	if excludes := viper.GetStringSlice(ExcludeInterfaceParamStr); len(excludes) != 1 || excludes[0] != "wg*" {
+		t.Errorf("%v != %v", excludes, []string{"wg*"})
+	}
+
+	t.Setenv("UECHO_AUTO_PORT", "true")
+	if !viper.GetBool(EmulateAutoPortParamStr) {
+		t.Errorf("%s is not enabled", EmulateAutoPortParamStr)
+	}
+}
@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cybergarage/uecho-go/net/echonet"
 )
@@ -19,14 +20,17 @@ type MessageFormatter interface {
 }
 
 // defaultMessageFormatter provides a default implementation of MessageFormatter.
+// The codes are annotated with the object, service and property names, and the property data are decoded by the dissector.
 type defaultMessageFormatter struct {
-	msgs []echonet.Message
+	msgs      []echonet.Message
+	dissector *echonet.Dissector
 }
 
 // NewMessageFormatter returns a new default message formatter.
 func NewMessageFormatter(msgs ...echonet.Message) MessageFormatter {
 	return &defaultMessageFormatter{
-		msgs: msgs,
+		msgs:      msgs,
+		dissector: echonet.NewDissector(),
 	}
 }
 
@@ -52,29 +56,36 @@ func (f *defaultMessageFormatter) Columns() []string {
 			fmt.Sprintf("EPC%d", n),
 			fmt.Sprintf("PDC%d", n),
 			fmt.Sprintf("EDT%d", n),
+			fmt.Sprintf("VAL%d", n),
 		)
 	}
 	return columns
 }
 
+// Rows returns the data rows for the messages.
 func (f *defaultMessageFormatter) Rows() [][]string {
 	rows := [][]string{}
 	for _, msg := range f.msgs {
-		ehd := msg.EHD()
+		dissected := f.dissector.Dissect(msg.ToProtocol())
+		if msg.IsFormat2() {
+			rows = append(rows, []string{dissected.EHD, dissected.TID, "", "", "", ""})
+			continue
+		}
 		strs := []string{
-			fmt.Sprintf("%02X%02X", ehd[0], ehd[1]),
-			fmt.Sprintf("%04X", msg.TID()),
-			msg.SEOJ().String(),
-			msg.DEOJ().String(),
-			msg.ESV().String(),
-			fmt.Sprintf("%02X", msg.OPC()),
+			dissected.EHD,
+			dissected.TID,
+			dissected.SEOJ.String(),
+			dissected.DEOJ.String(),
+			dissected.ESV.String(),
+			fmt.Sprintf("%02X", dissected.OPC),
 		}
-		for _, prop := range msg.Properties() {
+		for _, prop := range dissected.Properties {
 			strs = append(
 				strs,
-				fmt.Sprintf("%02X", prop.Code()),
-				fmt.Sprintf("%02X", prop.Size()),
-				fmt.Sprintf("%X", prop.Data()),
+				prop.EPC.String(),
+				fmt.Sprintf("%02X", prop.PDC),
+				prop.EDT,
+				strings.TrimSpace(prop.Value+" "+prop.Unit),
 			)
 		}
 		rows = append(rows, strs)
@@ -0,0 +1,51 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet"
+)
+
+func TestMessageFormatter(t *testing.T) {
+	msg := echonet.NewMessage(
+		echonet.WithMessageDEOJ(0x029101),
+		echonet.WithMessageESV(echonet.ESVReadRequest),
+		echonet.WithMessageProperties(
+			echonet.NewProperty(
+				echonet.WithPropertyCode(0x80),
+				echonet.WithPropertyData([]byte{0x30}),
+			),
+		),
+	)
+
+	formatter := NewMessageFormatter(msg)
+	cols := formatter.Columns()
+	rows := formatter.Rows()
+	if len(rows) != 1 {
+		t.Fatalf("%d != %d", len(rows), 1)
+	}
+	if len(rows[0]) != len(cols) {
+		t.Errorf("%d != %d", len(rows[0]), len(cols))
+	}
+
+	expected := map[string]string{
+		"DEOJ": "029101 (Mono functional lighting)",
+		"ESV":  "62 (Get)",
+		"EPC0": "80 (Operation status)",
+		"EDT0": "30",
+		"VAL0": "ON",
+	}
+	for n, col := range cols {
+		value, ok := expected[col]
+		if !ok || len(rows[0]) <= n {
+			continue
+		}
+		if rows[0][n] != value {
+			t.Errorf("%s : %s != %s", col, rows[0][n], value)
+		}
+	}
+}
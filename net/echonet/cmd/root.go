@@ -15,7 +15,9 @@ import (
 )
 
 var (
-	VerboseParamStr = "verbose"
+	VerboseParamStr          = "verbose"
+	InterfaceParamStr        = "interface"
+	ExcludeInterfaceParamStr = "exclude-interface"
 )
 
 // rootCmd represents the base command for the uechoctl CLI tool.
@@ -38,6 +40,8 @@ func Execute() error {
 
 func init() {
 	viper.SetEnvPrefix("UECHO")
+	// The hyphens of the flag names are replaced with the underscores to be set in the shells, such as UECHO_EXCLUDE_INTERFACE.
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 
 	viper.SetDefault(FormatParamStr, FormatTableStr)
 	rootCmd.PersistentFlags().String(FormatParamStr, FormatTableStr, fmt.Sprintf("output format: %s", strings.Join(allSupportedFormats(), "|")))
@@ -48,4 +52,12 @@ func init() {
 	rootCmd.PersistentFlags().Bool((VerboseParamStr), false, "enable verbose output")
 	viper.BindPFlag(VerboseParamStr, rootCmd.PersistentFlags().Lookup(VerboseParamStr))
 	viper.BindEnv(VerboseParamStr) // UECHO_VERBOSE
+
+	rootCmd.PersistentFlags().StringSlice(InterfaceParamStr, []string{}, "interface names, glob patterns, CIDRs or addresses to bind")
+	viper.BindPFlag(InterfaceParamStr, rootCmd.PersistentFlags().Lookup(InterfaceParamStr))
+	viper.BindEnv(InterfaceParamStr) // UECHO_INTERFACE
+
+	rootCmd.PersistentFlags().StringSlice(ExcludeInterfaceParamStr, []string{}, "interface names, glob patterns, CIDRs or addresses not to bind")
+	viper.BindPFlag(ExcludeInterfaceParamStr, rootCmd.PersistentFlags().Lookup(ExcludeInterfaceParamStr))
+	viper.BindEnv(ExcludeInterfaceParamStr) // UECHO_EXCLUDE_INTERFACE
 }
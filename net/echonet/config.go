@@ -7,11 +7,34 @@ package echonet
 import (
 	"time"
 
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 	"github.com/cybergarage/uecho-go/net/echonet/transport"
 )
 
 type transportConfig = transport.Config
 
+// ParseMode represents a mode to parse the received messages.
+type ParseMode = protocol.ParseMode
+
+const (
+	// ParseModeStrict rejects the truncated or inconsistent received messages.
+	ParseModeStrict = protocol.ParseModeStrict
+	// ParseModeLenient accepts the malformed received messages as far as possible for interoperability debugging.
+	ParseModeLenient = protocol.ParseModeLenient
+)
+
+// AddressFamily represents the IP address families which the node is bound to.
+type AddressFamily = transport.AddressFamily
+
+const (
+	// AddressFamilyIPv4 binds the node to the IPv4 addresses.
+	AddressFamilyIPv4 = transport.AddressFamilyIPv4
+	// AddressFamilyIPv6 binds the node to the IPv6 link-local and global addresses.
+	AddressFamilyIPv6 = transport.AddressFamilyIPv6
+	// AddressFamilyDualStack binds the node to both the IPv4 and IPv6 addresses.
+	AddressFamilyDualStack = transport.AddressFamilyDualStack
+)
+
 // Config is an interface for Echonet configuration.
 type Config interface {
 	configInternal
@@ -21,7 +44,27 @@ type configInternal interface {
 	TransportConfig() *transportConfig
 	SelfMessageEnabled() bool
 	TCPEnabled() bool
+	TCPIdleTimeout() time.Duration
+	TCPKeepAlive() time.Duration
+	ParseMode() ParseMode
+	AddressFamily() AddressFamily
+	InterfaceIncludes() []string
+	InterfaceExcludes() []string
+	InterfaceWatchInterval() time.Duration
+	MulticastIPv4Address() string
+	MulticastIPv6Address() string
+	MulticastPort() int
+	MulticastTTL() int
+	MulticastLoopbackEnabled() bool
 	RequestTimeout() time.Duration
+	IdentificationSource() IdentificationSource
+	NodeID() []byte
+	IdentificationFile() string
+	InstanceCodePolicy() InstanceCodePolicy
+	MaxResponseDeferral() time.Duration
+	SourceRateLimit() RateLimit
+	GlobalRateLimit() RateLimit
+	RateLimitAction() RateLimitAction
 }
 
 // ConfigOption is a function that configures a configuration.
@@ -31,6 +74,14 @@ type ConfigOption func(*config)
 type config struct {
 	*transportConfig
 	selfMsgEnabled bool
+	idSource       IdentificationSource
+	nodeID         []byte
+	idFile         string
+	instancePolicy InstanceCodePolicy
+	maxDeferral    time.Duration
+	sourceLimit    RateLimit
+	globalLimit    RateLimit
+	limitAction    RateLimitAction
 }
 
 // WithConfigTCPEnabled sets the specified TCP option to the config.
@@ -40,6 +91,149 @@ func WithConfigTCPEnabled(flag bool) ConfigOption {
 	}
 }
 
+// WithConfigTCPIdleTimeout sets the specified timeout to close the idle persistent TCP connections. The connections are closed after each message when the timeout is zero.
+func WithConfigTCPIdleTimeout(d time.Duration) ConfigOption {
+	return func(conf *config) {
+		conf.SetTCPIdleTimeout(d)
+	}
+}
+
+// WithConfigTCPKeepAlive sets the specified keepalive period of the TCP connections. The keepalive is disabled when the period is zero.
+func WithConfigTCPKeepAlive(d time.Duration) ConfigOption {
+	return func(conf *config) {
+		conf.SetTCPKeepAlive(d)
+	}
+}
+
+// WithConfigParseMode sets the specified mode to parse the received messages. The mode is ParseModeStrict by default.
+func WithConfigParseMode(mode ParseMode) ConfigOption {
+	return func(conf *config) {
+		conf.SetParseMode(mode)
+	}
+}
+
+// WithConfigAddressFamily sets the specified address families to bind the node. The family is AddressFamilyIPv4 by default.
+func WithConfigAddressFamily(family AddressFamily) ConfigOption {
+	return func(conf *config) {
+		conf.SetAddressFamily(family)
+	}
+}
+
+// WithConfigInterfaceIncludes sets the specified rules to include the interfaces and addresses to bind the node.
+// Each rule is an interface name, a glob pattern of the interface names such as "eth*", a CIDR such as "192.168.0.0/24", or an explicit IP address.
+func WithConfigInterfaceIncludes(rules ...string) ConfigOption {
+	return func(conf *config) {
+		conf.SetInterfaceIncludes(rules...)
+	}
+}
+
+// WithConfigInterfaceExcludes sets the specified rules to exclude the interfaces and addresses to bind the node.
+// The rules are the same as WithConfigInterfaceIncludes, and the excluded interfaces and addresses are never bound even if they are included.
+func WithConfigInterfaceExcludes(rules ...string) ConfigOption {
+	return func(conf *config) {
+		conf.SetInterfaceExcludes(rules...)
+	}
+}
+
+// WithConfigInterfaceWatchInterval sets the specified interval to poll the interface changes. The node is rebound to the changed interfaces and addresses automatically.
+// The interfaces are not watched when the interval is zero, and the interval is 5 seconds by default.
+func WithConfigInterfaceWatchInterval(d time.Duration) ConfigOption {
+	return func(conf *config) {
+		conf.SetInterfaceWatchInterval(d)
+	}
+}
+
+// WithConfigMulticastIPv4Address sets the specified IPv4 multicast group address to join and announce. The address is 224.0.23.0 by default.
+func WithConfigMulticastIPv4Address(addr string) ConfigOption {
+	return func(conf *config) {
+		conf.SetMulticastIPv4Address(addr)
+	}
+}
+
+// WithConfigMulticastIPv6Address sets the specified IPv6 multicast group address to join and announce. The address is ff02::1 by default.
+func WithConfigMulticastIPv6Address(addr string) ConfigOption {
+	return func(conf *config) {
+		conf.SetMulticastIPv6Address(addr)
+	}
+}
+
+// WithConfigMulticastPort sets the specified port of the multicast group to join and announce. The port is 3610 by default.
+func WithConfigMulticastPort(port int) ConfigOption {
+	return func(conf *config) {
+		conf.SetMulticastPort(port)
+	}
+}
+
+// WithConfigMulticastTTL sets the specified TTL (IPv6 hop limit) of the announced multicast messages. The TTL is 1 by default.
+func WithConfigMulticastTTL(ttl int) ConfigOption {
+	return func(conf *config) {
+		conf.SetMulticastTTL(ttl)
+	}
+}
+
+// WithConfigMulticastLoopbackEnabled sets a flag to deliver the announced multicast messages to the nodes on the same host. The loopback is enabled by default.
+func WithConfigMulticastLoopbackEnabled(flag bool) ConfigOption {
+	return func(conf *config) {
+		conf.SetMulticastLoopbackEnabled(flag)
+	}
+}
+
+// WithConfigIdentificationSource sets the specified source of the node ID to the config.
+func WithConfigIdentificationSource(src IdentificationSource) ConfigOption {
+	return func(conf *config) {
+		conf.SetIdentificationSource(src)
+	}
+}
+
+// WithConfigNodeID sets the specified node ID to the config, and sets the source to IdentificationSourceUser.
+func WithConfigNodeID(nodeID []byte) ConfigOption {
+	return func(conf *config) {
+		conf.SetNodeID(nodeID)
+	}
+}
+
+// WithConfigIdentificationFile sets the specified file to persist the node ID across restarts.
+func WithConfigIdentificationFile(filename string) ConfigOption {
+	return func(conf *config) {
+		conf.SetIdentificationFile(filename)
+	}
+}
+
+// WithConfigInstanceCodePolicy sets the specified policy for the instance code collision of the added devices.
+func WithConfigInstanceCodePolicy(policy InstanceCodePolicy) ConfigOption {
+	return func(conf *config) {
+		conf.SetInstanceCodePolicy(policy)
+	}
+}
+
+// WithConfigMaxResponseDeferral sets the specified maximum time to defer the responses of the request messages.
+func WithConfigMaxResponseDeferral(d time.Duration) ConfigOption {
+	return func(conf *config) {
+		conf.SetMaxResponseDeferral(d)
+	}
+}
+
+// WithConfigSourceRateLimit sets the specified token bucket rate limit for the request messages from each source address.
+func WithConfigSourceRateLimit(rate float64, burst int) ConfigOption {
+	return func(conf *config) {
+		conf.SetSourceRateLimit(RateLimit{Rate: rate, Burst: burst})
+	}
+}
+
+// WithConfigGlobalRateLimit sets the specified token bucket rate limit for the request messages from all sources.
+func WithConfigGlobalRateLimit(rate float64, burst int) ConfigOption {
+	return func(conf *config) {
+		conf.SetGlobalRateLimit(RateLimit{Rate: rate, Burst: burst})
+	}
+}
+
+// WithConfigRateLimitAction sets the specified action for the request messages which exceed the rate limits.
+func WithConfigRateLimitAction(action RateLimitAction) ConfigOption {
+	return func(conf *config) {
+		conf.SetRateLimitAction(action)
+	}
+}
+
 // NewDefaultConfig returns a new default configuration.
 func NewDefaultConfig(opts ...ConfigOption) Config {
 	return newDefaultConfig(opts...)
@@ -49,6 +243,14 @@ func newDefaultConfig(opts ...ConfigOption) *config {
 	conf := &config{
 		selfMsgEnabled:  true,
 		transportConfig: transport.NewDefaultConfig(),
+		idSource:        IdentificationSourceMACAddress,
+		nodeID:          nil,
+		idFile:          "",
+		instancePolicy:  InstanceCodePolicyAllocate,
+		maxDeferral:     DefaultMaxResponseDeferral,
+		sourceLimit:     RateLimit{Rate: 0, Burst: 0},
+		globalLimit:     RateLimit{Rate: 0, Burst: 0},
+		limitAction:     RateLimitActionDrop,
 	}
 	for _, opt := range opts {
 		opt(conf)
@@ -77,3 +279,93 @@ func (conf *config) SetSelfMessageEnabled(flag bool) Config {
 func (conf *config) SelfMessageEnabled() bool {
 	return conf.selfMsgEnabled
 }
+
+// SetIdentificationSource sets a source of the node ID.
+func (conf *config) SetIdentificationSource(src IdentificationSource) Config {
+	conf.idSource = src
+	return conf
+}
+
+// IdentificationSource returns the source of the node ID.
+func (conf *config) IdentificationSource() IdentificationSource {
+	return conf.idSource
+}
+
+// SetNodeID sets a node ID supplied by the caller, and sets the source to IdentificationSourceUser.
+func (conf *config) SetNodeID(nodeID []byte) Config {
+	conf.idSource = IdentificationSourceUser
+	conf.nodeID = make([]byte, len(nodeID))
+	copy(conf.nodeID, nodeID)
+	return conf
+}
+
+// NodeID returns the node ID supplied by the caller.
+func (conf *config) NodeID() []byte {
+	return conf.nodeID
+}
+
+// SetIdentificationFile sets a file to persist the node ID across restarts.
+func (conf *config) SetIdentificationFile(filename string) Config {
+	conf.idFile = filename
+	return conf
+}
+
+// IdentificationFile returns the file to persist the node ID.
+func (conf *config) IdentificationFile() string {
+	return conf.idFile
+}
+
+// SetInstanceCodePolicy sets a policy for the instance code collision of the added devices.
+func (conf *config) SetInstanceCodePolicy(policy InstanceCodePolicy) Config {
+	conf.instancePolicy = policy
+	return conf
+}
+
+// InstanceCodePolicy returns the policy for the instance code collision of the added devices.
+func (conf *config) InstanceCodePolicy() InstanceCodePolicy {
+	return conf.instancePolicy
+}
+
+// SetMaxResponseDeferral sets a maximum time to defer the responses of the request messages.
+func (conf *config) SetMaxResponseDeferral(d time.Duration) Config {
+	conf.maxDeferral = d
+	return conf
+}
+
+// MaxResponseDeferral returns the maximum time to defer the responses of the request messages.
+func (conf *config) MaxResponseDeferral() time.Duration {
+	return conf.maxDeferral
+}
+
+// SetSourceRateLimit sets a rate limit for the request messages from each source address.
+func (conf *config) SetSourceRateLimit(limit RateLimit) Config {
+	conf.sourceLimit = limit
+	return conf
+}
+
+// SourceRateLimit returns the rate limit for the request messages from each source address.
+func (conf *config) SourceRateLimit() RateLimit {
+	return conf.sourceLimit
+}
+
+// SetGlobalRateLimit sets a rate limit for the request messages from all sources.
+func (conf *config) SetGlobalRateLimit(limit RateLimit) Config {
+	conf.globalLimit = limit
+	return conf
+}
+
+// GlobalRateLimit returns the rate limit for the request messages from all sources.
+func (conf *config) GlobalRateLimit() RateLimit {
+	return conf.globalLimit
+}
+
+// SetRateLimitAction sets an action for the request messages which exceed the rate limits.
+func (conf *config) SetRateLimitAction(action RateLimitAction) Config {
+	conf.limitAction = action
+	return conf
+}
+
+// RateLimitAction returns the action for the request messages which exceed the rate limits.
+func (conf *config) RateLimitAction() RateLimitAction {
+	return conf.limitAction
+}
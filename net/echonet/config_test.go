@@ -18,3 +18,31 @@ func newTestDefaultConfig() Config {
 func TestNewDefaultConfig(t *testing.T) {
 	NewDefaultConfig()
 }
+
+func TestConfigMulticastOptions(t *testing.T) {
+	conf := NewDefaultConfig(
+		WithConfigMulticastIPv4Address("239.255.36.10"),
+		WithConfigMulticastIPv6Address("ff05::3610"),
+		WithConfigMulticastPort(13610),
+		WithConfigMulticastTTL(4),
+		WithConfigMulticastLoopbackEnabled(false),
+	)
+	if conf.MulticastIPv4Address() != "239.255.36.10" {
+		t.Errorf("%s != %s", conf.MulticastIPv4Address(), "239.255.36.10")
+	}
+	if conf.MulticastIPv6Address() != "ff05::3610" {
+		t.Errorf("%s != %s", conf.MulticastIPv6Address(), "ff05::3610")
+	}
+	if conf.MulticastPort() != 13610 {
+		t.Errorf("%d != %d", conf.MulticastPort(), 13610)
+	}
+	if conf.MulticastTTL() != 4 {
+		t.Errorf("%d != %d", conf.MulticastTTL(), 4)
+	}
+	if conf.MulticastLoopbackEnabled() {
+		t.Errorf("multicast loopback is enabled")
+	}
+	if err := conf.TransportConfig().ValidateMulticastConfig(); err != nil {
+		t.Error(err)
+	}
+}
@@ -6,6 +6,8 @@ package echonet
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
@@ -29,16 +31,27 @@ type Controller interface {
 	SetListener(ControllerListener)
 	// Addresses returns the local addresses that this controller is bound to.
 	Addresses() []string
+	// InterfaceAddresses returns the local addresses that this controller is bound to for each interface name.
+	InterfaceAddresses() map[string][]string
 	// Search searches echonet nodes until the context is done.
 	Search(ctx context.Context) error
 	// Nodes returns discovered nodes.
 	Nodes() []Node
 	// LookupNode returns a node which has the specified address.
 	LookupNode(addr string) (Node, bool)
-	// SendMessage sends a message to the node.
+	// SendMessage sends a message to the node. SendMessage returns an error without sending when the message is invalid.
 	SendMessage(ctx context.Context, dstNode Node, msg Message) error
-	// PostMessage posts a message to the node, and wait the response message.
+	// PostMessage posts a request message to the node, and wait the response message.
+	// PostMessage returns an error without sending when the message is invalid or the ESV does not require any response.
 	PostMessage(ctx context.Context, dstNode Node, msg Message) (Message, error)
+	// SetInterfaceListener sets a listener to receive the interface changes of the controller.
+	SetInterfaceListener(InterfaceListener)
+	// AddMessageObserver adds an observer of the message frames which are sent and received by the controller.
+	AddMessageObserver(MessageObserver)
+	// RemoveMessageObserver removes the specified observer.
+	RemoveMessageObserver(MessageObserver)
+	// Metrics returns a snapshot of the traffic counters, the request latencies and the error counters of the controller.
+	Metrics() *Metrics
 	// Start starts the controller.
 	Start() error
 	// Stop stops the controller.
@@ -47,6 +60,7 @@ type Controller interface {
 
 type controller struct {
 	*localNode
+	nodesMutex         *sync.Mutex
 	foundNodes         []Node
 	controllerListener ControllerListener
 }
@@ -58,6 +72,20 @@ func WithControllerConfig(cfg Config) ControllerOption {
 	}
 }
 
+// WithControllerTransport sets the specified transport to the controller instead of the default transport over the real sockets.
+func WithControllerTransport(t Transport) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.SetTransport(t)
+	}
+}
+
+// WithControllerInterfaceListener sets the specified listener to receive the interface changes of the controller.
+func WithControllerInterfaceListener(l InterfaceListener) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.SetInterfaceListener(l)
+	}
+}
+
 // NewController returns a new controller.
 func NewController(opts ...ControllerOption) Controller {
 	return newController(opts...)
@@ -66,6 +94,7 @@ func NewController(opts ...ControllerOption) Controller {
 func newController(opts ...ControllerOption) *controller {
 	ctrl := &controller{
 		localNode:          newLocalNode(),
+		nodesMutex:         new(sync.Mutex),
 		foundNodes:         make([]Node, 0),
 		controllerListener: nil,
 	}
@@ -83,7 +112,9 @@ func (ctrl *controller) SetListener(l ControllerListener) {
 
 // Nodes returns found nodes.
 func (ctrl *controller) Nodes() []Node {
-	return ctrl.foundNodes
+	ctrl.nodesMutex.Lock()
+	defer ctrl.nodesMutex.Unlock()
+	return slices.Clone(ctrl.foundNodes)
 }
 
 // LookupNode returns a node which has the specified address.
@@ -144,6 +175,8 @@ func (ctrl *controller) Search(ctx context.Context) error {
 
 // Clear clears all found nodes.
 func (ctrl *controller) Clear() error {
+	ctrl.nodesMutex.Lock()
+	defer ctrl.nodesMutex.Unlock()
 	ctrl.foundNodes = make([]Node, 0)
 	return nil
 }
@@ -16,24 +16,10 @@ const (
 
 func (ctrl *controller) isSelfMessage(msg *protocol.Message) bool {
 	msgNode := newRemoteNodeWithRequestMessage(msg)
-	for _, server := range ctrl.MulticastManager().Servers {
-		port, err := server.Port()
-		if err != nil {
-			continue
-		}
-		if msgNode.Port() != port {
-			continue
-		}
-		addr, err := server.Address()
-		if err != nil {
-			continue
-		}
-		if msgNode.Address() != addr {
-			continue
-		}
-		return true
+	if msgNode.Port() != ctrl.server.Port() {
+		return false
 	}
-	return false
+	return slices.Contains(ctrl.server.Addresses(), msgNode.Address())
 }
 
 // OnMessage is a listener of the local node.
@@ -84,13 +70,18 @@ func (ctrl *controller) parseNodeProfileMessage(msg *protocol.Message) {
 	ctrl.addNode(node)
 }
 
-// addNode adds a specified node if the node is not added.
+// addNode adds a specified node if the node is not added, otherwise updates the added node.
+// The messages are handled concurrently, so that the found nodes are guarded by the mutex.
 func (ctrl *controller) addNode(notifyNode Node) bool {
-	if slices.ContainsFunc(ctrl.foundNodes, notifyNode.Equals) {
+	ctrl.nodesMutex.Lock()
+	// Updates the objects of the found node with the latest instance list.
+	if n := slices.IndexFunc(ctrl.foundNodes, notifyNode.Equals); 0 <= n {
+		ctrl.foundNodes[n] = notifyNode
+		ctrl.nodesMutex.Unlock()
 		return false
 	}
-
 	ctrl.foundNodes = append(ctrl.foundNodes, notifyNode)
+	ctrl.nodesMutex.Unlock()
 
 	if ctrl.controllerListener != nil {
 		ctrl.controllerListener.ControllerNewNodeFound(notifyNode)
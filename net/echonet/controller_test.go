@@ -6,11 +6,13 @@ package echonet
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cybergarage/go-logger/log"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
 )
 
 const (
@@ -20,13 +22,13 @@ const (
 type testController struct {
 	Controller
 
-	foundTestNodeCount int
+	foundTestNodeCount atomic.Int32
 }
 
 func newTestController(opts ...ControllerOption) *testController {
 	ctrl := &testController{
 		Controller:         NewController(opts...),
-		foundTestNodeCount: 0,
+		foundTestNodeCount: atomic.Int32{},
 	}
 	ctrl.SetListener(ctrl)
 	return ctrl
@@ -42,7 +44,7 @@ func (ctrl *testController) ControllerNewNodeFound(node Node) {
 		return
 	}
 
-	ctrl.foundTestNodeCount++
+	ctrl.foundTestNodeCount.Add(1)
 }
 
 func TestNewController(t *testing.T) {
@@ -70,12 +72,22 @@ func TestNewController(t *testing.T) {
 
 func testControllerSearchWithConfig(t *testing.T, config Config) {
 	t.Helper()
+	testControllerSearchWithNetwork(t, config, nil)
+}
+
+// testControllerSearchWithNetwork runs the controller and test nodes on the specified virtual network, or on the real network when it is nil.
+func testControllerSearchWithNetwork(t *testing.T, config Config, vnet *VirtualNetwork) {
+	t.Helper()
 
 	// Start a controller
 
-	ctrl := newTestController(
+	ctrlOpts := []ControllerOption{
 		WithControllerConfig(config),
-	)
+	}
+	if vnet != nil {
+		ctrlOpts = append(ctrlOpts, WithControllerTransport(vnet.NewTransport()))
+	}
+	ctrl := newTestController(ctrlOpts...)
 	err := ctrl.Start()
 	if err != nil {
 		t.Error(err)
@@ -87,7 +99,11 @@ func testControllerSearchWithConfig(t *testing.T, config Config) {
 
 	nodes := make([]*testLocalNode, testControllerNodeCount)
 	for n := range testControllerNodeCount {
-		node, err := newTestSampleNode(config)
+		nodeOpts := []LocalNodeOption{}
+		if vnet != nil {
+			nodeOpts = append(nodeOpts, WithLocalNodeTransport(vnet.NewTransport()))
+		}
+		node, err := newTestSampleNodeWithConfig(config, nodeOpts...)
 		if err != nil {
 			t.Error(err)
 			return
@@ -118,15 +134,16 @@ func testControllerSearchWithConfig(t *testing.T, config Config) {
 
 	// Check a found device by the listener
 
-	if ctrl.foundTestNodeCount < testControllerNodeCount {
-		if ctrl.foundTestNodeCount == 0 {
-			t.Errorf("Any nodes are not found (%d < %d)", ctrl.foundTestNodeCount, testControllerNodeCount)
+	foundTestNodeCount := int(ctrl.foundTestNodeCount.Load())
+	if foundTestNodeCount < testControllerNodeCount {
+		if foundTestNodeCount == 0 {
+			t.Errorf("Any nodes are not found (%d < %d)", foundTestNodeCount, testControllerNodeCount)
 			return
 		}
-		t.Skipf("%d < %d", ctrl.foundTestNodeCount, testControllerNodeCount)
+		t.Skipf("%d < %d", foundTestNodeCount, testControllerNodeCount)
 	}
 
-	if ctrl.foundTestNodeCount != testControllerNodeCount {
+	if foundTestNodeCount != testControllerNodeCount {
 		for foundNodeIdx, foundNode := range ctrl.Nodes() {
 			isTestNode := false
 			for _, node := range nodes {
@@ -153,8 +170,8 @@ func testControllerSearchWithConfig(t *testing.T, config Config) {
 			}
 		}
 
+		// Skip the controller itself and other Echonet nodes
 		if !isTestNode {
-			t.Skipf("[%d] %s:%d is an unknow node", foundNodeIdx, foundNode.Address(), foundNode.Port())
 			continue
 		}
 
@@ -214,4 +231,28 @@ func TestController(t *testing.T) {
 		conf.TransportConfig().SetTCPEnabled(true)
 		testControllerSearchWithConfig(t, conf)
 	})
+
+	t.Run("IPv6", func(t *testing.T) {
+		addrs, err := transport.GetAvailableAddressesWithFamily(AddressFamilyIPv6)
+		if err != nil || len(addrs) == 0 {
+			t.Skip("IPv6 address is not available")
+		}
+		conf := newTestDefaultConfig()
+		conf.TransportConfig().SetConnectionTimeout(testNodeRequestTimeout)
+		conf.TransportConfig().SetAddressFamily(AddressFamilyIPv6)
+		testControllerSearchWithConfig(t, conf)
+	})
+
+	t.Run("VirtualNetwork", func(t *testing.T) {
+		conf := newTestDefaultConfig()
+		conf.TransportConfig().SetConnectionTimeout(testNodeRequestTimeout)
+		testControllerSearchWithNetwork(t, conf, NewVirtualNetwork())
+	})
+
+	t.Run("VirtualNetworkTCPEnabled", func(t *testing.T) {
+		conf := newTestDefaultConfig()
+		conf.TransportConfig().SetConnectionTimeout(testNodeRequestTimeout)
+		conf.TransportConfig().SetTCPEnabled(true)
+		testControllerSearchWithNetwork(t, conf, NewVirtualNetwork())
+	})
 }
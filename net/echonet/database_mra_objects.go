@@ -12,7 +12,7 @@ func newStandardObject(clsName string, grpCode byte, clsCode byte) Object {
 	return obj
 }
 
-func newStandardProperty(code PropertyCode, name string, dataType string, dataSize int, getRule string, setRule string, annoRule string) Property {
+func newStandardProperty(code PropertyCode, name string, dataType string, dataSize int, getRule string, setRule string, annoRule string, enums ...[]byte) Property {
 	strAttrToPropertyAttr := func(strAttr string) PropertyAttribute {
 		switch strAttr {
 		case "required":
@@ -25,6 +25,9 @@ func newStandardProperty(code PropertyCode, name string, dataType string, dataSi
 	prop := NewProperty(
 		WithPropertyCode(code),
 		WithPropertyName(name),
+		WithPropertyDataType(dataType),
+		WithPropertyCapacity(dataSize),
+		WithPropertyEnums(enums...),
 		WithPropertyReadAttribute(strAttrToPropertyAttr(getRule)),
 		WithPropertyWriteAttribute(strAttrToPropertyAttr(setRule)),
 		WithPropertyAnnoAttribute(strAttrToPropertyAttr(annoRule)),
@@ -38,7 +41,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Super class (0x0000)
 	obj = newStandardObject("Super class", 0x00, 0x00)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "optional", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "optional", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0x81, "Installation location", "raw", 0, "required", "required", "required"))
 	obj.AddProperty(newStandardProperty(0x81, "Installation location", "", 0, "required", "required", "required"))
 	obj.AddProperty(newStandardProperty(0x82, "Standard version information", "raw", 0, "required", "notApplicable", "optional"))
@@ -48,7 +51,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0x85, "Measured cumulative electric energy consumption", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x86, "Manufacturer's fault code", "raw", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x87, "Current limit setting", "number", 0, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0x88, "Fault status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0x88, "Fault status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0x89, "Fault description", "state", 2, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x8A, "manufacturer code", "raw", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x8A, "Manufacturer code", "raw", 0, "required", "notApplicable", "optional"))
@@ -72,7 +75,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Node profile (0x0EF0)
 	obj = newStandardObject("Node profile", 0x0E, 0xF0)
-	obj.AddProperty(newStandardProperty(0x80, "Operating status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operating status", "state", 1, "required", "notApplicable", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0x82, "Version information", "raw", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x83, "Identification number", "raw", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x88, "Fault status", "state", 1, "optional", "notApplicable", "optional"))
@@ -117,7 +120,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0x90, "ON timer reservation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x91, "ON timer setting", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x92, "Set value of ON timer relative time", "time", 2, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xD0, "Hot water heating status", "state", 1, "required", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xD0, "Hot water heating status", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xD1, "Set value of hot water temperature", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xD2, "Hot water warmer setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xD4, "Bath water volume setting 4", "number", 0, "optional", "optional", "optional"))
@@ -127,8 +130,8 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xDA, "Duration of Automatic operation setting", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xDB, "Remaining Automatic operation time", "", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE1, "Set value of bath temperature", "number", 0, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xE2, "Bath water heater status", "state", 1, "required", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xE3, "Bath Auto mode setting", "state", 1, "required_o", "required_o", "optional"))
+	obj.AddProperty(newStandardProperty(0xE2, "Bath water heater status", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}))
+	obj.AddProperty(newStandardProperty(0xE3, "Bath Auto mode setting", "state", 1, "required_o", "required_o", "optional", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xE4, "Bath additional boil-up operation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE5, "Bath hot water adding operation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE6, "Bath water temperature lowering operation setting", "state", 1, "optional", "optional", "optional"))
@@ -179,7 +182,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Television (0x0602)
 	obj = newStandardObject("Television", 0x06, 0x02)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required_o", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required_o", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0xB0, "Display control setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB1, "Character string setting acceptance status", "state", 1, "required_o", "notApplicable", "required"))
 	obj.AddProperty(newStandardProperty(0xB2, "Supported character codes", "bitmap", 2, "required_o", "notApplicable", "optional"))
@@ -222,15 +225,15 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xD7, "Cumulative amount of discharging electric energy reset setting", "state", 1, "notApplicable", "optional", "notApplicable"))
 	obj.AddProperty(newStandardProperty(0xD8, "Measured cumulative amount of charging electric energy", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xD9, "Cumulative amount of charging electric energy reset setting", "state", 1, "notApplicable", "optional", "notApplicable"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x47}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x47}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x47}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x47}, []byte{0x40}))
 	obj.AddProperty(newStandardProperty(0xDB, "System interconnected type", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xDC, "Charging method", "state", 1, "required", "optional", "required"))
-	obj.AddProperty(newStandardProperty(0xDC, "Charging method", "state", 1, "required", "optional", "required"))
-	obj.AddProperty(newStandardProperty(0xDD, "Discharging method", "state", 1, "required", "optional", "required"))
-	obj.AddProperty(newStandardProperty(0xDD, "Discharging method", "state", 1, "required", "optional", "required"))
+	obj.AddProperty(newStandardProperty(0xDC, "Charging method", "state", 1, "required", "optional", "required", []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x00}))
+	obj.AddProperty(newStandardProperty(0xDC, "Charging method", "state", 1, "required", "optional", "required", []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x00}))
+	obj.AddProperty(newStandardProperty(0xDD, "Discharging method", "state", 1, "required", "optional", "required", []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x00}))
+	obj.AddProperty(newStandardProperty(0xDD, "Discharging method", "state", 1, "required", "optional", "required", []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x00}))
 	obj.AddProperty(newStandardProperty(0xDE, "Purchasing electric power setting", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xDF, "Re-interconnection permission setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE0, "Charging/Discharging electric power setting", "number", 0, "optional", "optional", "optional"))
@@ -270,7 +273,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Mono functional lighting (0x0291)
 	obj = newStandardObject("Mono functional lighting", 0x02, 0x91)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0xB0, "Light level Setting", "number", 0, "optional", "optional", "optional"))
 	db.addObject(obj)
 
@@ -338,20 +341,20 @@ func (db *stdDatabase) initObjects() {
 	obj = newStandardObject("Electric water heater", 0x02, 0x6B)
 	obj.AddProperty(newStandardProperty(0x90, "ON timer setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x91, "ON timer setting", "time", 2, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Automatic water heating setting", "state", 1, "required", "required", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Automatic water heating setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xB0, "Automatic water heating setting", "state", 1, "required", "required", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}))
+	obj.AddProperty(newStandardProperty(0xB0, "Automatic water heating setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}))
 	obj.AddProperty(newStandardProperty(0xB1, "Automatic water temperature control setting", "state", 1, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB2, "Water heater status", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xB2, "Water heater status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xB2, "Water heater status", "state", 1, "optional", "notApplicable", "optional", []byte{0x41}, []byte{0x42}))
+	obj.AddProperty(newStandardProperty(0xB2, "Water heater status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xB3, "Water heating temperature setting", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB4, "Manual water heating stop days setting", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB5, "Relative time setting value for manual water heating OFF", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB6, "Tank operation mode setting", "state", 1, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xC0, "Daytime reheating permission setting", "state", 1, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xC0, "Daytime reheating permission setting", "state", 1, "required", "required", "optional"))
+	obj.AddProperty(newStandardProperty(0xC0, "Daytime reheating permission setting", "state", 1, "optional", "optional", "optional", []byte{0x41}, []byte{0x42}))
+	obj.AddProperty(newStandardProperty(0xC0, "Daytime reheating permission setting", "state", 1, "required", "required", "optional", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xC1, "Measured temperature of water in water heater", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC2, "Alarm status", "bitmap", 4, "optional", "notApplicable", "required"))
-	obj.AddProperty(newStandardProperty(0xC3, "Hot water supply status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xC3, "Hot water supply status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xC4, "Relative time setting for keeping bath temperature", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xC7, "Participation in energy shift", "state", 1, "required", "required", "optional"))
 	obj.AddProperty(newStandardProperty(0xC8, "Standard time to start heating", "number", 0, "required", "notApplicable", "optional"))
@@ -376,7 +379,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xE0, "Bath water volume setting", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE1, "Measured amount of water remaining in tank", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE2, "Tank capacity", "number", 0, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xE3, "Automatic Bath Water Heating Mode Setting", "state", 1, "required_o", "required_o", "optional"))
+	obj.AddProperty(newStandardProperty(0xE3, "Automatic Bath Water Heating Mode Setting", "state", 1, "required_o", "required_o", "optional", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xE4, "Manual bath reheating operation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE5, "Addition of hot water function setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE5, "Manual bath hot water addition function setting", "state", 1, "optional", "optional", "optional"))
@@ -391,7 +394,7 @@ func (db *stdDatabase) initObjects() {
 
 	// General lighting (0x0290)
 	obj = newStandardObject("General lighting", 0x02, 0x90)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0x90, "ON timer reservation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x91, "ON timer setting", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x94, "OFF timer reservation setting", "state", 1, "optional", "optional", "optional"))
@@ -404,7 +407,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xB3, "Light color step setting", "raw", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB4, "Maximum specifiable values", "object", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xB5, "Maximum value of settable level for night lighting", "object", 0, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xB6, "Lighting mode setting", "state", 1, "required", "required", "optional"))
+	obj.AddProperty(newStandardProperty(0xB6, "Lighting mode setting", "state", 1, "required", "required", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x45}))
 	obj.AddProperty(newStandardProperty(0xB7, "Light level setting for main lighting", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB8, "Light level step setting for main lighting", "raw", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB9, "Light level setting for night lighting", "number", 0, "optional", "optional", "optional"))
@@ -436,8 +439,8 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xA9, "AC measured cumulative discharging electric energy", "number", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xAA, "AC charge amount setting value", "", 0, "required", "required", "required"))
 	obj.AddProperty(newStandardProperty(0xAB, "AC discharge amount setting value", "", 0, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xC1, "Charging method", "state", 1, "required", "optional", "required"))
-	obj.AddProperty(newStandardProperty(0xC2, "Discharging method", "state", 1, "required", "optional", "required"))
+	obj.AddProperty(newStandardProperty(0xC1, "Charging method", "state", 1, "required", "optional", "required", []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x00}))
+	obj.AddProperty(newStandardProperty(0xC2, "Discharging method", "state", 1, "required", "optional", "required", []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x00}))
 	obj.AddProperty(newStandardProperty(0xC7, "AC rated electric energy", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC8, "Minimum/maximum charging electric power", "object", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC8, "Minimum/maximum charging electric power", "object", 0, "required", "notApplicable", "optional"))
@@ -448,8 +451,8 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xCC, "Re-interconnection permission setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xCD, "Operation permission setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xCE, "Independent operation permission setting", "state", 1, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xCF, "Working operation status", "state", 1, "required", "notApplicable", "required"))
-	obj.AddProperty(newStandardProperty(0xCF, "Working operation status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xCF, "Working operation status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x46}, []byte{0x48}, []byte{0x49}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xCF, "Working operation status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x46}, []byte{0x48}, []byte{0x49}, []byte{0x40}))
 	obj.AddProperty(newStandardProperty(0xD0, "Rated electric energy", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xD1, "Rated capacity", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xD2, "Rated voltage", "number", 0, "optional", "notApplicable", "optional"))
@@ -460,12 +463,12 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xD7, "Measured cumulative discharging electric energy reset setting", "state", 1, "notApplicable", "optional", "notApplicable"))
 	obj.AddProperty(newStandardProperty(0xD8, "Measured cumulative charging electric energy", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xD9, "Measured cumulative charging electric energy reset setting", "state", 1, "notApplicable", "optional", "notApplicable"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xDB, "System-interconnected type", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xDB, "System-interconnected type", "state", 1, "required", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x46}, []byte{0x48}, []byte{0x49}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x46}, []byte{0x48}, []byte{0x49}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x46}, []byte{0x48}, []byte{0x49}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x46}, []byte{0x48}, []byte{0x49}, []byte{0x40}))
+	obj.AddProperty(newStandardProperty(0xDB, "System-interconnected type", "state", 1, "optional", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}))
+	obj.AddProperty(newStandardProperty(0xDB, "System-interconnected type", "state", 1, "required", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}))
 	obj.AddProperty(newStandardProperty(0xDC, "Minimum/maximum charging power (Independent)", "object", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xDD, "Minimum/maximum discharging power (Independent)", "object", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xDE, "Minimum/maximum charging current (Independent)", "object", 0, "optional", "notApplicable", "optional"))
@@ -478,7 +481,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xE3, "Remaining stored electricity 2", "number", 0, "required_c", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE4, "Remaining stored electricity 3", "number", 0, "required_c", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE5, "Battery state of health", "number", 0, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xE6, "Battery type", "state", 1, "required", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xE6, "Battery type", "state", 1, "required", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}, []byte{0x05}, []byte{0x06}))
 	obj.AddProperty(newStandardProperty(0xE7, "Charging amount setting 1", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE8, "Discharging amount setting 1", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE9, "Charging amount setting 2", "number", 0, "optional", "optional", "optional"))
@@ -492,10 +495,10 @@ func (db *stdDatabase) initObjects() {
 
 	// Package-type commercial air conditioner (indoor unit) (except those for facilities) (0x0156)
 	obj = newStandardObject("Package-type commercial air conditioner (indoor unit) (except those for facilities)", 0x01, 0x56)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0xAC, "Thermostat state", "state", 1, "required", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xAE, "Current function (automatic operation mode)", "state", 1, "required", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Operation mode setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
+	obj.AddProperty(newStandardProperty(0xAC, "Thermostat state", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}))
+	obj.AddProperty(newStandardProperty(0xAE, "Current function (automatic operation mode)", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}))
+	obj.AddProperty(newStandardProperty(0xB0, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x40}))
 	obj.AddProperty(newStandardProperty(0xB3, "Temperature setting", "number", 0, "required", "required", "required"))
 	obj.AddProperty(newStandardProperty(0xBB, "Measured indoor unit temperature", "", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xCA, "Group information", "", 0, "required", "notApplicable", "optional"))
@@ -508,8 +511,8 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0x91, "Rice cooking reservation time setting", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x92, "Rice cooking reservation relative time setting", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB0, "Cover closure status", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xB1, "Rice cooking status", "state", 1, "required", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xB2, "Rice cooking control setting", "state", 1, "required_o", "required_o", "optional"))
+	obj.AddProperty(newStandardProperty(0xB1, "Rice cooking status", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}))
+	obj.AddProperty(newStandardProperty(0xB2, "Rice cooking control setting", "state", 1, "required_o", "required_o", "optional", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xE1, "Warmer setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE5, "Inner pot removal status", "state", 1, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE6, "Cover removal status", "state", 1, "optional", "notApplicable", "optional"))
@@ -569,8 +572,8 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0x94, "Off timer reservation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x95, "OFF timer setting value", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x96, "Off relative timer setting value", "time", 2, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Operation setting", "state", 1, "required", "required", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Operation setting", "state", 1, "required", "required", "optional"))
+	obj.AddProperty(newStandardProperty(0xB0, "Operation setting", "state", 1, "required", "required", "optional", []byte{0x10}, []byte{0x20}, []byte{0x30}, []byte{0x40}, []byte{0x50}, []byte{0x60}, []byte{0x61}, []byte{0x00}))
+	obj.AddProperty(newStandardProperty(0xB0, "Operation setting", "state", 1, "required", "required", "optional", []byte{0x10}, []byte{0x20}, []byte{0x30}, []byte{0x40}, []byte{0x50}, []byte{0x60}, []byte{0x61}, []byte{0x00}))
 	obj.AddProperty(newStandardProperty(0xB1, "Ventilation operation setting", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB2, "Bathroom prewarming operation setting", "", 0, "required", "required", "optional"))
 	obj.AddProperty(newStandardProperty(0xB2, "Bathroom prewarming operation setting", "", 0, "optional", "optional", "optional"))
@@ -652,15 +655,15 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xC9, "Cumulative gas consumption reset setting", "state", 1, "notApplicable", "optional", "notApplicable"))
 	obj.AddProperty(newStandardProperty(0xCA, "Power generation setting", "state", 1, "notApplicable", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xCA, "Power generation setting", "state", 1, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xCB, "Power generation status", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xCB, "Power generation status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xCB, "Power generation status", "state", 1, "optional", "notApplicable", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}))
+	obj.AddProperty(newStandardProperty(0xCB, "Power generation status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}))
 	obj.AddProperty(newStandardProperty(0xCC, "Measured in-house instantaneous power consumption", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xCD, "Measured in-house cumulative power consumption", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xCD, "Measured in-house cumulative energy consumption", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xCE, "In-house cumulative power consumption reset", "state", 1, "notApplicable", "optional", "notApplicable"))
 	obj.AddProperty(newStandardProperty(0xCE, "In-house cumulative energy consumption reset", "state", 1, "notApplicable", "optional", "notApplicable"))
-	obj.AddProperty(newStandardProperty(0xD0, "System interconnected type", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xD0, "System interconnected type", "state", 1, "required", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xD0, "System interconnected type", "state", 1, "optional", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}))
+	obj.AddProperty(newStandardProperty(0xD0, "System interconnected type", "state", 1, "required", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}))
 	obj.AddProperty(newStandardProperty(0xD1, "Power generation request time setting", "", 0, "required", "required", "optional"))
 	obj.AddProperty(newStandardProperty(0xD2, "Designated power generation status", "state", 1, "required", "required", "optional"))
 	obj.AddProperty(newStandardProperty(0xE1, "Measured remaining hot water amount", "number", 0, "optional", "notApplicable", "optional"))
@@ -670,7 +673,7 @@ func (db *stdDatabase) initObjects() {
 	// Crime prevention sensor (0x0002)
 	obj = newStandardObject("Crime prevention sensor", 0x00, 0x02)
 	obj.AddProperty(newStandardProperty(0xB0, "Detection threshold level", "level", 0, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB1, "Invasion occurrence status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xB1, "Invasion occurrence status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xBF, "Invasion occurrence status resetting", "state", 1, "notApplicable", "optional", "notApplicable"))
 	db.addObject(obj)
 
@@ -690,8 +693,8 @@ func (db *stdDatabase) initObjects() {
 
 	// Home air conditioner (0x0130)
 	obj = newStandardObject("Home air conditioner", 0x01, 0x30)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
-	obj.AddProperty(newStandardProperty(0x8F, "Power-saving operation setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
+	obj.AddProperty(newStandardProperty(0x8F, "Power-saving operation setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0x90, "ON timer-based reservation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x91, "ON timer setting (time)", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x92, "ON timer setting (relative time)", "time", 2, "optional", "optional", "optional"))
@@ -706,7 +709,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xA5, "Air flow direction (horizontal) setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xAA, "Special state", "state", 1, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xAB, "Non-priority state", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Operation mode setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xB0, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}, []byte{0x40}))
 	obj.AddProperty(newStandardProperty(0xB1, "Automatic temperature control setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB2, "Normal/highspeed/silent operation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB3, "Set temperature value", "", 0, "required", "required", "optional"))
@@ -790,7 +793,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Bidirectional high voltage smart electric energy meter (0x028F)
 	obj = newStandardObject("Bidirectional high voltage smart electric energy meter", 0x02, 0x8F)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "optional", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "optional", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0xC0, "Route B Identification number", "raw", 0, "required_c", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC1, "Monthly maximum electric power demand (normal and reverse directions)", "object", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC2, "Cumulative maximum electric power demand (normal and reverse directions)", "object", 0, "optional", "notApplicable", "optional"))
@@ -835,7 +838,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xD0, "Opening speed setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xD1, "Closing speed setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xD2, "Operation time", "number", 0, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xE0, "Open/close operation setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xE0, "Open/close operation setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}))
 	obj.AddProperty(newStandardProperty(0xE1, "Degree-of-opening setting", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE2, "Blind angle setting", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE3, "Opening/closing speed setting", "state", 1, "optional", "optional", "optional"))
@@ -877,7 +880,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xD3, "Measured instantaneous charging electric energy", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xD8, "Measured cumulative amount of charging electric energy", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xD9, "Cumulative amount of charging electric energy reset setting", "state", 1, "notApplicable", "optional", "notApplicable"))
-	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xDA, "Operation mode setting", "state", 1, "required", "required", "required", []byte{0x42}, []byte{0x44}, []byte{0x47}, []byte{0x40}))
 	obj.AddProperty(newStandardProperty(0xE2, "Remaining stored electricity of vehicle mounted battery1", "number", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE4, "Remaining stored electricity of vehicle mounted battery3", "number", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE6, "Vehicle ID", "object", 0, "required", "notApplicable", "optional"))
@@ -895,7 +898,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Emergency button (0x0003)
 	obj = newStandardObject("Emergency button", 0x00, 0x03)
-	obj.AddProperty(newStandardProperty(0xB1, "Emergency occurrence status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xB1, "Emergency occurrence status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xBF, "Emergency occurrence status resetting", "state", 1, "notApplicable", "optional", "notApplicable"))
 	db.addObject(obj)
 
@@ -915,13 +918,13 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xB3, "Output power change time setting value", "number", 0, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xB4, "Upper limit clip setting value", "", 0, "required_c", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC0, "Operation power factor setting value", "number", 0, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xC1, "FIT contract type", "state", 1, "required", "required", "optional"))
-	obj.AddProperty(newStandardProperty(0xC2, "Self-consumption type", "state", 1, "required", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xC1, "FIT contract type", "state", 1, "required", "required", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}))
+	obj.AddProperty(newStandardProperty(0xC2, "Self-consumption type", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}))
 	obj.AddProperty(newStandardProperty(0xC3, "Capacity approved by equipment", "", 0, "required_c", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC4, "Conversion coefficient", "number", 0, "required_c", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xD0, "System-interconnected type", "state", 1, "optional", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xD0, "System-interconnected type", "state", 1, "required", "notApplicable", "optional"))
-	obj.AddProperty(newStandardProperty(0xD1, "Output power restraint status", "state", 1, "required", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xD0, "System-interconnected type", "state", 1, "optional", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}))
+	obj.AddProperty(newStandardProperty(0xD0, "System-interconnected type", "state", 1, "required", "notApplicable", "optional", []byte{0x00}, []byte{0x01}, []byte{0x02}))
+	obj.AddProperty(newStandardProperty(0xD1, "Output power restraint status", "state", 1, "required", "notApplicable", "optional", []byte{0x41}, []byte{0x42}, []byte{0x43}, []byte{0x44}, []byte{0x45}))
 	obj.AddProperty(newStandardProperty(0xE0, "Measured instantaneous amount of electricity generated", "number", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE1, "Measured cumulative amount of electric energy generated", "number", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE2, "Resetting cumulative amount of electric energy generated", "state", 1, "notApplicable", "optional", "notApplicable"))
@@ -937,7 +940,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Floor heater (0x027B)
 	obj = newStandardObject("Floor heater", 0x02, 0x7B)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0x90, "ON timer reservation setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x91, "Time set by ON timer", "time", 2, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0x92, "Relative ON timer setting", "time", 2, "optional", "optional", "optional"))
@@ -962,7 +965,7 @@ func (db *stdDatabase) initObjects() {
 	// Bath heating status sensor (0x0016)
 	obj = newStandardObject("Bath heating status sensor", 0x00, 0x16)
 	obj.AddProperty(newStandardProperty(0xB0, "Detection threshold level", "level", 0, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB1, "Bath heating detection status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xB1, "Bath heating detection status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	db.addObject(obj)
 
 	// Cold or hot water heat source equipment (0x027A)
@@ -1017,7 +1020,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xD1, "Closing (retraction) speed setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xD2, "Operation time", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xD4, "Automatic operation setting", "state", 1, "optional", "optional", "required"))
-	obj.AddProperty(newStandardProperty(0xE0, "Open/close (extension/retraction) setting", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xE0, "Open/close (extension/retraction) setting", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}, []byte{0x43}))
 	obj.AddProperty(newStandardProperty(0xE1, "Degree-of-opening level", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE2, "Shade angle setting", "number", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE3, "Open/close (extension/retraction) speed setting", "state", 1, "optional", "optional", "optional"))
@@ -1038,7 +1041,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Ventilation fan (0x0133)
 	obj = newStandardObject("Ventilation fan", 0x01, 0x33)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0xA0, "Set value of ventilation air flow rate", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xBF, "Ventilation Auto setting", "state", 1, "optional", "optional", "optional"))
 	db.addObject(obj)
@@ -1069,7 +1072,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Electric lock (0x026F)
 	obj = newStandardObject("Electric lock", 0x02, 0x6F)
-	obj.AddProperty(newStandardProperty(0xE0, "Lock setting1", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0xE0, "Lock setting1", "state", 1, "required", "required", "required", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xE1, "Lock setting 2", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xE2, "Lock status of door guard", "state", 1, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xE3, "Door open/close status", "state", 1, "optional", "notApplicable", "optional"))
@@ -1087,7 +1090,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Air conditioner ventilation fan (0x0134)
 	obj = newStandardObject("Air conditioner ventilation fan", 0x01, 0x34)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0xA0, "Set value of ventilation air flow rate", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB0, "Ventilation mode automatic setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xB1, "Ventilation method setting", "state", 1, "optional", "optional", "optional"))
@@ -1158,7 +1161,7 @@ func (db *stdDatabase) initObjects() {
 	// Human detection sensor (0x0007)
 	obj = newStandardObject("Human detection sensor", 0x00, 0x07)
 	obj.AddProperty(newStandardProperty(0xB0, "Detection threshold level", "level", 0, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB1, "Human detection status", "state", 1, "required", "notApplicable", "required"))
+	obj.AddProperty(newStandardProperty(0xB1, "Human detection status", "state", 1, "required", "notApplicable", "required", []byte{0x41}, []byte{0x42}))
 	db.addObject(obj)
 
 	// Refrigerator (0x03B7)
@@ -1171,7 +1174,7 @@ func (db *stdDatabase) initObjects() {
 	obj.AddProperty(newStandardProperty(0xA8, "Refrigerator compartment humidification function setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xA9, "Vegetable compartment humidification function setting", "state", 1, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xAD, "Deodorization function setting", "state", 1, "optional", "optional", "optional"))
-	obj.AddProperty(newStandardProperty(0xB0, "Door open/close status", "state", 1, "required_o", "notApplicable", "optional"))
+	obj.AddProperty(newStandardProperty(0xB0, "Door open/close status", "state", 1, "required_o", "notApplicable", "optional", []byte{0x41}, []byte{0x42}))
 	obj.AddProperty(newStandardProperty(0xB1, "Door open warning", "state", 1, "optional", "notApplicable", "required"))
 	obj.AddProperty(newStandardProperty(0xB2, "Refrigerator compartment door status", "state", 1, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xB3, "Freezer compartment door status", "state", 1, "optional", "notApplicable", "optional"))
@@ -1201,7 +1204,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Multiple input pcs (0x02A5)
 	obj = newStandardObject("Multiple input pcs", 0x02, 0xA5)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "optional", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "optional", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0x83, "Identification number", "raw", 0, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x89, "Fault description", "state", 2, "required", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0x8C, "Product code", "raw", 0, "required", "notApplicable", "optional"))
@@ -1216,7 +1219,7 @@ func (db *stdDatabase) initObjects() {
 
 	// Air cleaner (0x0135)
 	obj = newStandardObject("Air cleaner", 0x01, 0x35)
-	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required"))
+	obj.AddProperty(newStandardProperty(0x80, "Operation status", "state", 1, "required", "required", "required", []byte{0x30}, []byte{0x31}))
 	obj.AddProperty(newStandardProperty(0xA0, "Air flow rate setting", "", 0, "optional", "optional", "optional"))
 	obj.AddProperty(newStandardProperty(0xC0, "Air pollution detection status", "state", 1, "optional", "notApplicable", "optional"))
 	obj.AddProperty(newStandardProperty(0xC1, "Smoke (cigarette) detection status", "state", 1, "optional", "notApplicable", "optional"))
@@ -124,6 +124,30 @@ func WithDeviceRequestHandler(handler ObjectRequestHandler) DeviceOption {
 	}
 }
 
+// WithDevicePropertyReadHandler returns a DeviceOption that sets the read handler for the specified property of a device.
+func WithDevicePropertyReadHandler(code PropertyCode, handler PropertyReadHandler) DeviceOption {
+	return func(dev *device) error {
+		prop, ok := dev.LookupProperty(code)
+		if !ok {
+			return fmt.Errorf(errPropertyNotFound, ErrNotFound, uint(code))
+		}
+		prop.SetReadHandler(handler)
+		return nil
+	}
+}
+
+// WithDevicePropertyWriteHandler returns a DeviceOption that sets the write handler for the specified property of a device.
+func WithDevicePropertyWriteHandler(code PropertyCode, handler PropertyWriteHandler) DeviceOption {
+	return func(dev *device) error {
+		prop, ok := dev.LookupProperty(code)
+		if !ok {
+			return fmt.Errorf(errPropertyNotFound, ErrNotFound, uint(code))
+		}
+		prop.SetWriteHandler(handler)
+		return nil
+	}
+}
+
 // NewDevice returns a new device with the specified options.
 func NewDevice(opts ...DeviceOption) (Device, error) {
 	dev := newDevice()
@@ -175,7 +199,7 @@ func (dev *device) StandardVersion() (byte, error) {
 	if err != nil {
 		return 0, err
 	}
-	if len(verBytes) <= DeviceStandardVersionSize {
+	if len(verBytes) < DeviceStandardVersionSize {
 		return 0, fmt.Errorf(errDeviceInvalidDeviceStandardVersion, ErrInvalid, string(verBytes))
 	}
 	return verBytes[2], nil
@@ -5,6 +5,7 @@
 package echonet
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 )
@@ -30,3 +31,90 @@ func TestNewDevice(t *testing.T) {
 
 	testObjectPropertyMaps(t, dev)
 }
+
+func TestNewDeviceDefaultPropertyData(t *testing.T) {
+	dev, err := NewDevice(WithDeviceCode(0x029101))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prop := range dev.Properties() {
+		if !prop.IsReadRequired() || prop.Capacity() <= 0 {
+			continue
+		}
+		t.Run(fmt.Sprintf("%02X", prop.Code()), func(t *testing.T) {
+			if prop.Size() == 0 {
+				t.Errorf("%02X : no default data", prop.Code())
+			}
+		})
+	}
+
+	tests := []struct {
+		code PropertyCode
+		data []byte
+	}{
+		{DeviceOperatingStatus, []byte{DeviceOperatingStatusOn}},
+		{DeviceInstallationLocation, []byte{DeviceInstallationLocationUnknown}},
+		{DeviceStandardVersion, []byte{0x00, 0x00, DeviceDefaultVersionAppendix, 0x00}},
+		{DeviceFaultStatus, []byte{DeviceNoFaultOccurred}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%02X", test.code), func(t *testing.T) {
+			data, err := dev.LookupPropertyData(test.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, test.data) {
+				t.Errorf("%X != %X", data, test.data)
+			}
+		})
+	}
+
+	ver, err := dev.(*device).StandardVersion()
+	if err != nil {
+		t.Error(err)
+	} else if ver != DeviceDefaultVersionAppendix {
+		t.Errorf("%c != %c", ver, DeviceDefaultVersionAppendix)
+	}
+
+	// The default data can be overridden.
+
+	if err := dev.SetPropertyData(DeviceOperatingStatus, []byte{DeviceOperatingStatusOff}); err != nil {
+		t.Error(err)
+	}
+	status, err := dev.LookupPropertyByte(DeviceOperatingStatus)
+	if err != nil || status != DeviceOperatingStatusOff {
+		t.Errorf("%X != %X", status, DeviceOperatingStatusOff)
+	}
+}
+
+func TestNewDeviceEnumDefaultPropertyData(t *testing.T) {
+	// General lighting (0x0290) has the lighting mode setting (0xB6) whose first enumerated value is 0x41.
+	dev, err := NewDevice(WithDeviceCode(0x029001))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prop := range dev.Properties() {
+		if !prop.IsReadRequired() || len(prop.Enums()) == 0 {
+			continue
+		}
+		if prop.Code() == DeviceFaultStatus {
+			continue
+		}
+		t.Run(fmt.Sprintf("%02X", prop.Code()), func(t *testing.T) {
+			if !bytes.Equal(prop.Data(), prop.Enums()[0]) {
+				t.Errorf("%X != %X", prop.Data(), prop.Enums()[0])
+			}
+		})
+	}
+
+	data, err := dev.LookupPropertyData(0xB6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0x41}) {
+		t.Errorf("%X != %X", data, []byte{0x41})
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+// DissectedCode represents a dissected code field with the name.
+type DissectedCode struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
+// String returns the string representation of the code such as "029101 (Mono functional lighting)".
+func (code *DissectedCode) String() string {
+	if len(code.Name) == 0 {
+		return code.Code
+	}
+	return fmt.Sprintf("%s (%s)", code.Code, code.Name)
+}
+
+// DissectedProperty represents a dissected property with the name, decoded value and unit.
+type DissectedProperty struct {
+	EPC   DissectedCode `json:"epc"`
+	PDC   int           `json:"pdc"`
+	EDT   string        `json:"edt"`
+	Value string        `json:"value,omitempty"`
+	Unit  string        `json:"unit,omitempty"`
+}
+
+// String returns the string representation of the property such as "80 (Operation status) = ON".
+func (prop *DissectedProperty) String() string {
+	switch {
+	case len(prop.Value) == 0:
+		return fmt.Sprintf("%s : %s", prop.EPC.String(), prop.EDT)
+	case len(prop.Unit) == 0:
+		return fmt.Sprintf("%s = %s", prop.EPC.String(), prop.Value)
+	default:
+		return fmt.Sprintf("%s = %s %s", prop.EPC.String(), prop.Value, prop.Unit)
+	}
+}
+
+// DissectedMessage represents a dissected message with the named fields.
+// The message is encoded to the structured JSON by encoding/json.
+type DissectedMessage struct {
+	EHD        string               `json:"ehd"`
+	TID        string               `json:"tid"`
+	SEOJ       *DissectedCode       `json:"seoj,omitempty"`
+	DEOJ       *DissectedCode       `json:"deoj,omitempty"`
+	ESV        *DissectedCode       `json:"esv,omitempty"`
+	OPC        int                  `json:"opc"`
+	Properties []*DissectedProperty `json:"properties,omitempty"`
+	// EDATA is the hex string of the arbitrary message format (Format 2) data.
+	EDATA string `json:"edata,omitempty"`
+}
+
+// String returns the multi-line text representation of the message.
+func (msg *DissectedMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "EHD  : %s\n", msg.EHD)
+	fmt.Fprintf(&b, "TID  : %s\n", msg.TID)
+	if 0 < len(msg.EDATA) {
+		fmt.Fprintf(&b, "EDATA: %s\n", msg.EDATA)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "SEOJ : %s\n", msg.SEOJ.String())
+	fmt.Fprintf(&b, "DEOJ : %s\n", msg.DEOJ.String())
+	fmt.Fprintf(&b, "ESV  : %s\n", msg.ESV.String())
+	fmt.Fprintf(&b, "OPC  : %d\n", msg.OPC)
+	for n, prop := range msg.Properties {
+		fmt.Fprintf(&b, "  [%d] EPC : %s\n", n, prop.EPC.String())
+		fmt.Fprintf(&b, "      PDC : %d\n", prop.PDC)
+		fmt.Fprintf(&b, "      EDT : %s\n", prop.EDT)
+		if 0 < len(prop.Value) {
+			fmt.Fprintf(&b, "      VAL : %s\n", strings.TrimSpace(prop.Value+" "+prop.Unit))
+		}
+	}
+	return b.String()
+}
+
+// Summary returns the single-line text representation of the message for the logs.
+func (msg *DissectedMessage) Summary() string {
+	if 0 < len(msg.EDATA) {
+		return fmt.Sprintf("%s %s : %s", msg.EHD, msg.TID, msg.EDATA)
+	}
+	props := make([]string, len(msg.Properties))
+	for n, prop := range msg.Properties {
+		props[n] = prop.String()
+	}
+	return fmt.Sprintf("%s %s %s -> %s : %s : [%s]", msg.TID, msg.ESV.String(), msg.SEOJ.String(), msg.DEOJ.String(), msg.EHD, strings.Join(props, ", "))
+}
+
+// DissectorOption is a function that configures a dissector.
+type DissectorOption func(*Dissector)
+
+// propertyDecoderKey represents a key of the property decoders.
+type propertyDecoderKey struct {
+	class ObjectCode
+	code  PropertyCode
+}
+
+// Dissector renders the messages with the named fields using the standard database.
+type Dissector struct {
+	db       StandardDatabase
+	decoders map[propertyDecoderKey]PropertyDecoder
+}
+
+// WithDissectorDatabase sets the database to look up the object and property names. The default database is the shared standard database.
+func WithDissectorDatabase(db StandardDatabase) DissectorOption {
+	return func(dissector *Dissector) {
+		dissector.db = db
+	}
+}
+
+// WithDissectorPropertyDecoder sets the decoder of the specified property of the object class.
+// The instance code of the object code is ignored, and the decoder of SuperObjectCode is used for all classes.
+func WithDissectorPropertyDecoder(code ObjectCode, propCode PropertyCode, decoder PropertyDecoder) DissectorOption {
+	return func(dissector *Dissector) {
+		dissector.decoders[propertyDecoderKey{class: code & 0xFFFF00, code: propCode}] = decoder
+	}
+}
+
+// NewDissector returns a new dissector with the standard property decoders.
+func NewDissector(opts ...DissectorOption) *Dissector {
+	dissector := &Dissector{
+		db:       SharedStandardDatabase(),
+		decoders: map[propertyDecoderKey]PropertyDecoder{},
+	}
+	for _, decoder := range standardPropertyDecoders(dissector) {
+		decoder(dissector)
+	}
+	for _, opt := range opts {
+		opt(dissector)
+	}
+	return dissector
+}
+
+var sharedDissector = sync.OnceValue(func() *Dissector {
+	return NewDissector()
+})
+
+// DissectMessage dissects the specified message with the shared default dissector.
+func DissectMessage(msg *protocol.Message) *DissectedMessage {
+	return sharedDissector().Dissect(msg)
+}
+
+// SetDissectedLogEnabled sets the transport trace logs to output the dissected messages instead of the hex dumps.
+func SetDissectedLogEnabled(flag bool) {
+	if !flag {
+		transport.SetLogMessageFormatter(nil)
+		return
+	}
+	transport.SetLogMessageFormatter(func(b []byte) string {
+		msg, err := sharedDissector().DissectBytes(b)
+		if err != nil {
+			return fmt.Sprintf("%s (%s)", hex.EncodeToString(b), err)
+		}
+		return msg.Summary()
+	})
+}
+
+// objectName returns the class name of the specified object code.
+func (dissector *Dissector) objectName(code ObjectCode) string {
+	obj, ok := dissector.db.LookupObject(code)
+	if !ok {
+		return ""
+	}
+	return obj.ClassName()
+}
+
+// lookupStandardProperty returns the standard property of the specified object code, or the super object.
+func (dissector *Dissector) lookupStandardProperty(code ObjectCode, propCode PropertyCode) (Property, bool) {
+	for _, objCode := range []ObjectCode{code, SuperObjectCode} {
+		obj, ok := dissector.db.LookupObject(objCode)
+		if !ok {
+			continue
+		}
+		prop, ok := obj.LookupProperty(propCode)
+		if ok {
+			return prop, true
+		}
+	}
+	return nil, false
+}
+
+// lookupPropertyDecoder returns the decoder of the specified object code, or the super object.
+func (dissector *Dissector) lookupPropertyDecoder(code ObjectCode, propCode PropertyCode) (PropertyDecoder, bool) {
+	for _, objCode := range []ObjectCode{code & 0xFFFF00, SuperObjectCode} {
+		decoder, ok := dissector.decoders[propertyDecoderKey{class: objCode, code: propCode}]
+		if ok {
+			return decoder, true
+		}
+	}
+	return nil, false
+}
+
+// DissectProperty dissects the specified property data of the specified object.
+func (dissector *Dissector) DissectProperty(code ObjectCode, prop protocol.Property) *DissectedProperty {
+	data := prop.Data()
+	dissected := &DissectedProperty{
+		EPC:   DissectedCode{Code: fmt.Sprintf("%02X", prop.Code()), Name: ""},
+		PDC:   len(data),
+		EDT:   strings.ToUpper(hex.EncodeToString(data)),
+		Value: "",
+		Unit:  "",
+	}
+	stdProp, ok := dissector.lookupStandardProperty(code, prop.Code())
+	if ok {
+		dissected.EPC.Name = stdProp.Name()
+	}
+	if len(data) == 0 {
+		return dissected
+	}
+	decoder, ok := dissector.lookupPropertyDecoder(code, prop.Code())
+	if !ok && stdProp != nil && stdProp.DataType() == propertyDataTypeNumber {
+		decoder, ok = newUnsignedDecoder(0, 0, ""), true
+	}
+	if !ok {
+		return dissected
+	}
+	if value, unit, ok := decoder(data); ok {
+		dissected.Value = value
+		dissected.Unit = unit
+	}
+	return dissected
+}
+
+// Dissect dissects the specified message.
+// The properties are dissected as the ones of the destination object for the requests, otherwise the source object.
+func (dissector *Dissector) Dissect(msg *protocol.Message) *DissectedMessage {
+	ehd := msg.EHD()
+	dissected := &DissectedMessage{
+		EHD:        fmt.Sprintf("%02X%02X", ehd[0], ehd[1]),
+		TID:        fmt.Sprintf("%04X", msg.TID()),
+		SEOJ:       nil,
+		DEOJ:       nil,
+		ESV:        nil,
+		OPC:        0,
+		Properties: nil,
+		EDATA:      "",
+	}
+	if msg.IsFormat2() {
+		dissected.EDATA = strings.ToUpper(hex.EncodeToString(msg.Format2Data()))
+		return dissected
+	}
+
+	newObjectCode := func(code ObjectCode) *DissectedCode {
+		return &DissectedCode{Code: code.String(), Name: dissector.objectName(code)}
+	}
+	dissected.SEOJ = newObjectCode(msg.SEOJ())
+	dissected.DEOJ = newObjectCode(msg.DEOJ())
+	dissected.ESV = &DissectedCode{Code: msg.ESV().String(), Name: msg.ESV().Name()}
+	dissected.OPC = msg.OPC()
+
+	propObj := msg.SEOJ()
+	if msg.ESV().IsRequest() {
+		propObj = msg.DEOJ()
+	}
+	dissected.Properties = make([]*DissectedProperty, 0, len(msg.Properties()))
+	for _, prop := range msg.Properties() {
+		dissected.Properties = append(dissected.Properties, dissector.DissectProperty(propObj, prop))
+	}
+
+	return dissected
+}
+
+// DissectBytes parses and dissects the specified message frame.
+func (dissector *Dissector) DissectBytes(b []byte) (*DissectedMessage, error) {
+	msg, err := protocol.NewMessageWithBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return dissector.Dissect(msg), nil
+}
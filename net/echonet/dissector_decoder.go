@@ -0,0 +1,217 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cybergarage/uecho-go/net/echonet/encoding"
+)
+
+// PropertyDecoder decodes the property data into a human-readable value and the unit.
+// The decoder returns false when the data cannot be decoded.
+type PropertyDecoder func(data []byte) (value string, unit string, ok bool)
+
+const (
+	unitWatt         = "W"
+	unitKiloWattHour = "kWh"
+	unitCelsius      = "°C"
+	unitPercent      = "%"
+)
+
+// formatDecimal returns the string of the specified value which has the specified number of the decimal places.
+func formatDecimal(v int64, decimals int) string {
+	if decimals <= 0 {
+		return fmt.Sprintf("%d", v)
+	}
+	return fmt.Sprintf("%.*f", decimals, float64(v)/math.Pow10(decimals))
+}
+
+// newEnumDecoder returns a decoder of the single byte state with the specified names.
+func newEnumDecoder(names map[byte]string) PropertyDecoder {
+	return func(data []byte) (string, string, bool) {
+		if len(data) != 1 {
+			return "", "", false
+		}
+		name, ok := names[data[0]]
+		return name, "", ok
+	}
+}
+
+// newUnsignedDecoder returns a decoder of the big-endian unsigned integer with the specified size, decimal places and unit.
+// The decoder accepts up to 8 bytes when the size is zero.
+func newUnsignedDecoder(size int, decimals int, unit string) PropertyDecoder {
+	return func(data []byte) (string, string, bool) {
+		if (size != 0 && len(data) != size) || 8 < len(data) {
+			return "", "", false
+		}
+		v := encoding.ByteToInteger(data)
+		if math.MaxInt64 < uint64(v) {
+			return fmt.Sprintf("%d", v), unit, decimals == 0
+		}
+		return formatDecimal(int64(v), decimals), unit, true
+	}
+}
+
+// newSignedDecoder returns a decoder of the big-endian two's complement integer with the specified size, decimal places and unit.
+func newSignedDecoder(size int, decimals int, unit string) PropertyDecoder {
+	return func(data []byte) (string, string, bool) {
+		if len(data) != size || 8 < size {
+			return "", "", false
+		}
+		v := int64(encoding.ByteToInteger(data))
+		if shift := 64 - (size * 8); 0 < shift {
+			v = (v << shift) >> shift
+		}
+		return formatDecimal(v, decimals), unit, true
+	}
+}
+
+// decodeStandardVersion decodes the release order of the APPENDIX Detailed Requirements for ECHONET Device objects.
+func decodeStandardVersion(data []byte) (string, string, bool) {
+	if len(data) != DeviceStandardVersionSize || data[2] < 'A' || 'Z' < data[2] {
+		return "", "", false
+	}
+	if data[3] == 0 {
+		return fmt.Sprintf("Release %c", data[2]), "", true
+	}
+	return fmt.Sprintf("Release %c rev.%d", data[2], data[3]), "", true
+}
+
+// decodeVersionInformation decodes the version information of the node profile.
+func decodeVersionInformation(data []byte) (string, string, bool) {
+	if len(data) != NodeProfileClassVersionInformationSize {
+		return "", "", false
+	}
+	return fmt.Sprintf("%d.%d", data[0], data[1]), "", true
+}
+
+// decodePropertyMap decodes the property code list of the property map.
+func decodePropertyMap(code PropertyCode) PropertyDecoder {
+	return func(data []byte) (string, string, bool) {
+		prop := NewProperty(WithPropertyCode(code), WithPropertyData(data))
+		codes, err := prop.PropertyMapData()
+		if err != nil {
+			return "", "", false
+		}
+		strs := make([]string, len(codes))
+		for n, code := range codes {
+			strs[n] = fmt.Sprintf("%02X", code)
+		}
+		return strings.Join(strs, " "), "", true
+	}
+}
+
+// decodeCurrentTime decodes the current time setting as "HH:MM".
+func decodeCurrentTime(data []byte) (string, string, bool) {
+	if len(data) != DeviceCurrentTimeSettingSize {
+		return "", "", false
+	}
+	return fmt.Sprintf("%02d:%02d", data[0], data[1]), "", true
+}
+
+// decodeCurrentDate decodes the current date setting as "YYYY-MM-DD".
+func decodeCurrentDate(data []byte) (string, string, bool) {
+	if len(data) != DeviceCurrentDateSettingSize {
+		return "", "", false
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", encoding.ByteToInteger(data[0:2]), data[2], data[3]), "", true
+}
+
+// decodeCumulativeOperatingTime decodes the cumulative operating time with the unit.
+func decodeCumulativeOperatingTime(data []byte) (string, string, bool) {
+	units := map[byte]string{0x41: "s", 0x42: "min", 0x43: "h", 0x44: "d"}
+	if len(data) != DeviceCumulativeOperatingTimeSize {
+		return "", "", false
+	}
+	unit, ok := units[data[0]]
+	if !ok {
+		return "", "", false
+	}
+	return fmt.Sprintf("%d", encoding.ByteToInteger(data[1:])), unit, true
+}
+
+// decodeManufacturerCode decodes the manufacturer name of the manufacturer code.
+func (dissector *Dissector) decodeManufacturerCode(data []byte) (string, string, bool) {
+	if len(data) != ObjectManufacturerCodeSize {
+		return "", "", false
+	}
+	man, ok := dissector.db.LookupManufacture(ManufactureCode(encoding.ByteToInteger(data)))
+	if !ok {
+		return "", "", false
+	}
+	return man.Name(), "", true
+}
+
+// decodeObjectList returns a decoder of the object code list which has the specified size of the object codes.
+func (dissector *Dissector) decodeObjectList(codeSize int) PropertyDecoder {
+	return func(data []byte) (string, string, bool) {
+		if len(data) < 1 || len(data) != 1+(int(data[0])*codeSize) {
+			return "", "", false
+		}
+		strs := make([]string, 0, data[0])
+		for n := 1; n < len(data); n += codeSize {
+			code := ObjectCode(encoding.ByteToInteger(data[n:n+codeSize]) << ((ObjectCodeSize - codeSize) * 8))
+			str := fmt.Sprintf("%0*X", codeSize*2, encoding.ByteToInteger(data[n:n+codeSize]))
+			if name := dissector.objectName(code); 0 < len(name) {
+				str = fmt.Sprintf("%s (%s)", str, name)
+			}
+			strs = append(strs, str)
+		}
+		return strings.Join(strs, ", "), "", true
+	}
+}
+
+// standardPropertyDecoders returns the decoders of the well-known standard properties.
+func standardPropertyDecoders(dissector *Dissector) []DissectorOption {
+	const (
+		nodeProfileClass          = ObjectCode(0x0EF000)
+		temperatureSensorClass    = ObjectCode(0x001100)
+		humiditySensorClass       = ObjectCode(0x001200)
+		homeAirConditionerClass   = ObjectCode(0x013000)
+		generalLightingClass      = ObjectCode(0x029000)
+		monoFunctionalLightClass  = ObjectCode(0x029100)
+		lowVoltageSmartMeterClass = ObjectCode(0x028800)
+	)
+	return []DissectorOption{
+		// Super class
+		WithDissectorPropertyDecoder(SuperObjectCode, ObjectOperatingStatus, newEnumDecoder(map[byte]string{ObjectOperatingStatusOn: "ON", ObjectOperatingStatusOff: "OFF"})),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceStandardVersion, decodeStandardVersion),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceMeasuredInstantaneousPowerConsumption, newUnsignedDecoder(DeviceMeasuredInstantaneousPowerConsumptionSize, 0, unitWatt)),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceMeasuredCumulativePowerConsumption, newUnsignedDecoder(DeviceMeasuredCumulativePowerConsumptionSize, 3, unitKiloWattHour)),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceFaultStatus, newEnumDecoder(map[byte]string{0x41: "fault", 0x42: "no fault"})),
+		WithDissectorPropertyDecoder(SuperObjectCode, ObjectManufacturerCode, dissector.decodeManufacturerCode),
+		WithDissectorPropertyDecoder(SuperObjectCode, DevicePowerSavingOperationSetting, newEnumDecoder(map[byte]string{0x41: "power saving", 0x42: "normal"})),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceCurrentTimeSetting, decodeCurrentTime),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceCurrentDateSetting, decodeCurrentDate),
+		WithDissectorPropertyDecoder(SuperObjectCode, DeviceCumulativeOperatingTime, decodeCumulativeOperatingTime),
+		WithDissectorPropertyDecoder(SuperObjectCode, ObjectAnnoPropertyMap, decodePropertyMap(ObjectAnnoPropertyMap)),
+		WithDissectorPropertyDecoder(SuperObjectCode, ObjectSetPropertyMap, decodePropertyMap(ObjectSetPropertyMap)),
+		WithDissectorPropertyDecoder(SuperObjectCode, ObjectGetPropertyMap, decodePropertyMap(ObjectGetPropertyMap)),
+		// Node profile
+		WithDissectorPropertyDecoder(nodeProfileClass, NodeProfileClassVersionInformation, decodeVersionInformation),
+		WithDissectorPropertyDecoder(nodeProfileClass, NodeProfileClassNumberOfSelfNodeInstances, newUnsignedDecoder(NodeProfileClassNumberOfSelfNodeInstancesSize, 0, "")),
+		WithDissectorPropertyDecoder(nodeProfileClass, NodeProfileClassNumberOfSelfNodeClasses, newUnsignedDecoder(NodeProfileClassNumberOfSelfNodeClassesSize, 0, "")),
+		WithDissectorPropertyDecoder(nodeProfileClass, NodeProfileClassInstanceListNotification, dissector.decodeObjectList(ObjectCodeSize)),
+		WithDissectorPropertyDecoder(nodeProfileClass, NodeProfileClassSelfNodeInstanceListS, dissector.decodeObjectList(ObjectCodeSize)),
+		WithDissectorPropertyDecoder(nodeProfileClass, NodeProfileClassSelfNodeClassListS, dissector.decodeObjectList(ObjectCodeSize-1)),
+		// Sensors
+		WithDissectorPropertyDecoder(temperatureSensorClass, 0xE0, newSignedDecoder(2, 1, unitCelsius)),
+		WithDissectorPropertyDecoder(humiditySensorClass, 0xE0, newUnsignedDecoder(1, 0, unitPercent)),
+		// Home air conditioner
+		WithDissectorPropertyDecoder(homeAirConditionerClass, 0xB0, newEnumDecoder(map[byte]string{0x40: "other", 0x41: "automatic", 0x42: "cooling", 0x43: "heating", 0x44: "dehumidification", 0x45: "air circulator"})),
+		WithDissectorPropertyDecoder(homeAirConditionerClass, 0xB3, newSignedDecoder(1, 0, unitCelsius)),
+		WithDissectorPropertyDecoder(homeAirConditionerClass, 0xBA, newUnsignedDecoder(1, 0, unitPercent)),
+		WithDissectorPropertyDecoder(homeAirConditionerClass, 0xBB, newSignedDecoder(1, 0, unitCelsius)),
+		WithDissectorPropertyDecoder(homeAirConditionerClass, 0xBE, newSignedDecoder(1, 0, unitCelsius)),
+		// Lighting
+		WithDissectorPropertyDecoder(generalLightingClass, 0xB0, newUnsignedDecoder(1, 0, unitPercent)),
+		WithDissectorPropertyDecoder(monoFunctionalLightClass, 0xB0, newUnsignedDecoder(1, 0, unitPercent)),
+		// Low-voltage smart electric energy meter
+		WithDissectorPropertyDecoder(lowVoltageSmartMeterClass, 0xE7, newSignedDecoder(4, 0, unitWatt)),
+	}
+}
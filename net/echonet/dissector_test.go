@@ -0,0 +1,158 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestDissector(t *testing.T) {
+	newTestMessage := func(seoj ObjectCode, deoj ObjectCode, esv protocol.ESV, props ...protocol.Property) *protocol.Message {
+		msg := protocol.NewMessage()
+		msg.SetSEOJ(seoj)
+		msg.SetDEOJ(deoj)
+		msg.SetESV(esv)
+		msg.AddProperties(props)
+		return msg
+	}
+
+	newTestProperty := func(code PropertyCode, data ...byte) protocol.Property {
+		prop := protocol.NewPropertyWithCode(code)
+		prop.SetData(data)
+		return prop
+	}
+
+	dissector := NewDissector(
+		WithDissectorPropertyDecoder(0x029101, 0xC0, func(data []byte) (string, string, bool) {
+			return "custom", "lx", true
+		}),
+	)
+
+	tests := []struct {
+		seoj   ObjectCode
+		esv    protocol.ESV
+		prop   protocol.Property
+		name   string
+		value  string
+		unit   string
+		edtHex string
+	}{
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0x80, 0x30), "Operation status", "ON", "", "30"},
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0xB0, 0x64), "Light level Setting", "100", "%", "64"},
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0x84, 0x01, 0x2C), "Measured instantaneous power consumption", "300", "W", "012C"},
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0x85, 0x00, 0x00, 0x30, 0x39), "Measured cumulative electric energy consumption", "12.345", "kWh", "00003039"},
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0x8A, 0x00, 0x00, 0x05), "Manufacturer code", "", "", "000005"},
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0x9F, 0x03, 0x80, 0x8A, 0x9F), "", "80 8A 9F", "", "03808A9F"},
+		{0x029101, protocol.ESVReadResponse, newTestProperty(0xC0, 0x01), "", "custom", "lx", "01"},
+		{0x001101, protocol.ESVReadResponse, newTestProperty(0xE0, 0xFF, 0x9C), "", "-10.0", "°C", "FF9C"},
+		{0x013001, protocol.ESVNotification, newTestProperty(0xBB, 0x1A), "", "26", "°C", "1A"},
+		{0x0EF001, protocol.ESVNotification, newTestProperty(0xD5, 0x01, 0x02, 0x91, 0x01), "", "029101 (Mono functional lighting)", "", "01029101"},
+		{0x0EF001, protocol.ESVReadResponse, newTestProperty(0x82, 0x01, 0x0D, 0x01, 0x00), "", "1.13", "", "010D0100"},
+	}
+
+	for _, test := range tests {
+		msg := newTestMessage(test.seoj, 0x05FF01, test.esv, test.prop)
+		dissected := dissector.Dissect(msg)
+		if len(dissected.Properties) != 1 {
+			t.Fatalf("%s : %d", msg, len(dissected.Properties))
+		}
+		prop := dissected.Properties[0]
+		if 0 < len(test.name) && prop.EPC.Name != test.name {
+			t.Errorf("%s : %s != %s", msg, prop.EPC.Name, test.name)
+		}
+		if 0 < len(test.value) && prop.Value != test.value {
+			t.Errorf("%s : %s != %s", msg, prop.Value, test.value)
+		}
+		if prop.Unit != test.unit {
+			t.Errorf("%s : %s != %s", msg, prop.Unit, test.unit)
+		}
+		if prop.EDT != test.edtHex || prop.PDC != len(test.prop.Data()) {
+			t.Errorf("%s : %s (%d) != %s", msg, prop.EDT, prop.PDC, test.edtHex)
+		}
+	}
+
+	t.Run("request", func(t *testing.T) {
+		// The properties of the requests are looked up in the destination object.
+		msg := newTestMessage(0x05FF01, 0x029101, protocol.ESVWriteRequest, newTestProperty(0xB0, 0x32))
+		dissected := dissector.Dissect(msg)
+		if dissected.ESV.String() != "60 (SetI)" || dissected.DEOJ.Name != "Mono functional lighting" {
+			t.Errorf("%s", dissected)
+		}
+		if prop := dissected.Properties[0]; prop.Value != "50" || prop.Unit != "%" {
+			t.Errorf("%s", prop)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		msg := newTestMessage(0x029101, 0x05FF01, protocol.ESVReadResponse, newTestProperty(0x80, 0x30), newTestProperty(0xB0, 0x64))
+		if err := msg.SetTID(0x1234); err != nil {
+			t.Fatal(err)
+		}
+		dissected := dissector.Dissect(msg)
+		text := dissected.String()
+		for _, line := range []string{
+			"TID  : 1234",
+			"SEOJ : 029101 (Mono functional lighting)",
+			"ESV  : 72 (Get_Res)",
+			"OPC  : 2",
+			"VAL : 100 %",
+		} {
+			if !strings.Contains(text, line) {
+				t.Errorf("%q is not found in\n%s", line, text)
+			}
+		}
+		summary := dissected.Summary()
+		if strings.Contains(summary, "\n") || !strings.Contains(summary, "80 (Operation status) = ON") {
+			t.Errorf("%s", summary)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		msg := newTestMessage(0x029101, 0x05FF01, protocol.ESVReadResponse, newTestProperty(0xB0, 0x64))
+		b, err := json.Marshal(dissector.Dissect(msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(b, &obj); err != nil {
+			t.Fatal(err)
+		}
+		props, ok := obj["properties"].([]any)
+		if !ok || len(props) != 1 {
+			t.Fatalf("%s", b)
+		}
+		prop, ok := props[0].(map[string]any)
+		if !ok || prop["value"] != "100" || prop["unit"] != "%" || prop["edt"] != "64" {
+			t.Errorf("%s", b)
+		}
+	})
+
+	t.Run("format2", func(t *testing.T) {
+		msg := protocol.NewMessage()
+		msg.SetFormat2Data([]byte{0x01, 0x02})
+		dissected := dissector.Dissect(msg)
+		if dissected.EDATA != "0102" || dissected.SEOJ != nil {
+			t.Errorf("%s", dissected)
+		}
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		msg := newTestMessage(0x029101, 0x05FF01, protocol.ESVReadResponse, newTestProperty(0x80, 0x31))
+		dissected, err := dissector.DissectBytes(msg.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dissected.Properties[0].Value != "OFF" {
+			t.Errorf("%s", dissected)
+		}
+		if _, err := dissector.DissectBytes([]byte{0x10, 0x81}); err == nil {
+			t.Error("truncated frame is dissected")
+		}
+	})
+}
@@ -20,5 +20,8 @@ var ErrNotFound = errors.New("not found")
 // ErrUnknown is returned when the value is unknown.
 var ErrUnknown = errors.New("unknown")
 
+// ErrDuplicated is returned when the value is duplicated.
+var ErrDuplicated = errors.New("duplicated")
+
 // ErrTimeout is returned when the operation times out.
 var ErrTimeout = errors.New("timeout")
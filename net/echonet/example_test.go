@@ -127,7 +127,7 @@ func ExampleStandardDatabase_manufacture() {
 func ExampleNewLocalNode() {
 	// onRequest handles property requests for the local device.
 	// It processes only write requests, updating the property if valid.
-	onRequest := func(obj echonet.Object, esv protocol.ESV, reqProp protocol.Property) error {
+	onRequest := func(ctx echonet.RequestContext, obj echonet.Object, esv protocol.ESV, reqProp protocol.Property) error {
 		// Only handle write requests.
 		if !esv.IsWriteRequest() {
 			return nil
@@ -0,0 +1,115 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/cybergarage/uecho-go/net/echonet/encoding"
+)
+
+// IdentificationSource represents a source of the node ID for the identification numbers.
+type IdentificationSource int
+
+const (
+	// IdentificationSourceRandom generates a random node ID.
+	IdentificationSourceRandom IdentificationSource = iota
+	// IdentificationSourceMACAddress derives the node ID from the MAC address of the first bound interface,
+	// or generates a random node ID when no bound interface has a MAC address. This is the default source.
+	IdentificationSourceMACAddress
+	// IdentificationSourceUser uses the node ID supplied by the caller.
+	IdentificationSourceUser
+)
+
+const (
+	// IdentificationNodeIDSize is the size of the node ID. The unique ID field of the identification number
+	// consists of the node ID and the object code, so that each object in a node has a different identification number.
+	IdentificationNodeIDSize = NodeProfileClassIdentificationUniqueIDSize - ObjectCodeSize
+)
+
+const (
+	errIdentificationInvalidNodeID = "%w: node ID (%X)"
+	errIdentificationNoMACAddress  = "%w: MAC address"
+	errIdentificationInvalidFile   = "%w: identification file (%s)"
+)
+
+// String returns the string representation of the source.
+func (src IdentificationSource) String() string {
+	switch src {
+	case IdentificationSourceRandom:
+		return "random"
+	case IdentificationSourceMACAddress:
+		return "mac"
+	case IdentificationSourceUser:
+		return "user"
+	}
+	return ""
+}
+
+// newIdentificationNumber returns a new identification number for the specified object.
+func newIdentificationNumber(manufacturerCode uint, nodeID []byte, objCode ObjectCode) ([]byte, error) {
+	if len(nodeID) != IdentificationNodeIDSize {
+		return nil, fmt.Errorf(errIdentificationInvalidNodeID, ErrInvalid, nodeID)
+	}
+	manufacturerCodeBytes := make([]byte, NodeProfileClassIdentificationManufacturerCodeSize)
+	encoding.IntegerToByte(manufacturerCode, manufacturerCodeBytes)
+
+	idBytes := make([]byte, 0, NodeProfileClassIdentificationNumberSize)
+	idBytes = append(idBytes, LowerCommunicationLayerProtocolType)
+	idBytes = append(idBytes, manufacturerCodeBytes...)
+	idBytes = append(idBytes, nodeID...)
+	idBytes = append(idBytes, objCode.Bytes()...)
+	return idBytes, nil
+}
+
+// newRandomNodeID returns a new random node ID.
+func newRandomNodeID() ([]byte, error) {
+	nodeID := make([]byte, IdentificationNodeIDSize)
+	if _, err := rand.Read(nodeID); err != nil {
+		return nil, err
+	}
+	return nodeID, nil
+}
+
+// newMACAddressNodeID returns a new node ID from the first MAC address of the specified interfaces.
+func newMACAddressNodeID(ifis ...*net.Interface) ([]byte, error) {
+	for _, ifi := range ifis {
+		if ifi == nil || len(ifi.HardwareAddr) == 0 || IdentificationNodeIDSize < len(ifi.HardwareAddr) {
+			continue
+		}
+		nodeID := make([]byte, IdentificationNodeIDSize)
+		copy(nodeID, ifi.HardwareAddr)
+		return nodeID, nil
+	}
+	return nil, fmt.Errorf(errIdentificationNoMACAddress, ErrNotFound)
+}
+
+// loadNodeID loads the node ID from the specified file. It returns nil without an error when the file does not exist.
+func loadNodeID(filename string) ([]byte, error) {
+	hexBytes, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	nodeID, err := hex.DecodeString(strings.TrimSpace(string(hexBytes)))
+	if err != nil || len(nodeID) != IdentificationNodeIDSize {
+		return nil, fmt.Errorf(errIdentificationInvalidFile, ErrInvalid, filename)
+	}
+	return nodeID, nil
+}
+
+// saveNodeID saves the node ID into the specified file.
+func saveNodeID(filename string, nodeID []byte) error {
+	return os.WriteFile(filename, []byte(hex.EncodeToString(nodeID)+"\n"), 0o600)
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func testLocalNodeIdentificationNumbers(t *testing.T, conf Config) [][]byte {
+	t.Helper()
+
+	dev, err := NewDevice(WithDeviceCode(0x029101))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := NewLocalNode(
+		WithLocalNodeConfig(conf),
+		WithLocalNodeDevices(dev),
+	)
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	nodeProf, err := node.NodeProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := [][]byte{}
+	for _, obj := range []SuperObject{nodeProf, dev} {
+		id, err := obj.LookupPropertyData(ObjectIdentificationNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(id) != NodeProfileClassIdentificationNumberSize {
+			t.Fatalf("%s : %X", obj.Code(), id)
+		}
+		if id[0] != LowerCommunicationLayerProtocolType {
+			t.Errorf("%s : %X", obj.Code(), id)
+		}
+		if !bytes.Equal(id[len(id)-ObjectCodeSize:], obj.Codes()) {
+			t.Errorf("%s : %X", obj.Code(), id)
+		}
+		ids = append(ids, id)
+	}
+
+	if bytes.Equal(ids[0], ids[1]) {
+		t.Errorf("%X == %X", ids[0], ids[1])
+	}
+
+	return ids
+}
+
+func TestLocalNodeIdentificationNumber(t *testing.T) {
+	t.Run("random", func(t *testing.T) {
+		conf := NewDefaultConfig(WithConfigIdentificationSource(IdentificationSourceRandom))
+		ids1 := testLocalNodeIdentificationNumbers(t, conf)
+		ids2 := testLocalNodeIdentificationNumbers(t, conf)
+		if bytes.Equal(ids1[0], ids2[0]) {
+			t.Errorf("%X == %X", ids1[0], ids2[0])
+		}
+	})
+
+	t.Run("mac", func(t *testing.T) {
+		conf := NewDefaultConfig()
+		if conf.IdentificationSource() != IdentificationSourceMACAddress {
+			t.Errorf("%s != %s", conf.IdentificationSource(), IdentificationSourceMACAddress)
+		}
+		node := NewLocalNode(WithLocalNodeConfig(conf))
+		if err := node.Start(); err != nil {
+			t.Fatal(err)
+		}
+		ifis := node.(*localNode).boundInterfaces()
+		node.Stop()
+		macID, err := newMACAddressNodeID(ifis...)
+		if err != nil {
+			t.Skipf("no bound interface has a MAC address (%v)", err)
+		}
+		ids1 := testLocalNodeIdentificationNumbers(t, conf)
+		ids2 := testLocalNodeIdentificationNumbers(t, conf)
+		for n := range ids1 {
+			if !bytes.Equal(ids1[n], ids2[n]) {
+				t.Errorf("%X != %X", ids1[n], ids2[n])
+			}
+			if !bytes.Contains(ids1[n], macID) {
+				t.Errorf("%X !~ %X", ids1[n], macID)
+			}
+		}
+	})
+
+	t.Run("mac-virtual", func(t *testing.T) {
+		// The virtual network interface has no MAC address, so the random node ID is generated.
+		node := NewLocalNode(WithLocalNodeTransport(NewVirtualNetwork().NewTransport()))
+		if err := node.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer node.Stop()
+		if len(node.(*localNode).nodeID) != IdentificationNodeIDSize {
+			t.Errorf("%X", node.(*localNode).nodeID)
+		}
+	})
+
+	t.Run("user", func(t *testing.T) {
+		nodeID := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A}
+		ids := testLocalNodeIdentificationNumbers(t, NewDefaultConfig(WithConfigNodeID(nodeID)))
+		for _, id := range ids {
+			if !bytes.Contains(id, nodeID) {
+				t.Errorf("%X !~ %X", id, nodeID)
+			}
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "uecho.id")
+		ids1 := testLocalNodeIdentificationNumbers(t, NewDefaultConfig(WithConfigIdentificationFile(filename)))
+		ids2 := testLocalNodeIdentificationNumbers(t, NewDefaultConfig(WithConfigIdentificationFile(filename)))
+		for n := range ids1 {
+			if !bytes.Equal(ids1[n], ids2[n]) {
+				t.Errorf("%X != %X", ids1[n], ids2[n])
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		node := NewLocalNode(WithLocalNodeConfig(NewDefaultConfig(WithConfigNodeID([]byte{0x01}))))
+		if err := node.Start(); err == nil {
+			node.Stop()
+			t.Error("invalid node ID is accepted")
+		}
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+// InterfaceEvent represents a change of the interfaces and addresses which the node is bound to.
+type InterfaceEvent = transport.InterfaceEvent
+
+// InterfaceEventType represents a type of the interface change.
+type InterfaceEventType = transport.InterfaceEventType
+
+const (
+	// InterfaceAddressAdded represents an address which is newly available, and the node is bound to.
+	InterfaceAddressAdded = transport.InterfaceAddressAdded
+	// InterfaceAddressRemoved represents an address which is no longer available, and the node is unbound from.
+	InterfaceAddressRemoved = transport.InterfaceAddressRemoved
+)
+
+// InterfaceListener is an interface to receive the interface changes of the node.
+type InterfaceListener interface {
+	// InterfaceChanged is called after the node is rebound to the changed interfaces and addresses.
+	InterfaceChanged(*InterfaceEvent)
+}
+
+// InterfaceChanged is a handler of the interface changes for the transport.
+// The node announces the instance list again from the new address when the node is bound to it,
+// because the other addresses of the same address family have already announced it.
+func (node *localNode) InterfaceChanged(event *InterfaceEvent) {
+	if event.Type == InterfaceAddressAdded && event.Err == nil {
+		node.announceInstanceListFrom(event.Address)
+	}
+	if node.ifListener != nil {
+		node.ifListener.InterfaceChanged(event)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+type testInterfaceListener struct {
+	events []*InterfaceEvent
+}
+
+func (l *testInterfaceListener) InterfaceChanged(event *InterfaceEvent) {
+	l.events = append(l.events, event)
+}
+
+func TestLocalNodeInterfaceListener(t *testing.T) {
+	l := &testInterfaceListener{events: []*InterfaceEvent{}}
+	node := newLocalNode(
+		WithLocalNodeInterfaceListener(l),
+	)
+
+	events := []*InterfaceEvent{
+		{Type: InterfaceAddressAdded, Interface: "eth0", Address: "192.168.0.2", Err: nil},
+		{Type: InterfaceAddressRemoved, Interface: "eth0", Address: "192.168.0.2", Err: nil},
+	}
+	for _, event := range events {
+		node.InterfaceChanged(event)
+	}
+
+	if len(l.events) != len(events) {
+		t.Fatalf("%d != %d", len(l.events), len(events))
+	}
+	for n, event := range l.events {
+		if event != events[n] {
+			t.Errorf("%v != %v", event, events[n])
+		}
+	}
+}
+
+type testAddressAnnouncer struct {
+	*transport.VirtualTransport
+	sync.Mutex
+	addrs []string
+}
+
+func (t *testAddressAnnouncer) AnnounceMessageFrom(addr string, msg *protocol.Message) error {
+	t.Lock()
+	defer t.Unlock()
+	t.addrs = append(t.addrs, addr)
+	return t.AnnounceMessage(msg)
+}
+
+func TestLocalNodeInterfaceAnnouncement(t *testing.T) {
+	tr := &testAddressAnnouncer{VirtualTransport: NewVirtualNetwork().NewTransport()} // nolint:exhaustruct
+	node := NewLocalNode(WithLocalNodeTransport(tr))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	// The instance list is announced from the added address only.
+
+	events := []*InterfaceEvent{
+		{Type: InterfaceAddressAdded, Interface: "eth1", Address: "192.168.1.2", Err: nil},
+		{Type: InterfaceAddressRemoved, Interface: "eth1", Address: "192.168.1.2", Err: nil},
+	}
+	for _, event := range events {
+		node.(*localNode).InterfaceChanged(event)
+	}
+
+	tr.Lock()
+	defer tr.Unlock()
+	if len(tr.addrs) != 1 || tr.addrs[0] != events[0].Address {
+		t.Errorf("%v != %v", tr.addrs, []string{events[0].Address})
+	}
+}
@@ -5,6 +5,8 @@
 package echonet
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"strconv"
 	"sync"
@@ -19,12 +21,35 @@ type LocalNodeOption func(*localNode)
 // LocalNode represents a local Echonet node.
 type LocalNode interface {
 	Node
+	// Addresses returns all local addresses that the node is bound to.
+	Addresses() []string
+	// InterfaceAddresses returns all local addresses that the node is bound to for each interface name.
+	InterfaceAddresses() map[string][]string
 	// SetManufacturerCode sets a manufacture codes to the node and all its devices.
 	SetManufacturerCode(code uint)
 	// AddDevice adds a new device into the node, and set the manufacturer code and update the node profile.
-	AddDevice(Device)
+	// The instance code of the device is checked with the instance code policy of the configuration.
+	// The node announces the updated instance list when the node is running.
+	AddDevice(Device) error
+	// RemoveDevice removes the specified device from the node, and update the node profile.
+	// The node announces the updated instance list when the node is running.
+	RemoveDevice(Device) error
 	// SetListener sets a listener to the node.
 	SetListener(NodeListener)
+	// SetInterfaceListener sets a listener to receive the interface changes of the node.
+	SetInterfaceListener(InterfaceListener)
+	// SetAccessPolicy sets an access control policy for the request messages from other nodes.
+	SetAccessPolicy(*AccessPolicy)
+	// AccessPolicy returns the access control policy of the node.
+	AccessPolicy() *AccessPolicy
+	// RateLimitDrops returns the number of the request messages which are dropped by the rate limits for each source address.
+	RateLimitDrops() map[string]uint64
+	// Metrics returns a snapshot of the traffic counters, the request latencies and the error counters of the node.
+	Metrics() *Metrics
+	// AddMessageObserver adds an observer of the message frames which are sent and received by the node.
+	AddMessageObserver(MessageObserver)
+	// RemoveMessageObserver removes the specified observer.
+	RemoveMessageObserver(MessageObserver)
 	// Start starts the node.
 	Start() error
 	// Stop stops the node.
@@ -44,7 +69,9 @@ func WithLocalNodeManufacturerCode(code uint) LocalNodeOption {
 func WithLocalNodeDevices(devs ...Device) LocalNodeOption {
 	return func(node *localNode) {
 		for _, dev := range devs {
-			node.AddDevice(dev)
+			if err := node.AddDevice(dev); err != nil {
+				log.Errorf("%v", err)
+			}
 		}
 	}
 }
@@ -56,6 +83,27 @@ func WithLocalNodeListener(l NodeListener) LocalNodeOption {
 	}
 }
 
+// WithLocalNodeInterfaceListener sets the specified listener to receive the interface changes of the node.
+func WithLocalNodeInterfaceListener(l InterfaceListener) LocalNodeOption {
+	return func(node *localNode) {
+		node.SetInterfaceListener(l)
+	}
+}
+
+// WithLocalNodeAccessPolicy sets the specified access control policy to the node.
+func WithLocalNodeAccessPolicy(policy *AccessPolicy) LocalNodeOption {
+	return func(node *localNode) {
+		node.SetAccessPolicy(policy)
+	}
+}
+
+// WithLocalNodeTransport sets the specified transport to the node instead of the default transport over the real sockets.
+func WithLocalNodeTransport(t Transport) LocalNodeOption {
+	return func(node *localNode) {
+		node.SetTransport(t)
+	}
+}
+
 // WithLocalNodeConfig sets the specified configuration to the node.
 func WithLocalNodeConfig(cfg Config) LocalNodeOption {
 	return func(node *localNode) {
@@ -68,6 +116,8 @@ type localNodeHelper interface {
 	IsRunning() bool
 	// AnnounceProperty announces a property change.
 	AnnounceProperty(prop Property) error
+	// AnnounceProperties announces the property changes of the same object in a message.
+	AnnounceProperties(props ...Property) error
 }
 
 // localNode is an instance for Echonet node.
@@ -77,11 +127,18 @@ type localNode struct {
 	*sync.Mutex
 	Config
 
+	devicesMutex     *sync.Mutex
+	postMutex        *sync.Mutex
 	manufacturerCode uint
+	nodeID           []byte
 	lastTID          uint
 	postResponseCh   chan *protocol.Message
 	postRequestMsg   *protocol.Message
 	listener         NodeListener
+	ifListener       InterfaceListener
+	accessPolicy     *AccessPolicy
+	rateLimiter      *rateLimiter
+	metrics          *nodeMetrics
 }
 
 // NewLocalNode returns a new local Echonet node.
@@ -94,16 +151,24 @@ func newLocalNode(opts ...LocalNodeOption) *localNode {
 		baseNode:         newBaseNode(),
 		server:           newServer(),
 		Mutex:            new(sync.Mutex),
+		devicesMutex:     new(sync.Mutex),
+		postMutex:        new(sync.Mutex),
 		manufacturerCode: NodeManufacturerExperimental,
+		nodeID:           nil,
 		Config:           NewDefaultConfig(),
 		lastTID:          TIDMin,
 		postResponseCh:   nil,
 		postRequestMsg:   nil,
 		listener:         nil,
+		ifListener:       nil,
+		accessPolicy:     nil,
+		rateLimiter:      newRateLimiter(RateLimit{}, RateLimit{}),
+		metrics:          newNodeMetrics(),
 	}
 
 	node.AddProfile(NewNodeProfile())
 	node.server.SetMessageHandler(node)
+	node.server.setInterfaceEventHandler(node)
 
 	for _, opt := range opts {
 		opt(node)
@@ -112,37 +177,44 @@ func newLocalNode(opts ...LocalNodeOption) *localNode {
 	return node
 }
 
-// Address returns the bound address.
+// Address returns the first bound address. Use Addresses() or InterfaceAddresses() to get all bound addresses of the multi-homed node.
 func (node *localNode) Address() string {
-	for _, server := range node.UnicastManager().Servers {
-		addr, err := server.UDPSocket.Address()
-		if err == nil {
-			return addr
-		}
+	addrs := node.server.Addresses()
+	if len(addrs) == 0 {
+		return ""
 	}
-	return ""
+	return addrs[0]
 }
 
 // Port returns the bound port.
 func (node *localNode) Port() int {
-	return node.UnicastManager().Port()
+	return node.server.Port()
 }
 
 // SetConfig sets all configuration flags.
 func (node *localNode) SetConfig(newConfig Config) {
 	node.Config = newConfig
-	node.server.MessageManager.SetConfig(newConfig.TransportConfig())
+	node.server.SetConfig(newConfig.TransportConfig())
+}
+
+// SetTransport sets the specified transport to send and receive the messages. The transport should be set before the node is started.
+func (node *localNode) SetTransport(t Transport) {
+	t.SetConfig(node.Config.TransportConfig())
+	t.SetMessageHandler(node)
+	node.server.Transport = t
+	node.server.setInterfaceEventHandler(node)
 }
 
 // SetManufacturerCode sets a manufacture codes to the node and all its devices.
 func (node *localNode) SetManufacturerCode(code uint) {
 	node.manufacturerCode = code
-	for _, dev := range node.devices {
+	for _, dev := range node.Devices() {
 		dev.SetManufacturerCode(code)
 	}
-	for _, prop := range node.profiles {
+	for _, prop := range node.Profiles() {
 		prop.SetManufacturerCode(code)
 	}
+	node.updateIdentificationNumbers()
 }
 
 // ManufacturerCode return the manufacture codes of the node.
@@ -160,6 +232,31 @@ func (node *localNode) Listener() NodeListener {
 	return node.listener
 }
 
+// SetInterfaceListener sets a listener to receive the interface changes of the node.
+func (node *localNode) SetInterfaceListener(l InterfaceListener) {
+	node.ifListener = l
+}
+
+// SetAccessPolicy sets an access control policy for the request messages from other nodes.
+func (node *localNode) SetAccessPolicy(policy *AccessPolicy) {
+	node.accessPolicy = policy
+}
+
+// AccessPolicy returns the access control policy of the node.
+func (node *localNode) AccessPolicy() *AccessPolicy {
+	return node.accessPolicy
+}
+
+// Metrics returns a snapshot of the traffic counters, the request latencies and the error counters of the node.
+func (node *localNode) Metrics() *Metrics {
+	return node.metrics.Snapshot(node.server.Metrics())
+}
+
+// RateLimitDrops returns the number of the request messages which are dropped by the rate limits for each source address.
+func (node *localNode) RateLimitDrops() map[string]uint64 {
+	return node.rateLimiter.Drops()
+}
+
 // LastTID returns a last sent TID.
 func (node *localNode) LastTID() uint {
 	return node.lastTID
@@ -176,28 +273,67 @@ func (node *localNode) NextTID() uint {
 }
 
 // AddDevice adds a new device into the node, and set the manufacturer code and update the node profile.
-func (node *localNode) AddDevice(dev Device) {
+// The instance code of the device is checked with the instance code policy of the configuration.
+// The node announces the updated instance list when the node is running.
+func (node *localNode) AddDevice(dev Device) error {
+	node.devicesMutex.Lock()
+	defer node.devicesMutex.Unlock()
+	if err := allocateInstanceCode(dev, node.Devices(), node.Config.InstanceCodePolicy()); err != nil {
+		return err
+	}
 	node.baseNode.AddDevice(dev)
 	dev.SetManufacturerCode(node.manufacturerCode)
+	node.updateIdentificationNumber(dev)
 	dev.SetParentNode(node)
 	node.updateNodeProfile()
+	node.announceInstanceList()
+	return nil
+}
+
+// RemoveDevice removes the specified device from the node, and update the node profile.
+// The node announces the updated instance list when the node is running.
+func (node *localNode) RemoveDevice(dev Device) error {
+	node.devicesMutex.Lock()
+	defer node.devicesMutex.Unlock()
+	if err := node.baseNode.RemoveDevice(dev); err != nil {
+		return err
+	}
+	dev.SetParentNode(nil)
+	node.updateNodeProfile()
+	node.announceInstanceList()
+	return nil
 }
 
 // AddProfile adds a new profile object into the node, and set the node profile and manufacture code.
 func (node *localNode) AddProfile(prof Profile) {
 	node.baseNode.AddProfile(prof)
 	prof.SetManufacturerCode(node.manufacturerCode)
+	node.updateIdentificationNumber(prof)
 	prof.SetParentNode(node)
 	node.updateNodeProfile()
 }
 
 // Start starts the node.
 func (node *localNode) Start() error {
+	node.rateLimiter.SetLimits(node.SourceRateLimit(), node.GlobalRateLimit())
+
 	err := node.server.Start()
 	if err != nil {
 		return err
 	}
 
+	// Sets the identification numbers after binding to know the bound interfaces, but before announcing them.
+
+	if len(node.nodeID) == 0 {
+		nodeID, err := node.resolveNodeID()
+		if err != nil {
+			node.server.Stop()
+			return err
+		}
+		node.nodeID = nodeID
+		node.updateIdentificationNumbers()
+	}
+
 	err = node.Announce()
 	if err != nil {
 		return err
@@ -227,6 +363,26 @@ func (node *localNode) Equals(otherNode Node) bool {
 	return nodeEquals(node, otherNode)
 }
 
+// announceInstanceList announces the instance list notification when the node is running.
+func (node *localNode) announceInstanceList() {
+	if !node.IsRunning() {
+		return
+	}
+	if err := node.Announce(); err != nil {
+		log.Errorf("%v", err)
+	}
+}
+
+// announceInstanceListFrom announces the instance list notification from the specified bound address when the node is running.
+func (node *localNode) announceInstanceListFrom(addr string) {
+	if !node.IsRunning() {
+		return
+	}
+	if err := node.announceFrom(addr); err != nil {
+		log.Errorf("%v", err)
+	}
+}
+
 // updateNodeProfile updates the node profile in the node.
 func (node *localNode) updateNodeProfile() {
 	nodeProf, err := node.NodeProfile()
@@ -237,9 +393,10 @@ func (node *localNode) updateNodeProfile() {
 
 	// Check the current all objects
 
+	devs := node.Devices()
 	classes := make([]Class, 0)
 
-	for _, dev := range node.devices {
+	for _, dev := range devs {
 		devClass := dev.Class()
 		hasSameClass := false
 		for _, class := range classes {
@@ -254,7 +411,7 @@ func (node *localNode) updateNodeProfile() {
 		classes = append(classes, devClass)
 	}
 
-	for _, prof := range node.profiles {
+	for _, prof := range node.Profiles() {
 		profClass := prof.Class()
 		hasSameClass := false
 		for _, class := range classes {
@@ -271,7 +428,7 @@ func (node *localNode) updateNodeProfile() {
 
 	// Number of self-node instances
 
-	instanceCount := uint(len(node.devices))
+	instanceCount := uint(len(devs))
 	nodeProf.SetInstanceCount(instanceCount)
 
 	// Number of self-node classes
@@ -280,7 +437,7 @@ func (node *localNode) updateNodeProfile() {
 
 	// Self-node instance list S and Instance list notification
 
-	nodeProf.SetInstanceList(node.devices)
+	nodeProf.SetInstanceList(devs)
 
 	// Self-node class list S
 
@@ -291,3 +448,98 @@ func (node *localNode) updateNodeProfile() {
 func (node *localNode) String() string {
 	return net.JoinHostPort(node.Address(), strconv.Itoa(node.Port()))
 }
+
+// resolveNodeID returns a node ID from the configured source. The node ID derived from the MAC address of the bound interface
+// is same after restarts, and the generated node ID is persisted into the identification file when the file is specified,
+// so that the persisted node ID is reused after restarts.
+func (node *localNode) resolveNodeID() ([]byte, error) {
+	if node.Config.IdentificationSource() == IdentificationSourceUser {
+		nodeID := node.Config.NodeID()
+		if len(nodeID) != IdentificationNodeIDSize {
+			return nil, fmt.Errorf(errIdentificationInvalidNodeID, ErrInvalid, nodeID)
+		}
+		return nodeID, nil
+	}
+
+	filename := node.Config.IdentificationFile()
+	if 0 < len(filename) {
+		nodeID, err := loadNodeID(filename)
+		if err != nil {
+			return nil, err
+		}
+		if nodeID != nil {
+			return nodeID, nil
+		}
+	}
+
+	var nodeID []byte
+	var err error
+	switch node.Config.IdentificationSource() {
+	case IdentificationSourceMACAddress:
+		nodeID, err = newMACAddressNodeID(node.boundInterfaces()...)
+		if errors.Is(err, ErrNotFound) {
+			// The bound interfaces such as the virtual network interfaces might have no MAC address.
+			nodeID, err = newRandomNodeID()
+		}
+	default:
+		nodeID, err = newRandomNodeID()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if 0 < len(filename) {
+		if err := saveNodeID(filename, nodeID); err != nil {
+			return nil, err
+		}
+	}
+
+	return nodeID, nil
+}
+
+// boundInterfaces returns the interfaces which the node is bound to in the order of the bound addresses.
+func (node *localNode) boundInterfaces() []*net.Interface {
+	ifnames := map[string]string{}
+	for ifname, addrs := range node.InterfaceAddresses() {
+		for _, addr := range addrs {
+			ifnames[addr] = ifname
+		}
+	}
+	ifis := []*net.Interface{}
+	for _, addr := range node.Addresses() {
+		ifi, err := net.InterfaceByName(ifnames[addr])
+		if err != nil {
+			continue
+		}
+		ifis = append(ifis, ifi)
+	}
+	return ifis
+}
+
+// updateIdentificationNumbers updates the identification numbers of all objects in the node.
+func (node *localNode) updateIdentificationNumbers() {
+	for _, prof := range node.Profiles() {
+		node.updateIdentificationNumber(prof)
+	}
+	for _, dev := range node.Devices() {
+		node.updateIdentificationNumber(dev)
+	}
+}
+
+// updateIdentificationNumber updates the identification number of the specified object when the node ID is resolved.
+// The identification number is set without the autonomous notification because the node is announced after that.
+func (node *localNode) updateIdentificationNumber(obj SuperObject) {
+	if len(node.nodeID) == 0 {
+		return
+	}
+	prop, ok := obj.LookupProperty(ObjectIdentificationNumber)
+	if !ok {
+		return
+	}
+	idBytes, err := newIdentificationNumber(node.manufacturerCode, node.nodeID, obj.Code())
+	if err != nil {
+		log.Errorf("%v", err)
+		return
+	}
+	prop.setData(idBytes)
+}
@@ -5,12 +5,26 @@
 package echonet
 
 import (
+	"context"
+
 	"github.com/cybergarage/go-logger/log"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
+const (
+	logLocalNodeAccessDeniedFormat = "localNode::access denied : %s %02X %s %02X"
+	logLocalNodeRateLimitedFormat  = "localNode::rate limited : %s %02X %s (%d drops)"
+)
+
 // ProtocolMessageReceived is a listener for the server.
 func (node *localNode) ProtocolMessageReceived(msg *protocol.Message) (*protocol.Message, error) {
+	resMsg, err := node.handleProtocolMessage(msg)
+	node.metrics.countSentResponse(resMsg)
+	return resMsg, err
+}
+
+// handleProtocolMessage handles the specified received message, and returns the response message.
+func (node *localNode) handleProtocolMessage(msg *protocol.Message) (*protocol.Message, error) {
 	if !node.SelfMessageEnabled() {
 		msgNode := newRemoteNodeWithRequestMessage(msg)
 		if msgNode.Equals(node) {
@@ -18,26 +32,127 @@ func (node *localNode) ProtocolMessageReceived(msg *protocol.Message) (*protocol
 		}
 	}
 
+	node.metrics.countReceivedResponse(msg)
+
 	if node.isResponseMessageWaiting() {
-		if node.isResponseMessage(msg) {
-			node.setResponseMessage(msg)
+		node.setResponseMessage(msg)
+	}
+
+	if isRateLimitedMessage(msg) {
+		if allowed, drops := node.rateLimiter.Allow(msg.SourceAddress()); !allowed {
+			// The dropped requests are logged only at the powers of two not to flood the log.
+			if drops&(drops-1) == 0 {
+				log.Warnf(logLocalNodeRateLimitedFormat, msg.From.String(), msg.ESV(), msg.DEOJ(), drops)
+			}
+			if node.RateLimitAction() == RateLimitActionSNA && !msg.IsFormat2() && msg.ESV() != protocol.ESVNotificationResponseRequired {
+				return protocol.NewImpossibleMessageWithMessage(msg), nil
+			}
+			return nil, nil
+		}
+	}
+
+	// The arbitrary message format (Format 2) messages are delivered only to the node listener when the source is allowed.
+
+	if msg.IsFormat2() {
+		ctx := newRequestContext(context.Background(), node, msg)
+		if !node.authorizeRequestMessage(ctx, msg) {
+			return nil, nil
 		}
+		return nil, node.executeFormat2MessageListener(msg)
 	}
 
+	if isAllInstanceObjectCode(msg.DEOJ()) {
+		return node.handleAllInstanceRequestMessage(msg)
+	}
+
+	return node.handleRequestMessage(msg)
+}
+
+// handleAllInstanceRequestMessage handles the specified request message for all instances of the destination class.
+// The request message is handled for each instance, and the response messages are sent for each instance.
+func (node *localNode) handleAllInstanceRequestMessage(msg *protocol.Message) (*protocol.Message, error) {
+	dstCodes := msg.DEOJ().Bytes()
+
+	var lastResMsg *protocol.Message
+	var lastErr error
+	for _, obj := range node.Objects() {
+		if obj.ClassGroupCode() != dstCodes[0] || obj.ClassCode() != dstCodes[1] {
+			continue
+		}
+
+		objMsg, err := protocol.NewMessageWithMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		objMsg.SetDEOJ(obj.Code())
+
+		resMsg, err := node.handleRequestMessage(objMsg)
+		if err != nil {
+			lastErr = err
+		}
+		if resMsg == nil {
+			continue
+		}
+
+		// Sends the previous response message, and returns the last response message to the transport.
+
+		if lastResMsg != nil {
+			if _, err := node.server.SendMessage(msg.SourceAddress(), msg.SourcePort(), lastResMsg); err != nil {
+				lastErr = err
+			}
+			node.metrics.countSentResponse(lastResMsg)
+		}
+		lastResMsg = resMsg
+	}
+
+	return lastResMsg, lastErr
+}
+
+// handleRequestMessage handles the specified request message for the destination object.
+func (node *localNode) handleRequestMessage(msg *protocol.Message) (*protocol.Message, error) {
 	if !node.validateReceivedMessage(msg) {
 		return protocol.NewImpossibleMessageWithMessage(msg), nil
 	}
 
-	err := node.executeMessageListeners(msg)
+	ctx := newRequestContext(context.Background(), node, msg)
+
+	if !node.authorizeRequestMessage(ctx, msg) {
+		return protocol.NewImpossibleMessageWithMessage(msg), nil
+	}
+
+	err := node.executeMessageListeners(ctx, msg)
+	if err != nil {
+		ctx.cancelDeferral()
+		return nil, err
+	}
+
+	// The response is sent by the responder when the request handlers defer the response.
+	// The TCP connection waits for the responder to return the response over the same connection.
+
+	if res := ctx.deferredResponder(); res != nil {
+		if res.isSynchronous() {
+			return res.wait(), nil
+		}
+		return nil, nil
+	}
+
+	return node.respondRequestMessage(ctx, msg)
+}
+
+// respondRequestMessage executes the property write handlers, and returns the response message for the specified request message.
+func (node *localNode) respondRequestMessage(ctx RequestContext, msg *protocol.Message) (*protocol.Message, error) {
+	rejectedProps, err := node.executePropertyWriteHandlers(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
 
-	if !msg.ESV().IsResponseRequired() {
+	// The SNA response is returned even when the request requires no response.
+
+	if !msg.ESV().IsResponseRequired() && len(rejectedProps) == 0 {
 		return nil, nil
 	}
 
-	resMsg, err := node.createResponseMessageForRequestMessage(msg)
+	resMsg, err := node.createResponseMessageForRequestMessage(ctx, msg, rejectedProps)
 	if err != nil {
 		log.Errorf("%v", err)
 	}
@@ -45,6 +160,23 @@ func (node *localNode) ProtocolMessageReceived(msg *protocol.Message) (*protocol
 	return resMsg, err
 }
 
+// authorizeRequestMessage checks whether the request message is allowed by the access policy, and emits an audit event when the request is denied.
+func (node *localNode) authorizeRequestMessage(ctx RequestContext, msg *protocol.Message) bool {
+	policy := node.AccessPolicy()
+	if policy == nil {
+		return true
+	}
+	action, deniedProps := policy.evaluate(msg)
+	if action == AccessAllow {
+		return true
+	}
+	log.Warnf(logLocalNodeAccessDeniedFormat, msg.From.String(), msg.ESV(), msg.DEOJ(), deniedProps)
+	if policy.auditHandler != nil {
+		policy.auditHandler(ctx, deniedProps)
+	}
+	return false
+}
+
 // validateReceivedMessage checks whether the received message is a valid message.
 func (node *localNode) validateReceivedMessage(msg *protocol.Message) bool {
 	// 4.2.2 Basic Sequences for Object Control in General
@@ -99,8 +231,19 @@ func (node *localNode) validateReceivedMessage(msg *protocol.Message) bool {
 	return true
 }
 
+// executeFormat2MessageListener posts the received arbitrary message format (Format 2) message to the node listener.
+func (node *localNode) executeFormat2MessageListener(msg *protocol.Message) error {
+	l := node.Listener()
+	if l == nil {
+		return nil
+	}
+	err := l.OnMessage(msg)
+	node.metrics.countHandlerError(err)
+	return err
+}
+
 // executeMessageListeners post the received message to the listeners.
-func (node *localNode) executeMessageListeners(msg *protocol.Message) error {
+func (node *localNode) executeMessageListeners(ctx RequestContext, msg *protocol.Message) error {
 	msgDstObjCode := msg.DEOJ()
 	dstObj, err := node.LookupObject(msgDstObjCode)
 	if err != nil {
@@ -117,6 +260,7 @@ func (node *localNode) executeMessageListeners(msg *protocol.Message) error {
 	if l := node.Listener(); l != nil {
 		err := l.OnMessage(msg)
 		if err != nil {
+			node.metrics.countHandlerError(err)
 			lastErr = err
 		}
 	}
@@ -128,8 +272,9 @@ func (node *localNode) executeMessageListeners(msg *protocol.Message) error {
 		if msgProp == nil {
 			continue
 		}
-		err := dstObj.notifyPropertyRequest(msgESV, msgProp)
+		err := dstObj.notifyPropertyRequest(ctx, msgESV, msgProp)
 		if err != nil {
+			node.metrics.countHandlerError(err)
 			lastErr = err
 		}
 	}
@@ -137,28 +282,130 @@ func (node *localNode) executeMessageListeners(msg *protocol.Message) error {
 	return lastErr
 }
 
+// executePropertyWriteHandlers calls the write handlers of the requested properties, and stores the accepted data into the properties.
+// It returns the properties which are rejected by the write handlers.
+func (node *localNode) executePropertyWriteHandlers(ctx context.Context, msg *protocol.Message) (map[PropertyCode]bool, error) {
+	rejectedProps := map[PropertyCode]bool{}
+
+	if !msg.ESV().IsWriteRequest() {
+		return rejectedProps, nil
+	}
+
+	dstObj, err := node.LookupObject(msg.DEOJ())
+	if err != nil {
+		return nil, err
+	}
+
+	// The accepted data is stored into the properties atomically.
+
+	acceptedProps := map[PropertyCode][]byte{}
+	for _, msgProp := range msg.Properties() {
+		prop, ok := dstObj.LookupProperty(msgProp.Code())
+		if !ok {
+			continue
+		}
+		h := prop.WriteHandler()
+		if h == nil {
+			continue
+		}
+		if err := h(ctx, prop, msgProp.Data()); err != nil {
+			node.metrics.countHandlerError(err)
+			log.Warnf("%s %02X : %v", dstObj.Code(), prop.Code(), err)
+			rejectedProps[prop.Code()] = true
+			continue
+		}
+		acceptedProps[prop.Code()] = msgProp.Data()
+	}
+
+	if 0 < len(acceptedProps) {
+		if err := dstObj.SetPropertiesData(acceptedProps); err != nil {
+			return nil, err
+		}
+	}
+
+	return rejectedProps, nil
+}
+
 // createResponseMessageForRequestMessage retunrs the response message for the specified request message.
-func (node *localNode) createResponseMessageForRequestMessage(reqMsg *protocol.Message) (*protocol.Message, error) {
+// The response message is a SNA response when some properties are rejected by the write handlers or the read handlers.
+func (node *localNode) createResponseMessageForRequestMessage(ctx context.Context, reqMsg *protocol.Message, rejectedProps map[PropertyCode]bool) (*protocol.Message, error) {
 	msgDstObjCode := reqMsg.DEOJ()
 	dstObj, err := node.LookupObject(msgDstObjCode)
 	if err != nil {
 		return nil, err
 	}
 
-	msgOPC := reqMsg.OPC()
+	reqESV := reqMsg.ESV()
+	isSNA := 0 < len(rejectedProps)
 
-	resMsg := protocol.NewResponseMessageWithMessage(reqMsg)
-	for n := range msgOPC {
-		msgProp := reqMsg.Property(n)
-		if msgProp == nil {
-			continue
-		}
+	// The stored data of the requested properties is read atomically, so that the response never includes a half-applied update.
+
+	msgProps := make([]protocol.Property, 0, reqMsg.OPC())
+	reqProps := make([]Property, 0, reqMsg.OPC())
+	reqCodes := make([]PropertyCode, 0, reqMsg.OPC())
+	for _, msgProp := range reqMsg.Properties() {
 		prop, ok := dstObj.LookupProperty(msgProp.Code())
 		if !ok {
 			continue
 		}
-		resMsg.AddProperty(prop.ToProtocol())
+		msgProps = append(msgProps, msgProp)
+		reqProps = append(reqProps, prop)
+		reqCodes = append(reqCodes, prop.Code())
+	}
+	propData, err := dstObj.LookupPropertiesData(reqCodes...)
+	if err != nil {
+		return nil, err
+	}
+
+	resProps := make([]protocol.Property, 0, reqMsg.OPC())
+	for n, prop := range reqProps {
+		msgProp := msgProps[n]
+		resProp := protocol.NewPropertyWithCode(prop.Code())
+		switch {
+		case rejectedProps[prop.Code()]:
+			// The rejected write data is returned as it is.
+			resProp.SetData(msgProp.Data())
+		case reqESV.IsWriteRequest():
+			resProp.SetData(propData[n])
+		case reqESV.IsReadRequest() || reqESV.IsNotificationRequest():
+			data, err := readPropertyData(ctx, prop, propData[n])
+			if err != nil {
+				node.metrics.countHandlerError(err)
+				log.Warnf("%s %02X : %v", dstObj.Code(), prop.Code(), err)
+				isSNA = true
+				break
+			}
+			resProp.SetData(data)
+		default:
+			resProp.SetData(propData[n])
+		}
+		resProps = append(resProps, resProp)
+	}
+
+	if !isSNA {
+		resMsg := protocol.NewResponseMessageWithMessage(reqMsg)
+		resMsg.AddProperties(resProps)
+		return resMsg, nil
+	}
+
+	// The accepted write data is not returned in the SNA response.
+
+	resMsg := protocol.NewImpossibleResponseMessageWithMessage(reqMsg)
+	for _, resProp := range resProps {
+		if reqESV.IsWriteRequest() && !rejectedProps[resProp.Code()] {
+			resProp.SetData([]byte{})
+		}
+		resMsg.AddProperty(resProp)
 	}
 
 	return resMsg, nil
 }
+
+// readPropertyData returns the property data from the read handler, or the specified stored data when the property has no read handler.
+func readPropertyData(ctx context.Context, prop Property, data []byte) ([]byte, error) {
+	h := prop.ReadHandler()
+	if h == nil {
+		return data, nil
+	}
+	return h(ctx, prop)
+}
@@ -20,45 +20,97 @@ const (
 const (
 	errNodeRequestTimeout = "request %w (%v)"
 	errNodeIsNotRunning   = "%w: node (%s) is not running "
+	errNodeInvalidMessage = "%w: message (%s) : %w"
+	errNodeNotRequest     = "%w: ESV (%s) does not require any response"
+	errNodeNotOutbound    = "%w: ESV (%s) is neither a request nor a notification"
 )
 
 // AnnounceMessage announces a message.
 func (node *localNode) AnnounceMessage(msg *protocol.Message) error {
+	if err := node.updateAnnouncementHeader(msg); err != nil {
+		return err
+	}
+	return node.server.AnnounceMessage(msg)
+}
+
+// announceMessageFrom announces a message from the specified bound address.
+func (node *localNode) announceMessageFrom(addr string, msg *protocol.Message) error {
+	if err := node.updateAnnouncementHeader(msg); err != nil {
+		return err
+	}
+	return node.server.announceMessageFrom(addr, msg)
+}
+
+// updateAnnouncementHeader updates the message header to announce it.
+func (node *localNode) updateAnnouncementHeader(msg *protocol.Message) error {
 	if !node.IsRunning() {
 		return fmt.Errorf(errNodeIsNotRunning, ErrInvalid, node)
 	}
 	msg.SetTID(node.NextTID())
 	msg.SetDEOJ(NodeProfileObjectCode)
-	return node.server.AnnounceMessage(msg)
+	return nil
 }
 
 // AnnounceProperty announces a specified property.
 func (node *localNode) AnnounceProperty(prop Property) error {
+	return node.AnnounceProperties(prop)
+}
+
+// AnnounceProperties announces the specified properties of the same object in a message.
+func (node *localNode) AnnounceProperties(props ...Property) error {
+	if len(props) == 0 {
+		return nil
+	}
+	return node.AnnounceMessage(newPropertyAnnouncementMessage(props...))
+}
+
+// newPropertyAnnouncementMessage returns a new notification message of the specified properties of the same object.
+func newPropertyAnnouncementMessage(props ...Property) *protocol.Message {
 	msg := protocol.NewMessage()
 	msg.SetESV(protocol.ESVNotification)
-	msg.SetSEOJ(prop.Object().Code())
-	msg.AddProperty(prop.ToProtocol())
-	return node.AnnounceMessage(msg)
+	msg.SetSEOJ(props[0].Object().Code())
+	for _, prop := range props {
+		msg.AddProperty(prop.ToProtocol())
+	}
+	return msg
 }
 
 // Announce announces the node.
 func (node *localNode) Announce() error {
+	msg, err := node.newInstanceListAnnouncementMessage()
+	if err != nil {
+		return err
+	}
+	return node.AnnounceMessage(msg)
+}
+
+// announceFrom announces the node from the specified bound address.
+func (node *localNode) announceFrom(addr string) error {
+	msg, err := node.newInstanceListAnnouncementMessage()
+	if err != nil {
+		return err
+	}
+	return node.announceMessageFrom(addr, msg)
+}
+
+// newInstanceListAnnouncementMessage returns a new notification message of the instance list of the node.
+func (node *localNode) newInstanceListAnnouncementMessage() (*protocol.Message, error) {
 	// 4.3.1 Basic Sequence for ECHONET Lite Node Startup
 
 	nodePropObj, err := node.NodeProfile()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	nodeProp, ok := nodePropObj.LookupProperty(NodeProfileClassInstanceListNotification)
 	if !ok {
-		return fmt.Errorf(errObjectProfileObjectNotFound, ErrNotFound)
+		return nil, fmt.Errorf(errObjectProfileObjectNotFound, ErrNotFound)
 	}
 
-	return node.AnnounceProperty(nodeProp)
+	return newPropertyAnnouncementMessage(nodeProp), nil
 }
 
-// updateMessageDestinationHeader update the message header using the local node status.
+// updateMessageDestinationHeader update the message header using the local node status, and validates the message.
 func (node *localNode) updateMessageDestinationHeader(msg *protocol.Message) error {
 	msg.SetTID(node.NextTID())
 
@@ -69,15 +121,24 @@ func (node *localNode) updateMessageDestinationHeader(msg *protocol.Message) err
 	}
 	msg.SetSEOJ(nodeProp.Code())
 
-	return err
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf(errNodeInvalidMessage, ErrInvalid, msg, err)
+	}
+
+	return nil
 }
 
 // SendMessage sends a message to the destination node.
+// The responses and SNA responses are answered only by the node itself, so that they are not sent as the outbound messages.
 func (node *localNode) SendMessage(ctx context.Context, dstNode Node, msg Message) error {
 	if !node.IsRunning() {
 		return fmt.Errorf(errNodeIsNotRunning, ErrInvalid, node)
 	}
 
+	if esv := msg.ESV(); !msg.IsFormat2() && !esv.IsRequest() && !esv.IsNotification() {
+		return fmt.Errorf(errNodeNotOutbound, ErrInvalid, esv)
+	}
+
 	err := node.updateMessageDestinationHeader(msg.ToProtocol())
 	if err != nil {
 		return err
@@ -110,19 +171,15 @@ func (node *localNode) postMessageSynchronously(dstNode Node, reqMsg *protocol.M
 
 // isResponseMessageWaiting returns true when the node is waiting the response message, otherwise false.
 func (node *localNode) isResponseMessageWaiting() bool {
-	if node.postRequestMsg == nil {
-		return false
-	}
-	if node.postResponseCh == nil {
-		return false
-	}
-	return true
+	node.postMutex.Lock()
+	defer node.postMutex.Unlock()
+	return node.postRequestMsg != nil && node.postResponseCh != nil
 }
 
-// isResponseMessage returns true when it is the response message, otherwise false.
+// isResponseMessage returns true when it is the response message, otherwise false. The caller must hold the post mutex.
 func (node *localNode) isResponseMessage(msg *protocol.Message) bool {
 	// TODO : Check the response message more strictly
-	if !node.isResponseMessageWaiting() {
+	if node.postRequestMsg == nil || node.postResponseCh == nil {
 		return false
 	}
 	if msg.Equals(node.postRequestMsg) {
@@ -131,20 +188,42 @@ func (node *localNode) isResponseMessage(msg *protocol.Message) bool {
 	if msg.TID() != node.postRequestMsg.TID() {
 		return false
 	}
+	// The notifications of other nodes might have the same TID by chance.
+	if !msg.ESV().IsResponseOf(node.postRequestMsg.ESV()) {
+		return false
+	}
 	return true
 }
 
-// setResponseMessage sets a message to the response channel.
+// setResponseMessage sets a message to the response channel when it is the response message.
+// The channel is buffered, and the duplicated responses are dropped not to block the message handler.
 func (node *localNode) setResponseMessage(msg *protocol.Message) bool {
-	if !node.isResponseMessageWaiting() {
+	node.postMutex.Lock()
+	defer node.postMutex.Unlock()
+	if !node.isResponseMessage(msg) {
 		return false
 	}
-	node.postResponseCh <- msg
-	return true
+	select {
+	case node.postResponseCh <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// openResponseChannel opens a new response channel for the specified request message.
+func (node *localNode) openResponseChannel(reqMsg *protocol.Message) chan *protocol.Message {
+	node.postMutex.Lock()
+	defer node.postMutex.Unlock()
+	node.postResponseCh = make(chan *protocol.Message, 1)
+	node.postRequestMsg = reqMsg
+	return node.postResponseCh
 }
 
 // closeResponseChannel closes the response channel.
 func (node *localNode) closeResponseChannel() {
+	node.postMutex.Lock()
+	defer node.postMutex.Unlock()
 	if node.postResponseCh == nil {
 		return
 	}
@@ -155,19 +234,28 @@ func (node *localNode) closeResponseChannel() {
 
 // PostMessage posts a message to the node, and wait the response message.
 func (node *localNode) PostMessage(ctx context.Context, dstNode Node, msg Message) (Message, error) {
+	if esv := msg.ESV(); !esv.IsResponseRequired() {
+		return nil, fmt.Errorf(errNodeNotRequest, ErrInvalid, esv)
+	}
+
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, DefaultResponseTimeout)
 		defer cancel()
 	}
 
+	startTime := time.Now()
+
 	// Use TCP connection when the function is enabled
 
 	if node.TCPEnabled() {
 		resMsg, err := node.postMessageSynchronously(dstNode, msg.ToProtocol())
 		if err == nil {
+			node.metrics.observeRequestDuration(dstNode, time.Since(startTime))
+			node.metrics.countReceivedResponse(resMsg)
 			return newMessageWithProtocolMessage(resMsg), nil
 		}
+		node.metrics.countRetry()
 	}
 
 	// Part V ECHONET Lite System Design Guidelines v1.12
@@ -180,8 +268,7 @@ func (node *localNode) PostMessage(ctx context.Context, dstNode Node, msg Messag
 
 	defer node.closeResponseChannel()
 
-	node.postResponseCh = make(chan *protocol.Message)
-	node.postRequestMsg = msg.ToProtocol()
+	resCh := node.openResponseChannel(msg.ToProtocol())
 
 	// log.Trace(logLocalNodePostMessageFormat, msg.String()))
 
@@ -192,8 +279,10 @@ func (node *localNode) PostMessage(ctx context.Context, dstNode Node, msg Messag
 
 	var resMsg *protocol.Message
 	select {
-	case resMsg = <-node.postResponseCh:
+	case resMsg = <-resCh:
+		node.metrics.observeRequestDuration(dstNode, time.Since(startTime))
 	case <-time.After(node.RequestTimeout()):
+		node.metrics.countTimeout()
 		err = fmt.Errorf(errNodeRequestTimeout, ErrTimeout, msg)
 	}
 
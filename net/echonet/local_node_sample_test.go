@@ -25,7 +25,7 @@ type testLocalNode struct {
 	LocalNode
 }
 
-func newTestSampleNodeWithConfig(config Config) (*testLocalNode, error) {
+func newTestSampleNodeWithConfig(config Config, opts ...LocalNodeOption) (*testLocalNode, error) {
 	dev, err := NewDevice(
 		WithDeviceCode(testLightDeviceCode),
 	)
@@ -40,9 +40,11 @@ func newTestSampleNodeWithConfig(config Config) (*testLocalNode, error) {
 
 	node := &testLocalNode{
 		LocalNode: NewLocalNode(
-			WithLocalNodeConfig(config),
-			WithLocalNodeDevices(dev),
-			WithLocalNodeManufacturerCode(testNodeManufacturerCode),
+			append([]LocalNodeOption{
+				WithLocalNodeConfig(config),
+				WithLocalNodeDevices(dev),
+				WithLocalNodeManufacturerCode(testNodeManufacturerCode),
+			}, opts...)...,
 		),
 	}
 	node.SetListener(node)
@@ -7,11 +7,13 @@ package echonet
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/cybergarage/go-logger/log"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
 )
 
 const (
@@ -299,3 +301,174 @@ func TestLocalNode(t *testing.T) {
 		testLocalNodeWithConfig(t, conf)
 	})
 }
+
+func TestLocalNodeInterfaceAddresses(t *testing.T) {
+	vnet := NewVirtualNetwork()
+
+	node, err := newTestSampleNodeWithConfig(newTestDefaultConfig(), WithLocalNodeTransport(vnet.NewTransport()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	ifaddrs := node.InterfaceAddresses()
+	addrs, ok := ifaddrs[transport.VirtualInterfaceName]
+	if !ok || len(addrs) != 1 {
+		t.Fatalf("%v", ifaddrs)
+	}
+	if addrs[0] != node.Address() {
+		t.Errorf("%s != %s", addrs[0], node.Address())
+	}
+	if nodeAddrs := node.Addresses(); len(nodeAddrs) != 1 || nodeAddrs[0] != node.Address() {
+		t.Errorf("%v", nodeAddrs)
+	}
+}
+
+func TestLocalNodeAddRemoveDevice(t *testing.T) {
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	dev, err := NewDevice(WithDeviceCode(testLightDeviceCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookupFoundDevice := func() bool {
+		for _, foundNode := range ctrl.Nodes() {
+			if !foundNode.Equals(node) {
+				continue
+			}
+			if _, err := foundNode.LookupDevice(testLightDeviceCode); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	checkInstanceCount := func(count uint) {
+		nodeProf, err := node.NodeProfile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		instanceCount, err := nodeProf.LookupPropertyInteger(NodeProfileClassNumberOfSelfNodeInstances)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if instanceCount != count {
+			t.Errorf("%d != %d", instanceCount, count)
+		}
+	}
+
+	// Adds a device while running
+
+	time.Sleep(testNodeRequestSleep)
+	node.AddDevice(dev)
+	checkInstanceCount(1)
+	time.Sleep(testNodeRequestSleep)
+	if !lookupFoundDevice() {
+		t.Errorf(errTestNodeNotFound, ErrNotFound, node.Address(), node.Port())
+	}
+
+	// Removes the device while running
+
+	if err := node.RemoveDevice(dev); err != nil {
+		t.Fatal(err)
+	}
+	checkInstanceCount(0)
+	time.Sleep(testNodeRequestSleep)
+	if lookupFoundDevice() {
+		t.Errorf("%s is not removed", dev.Code())
+	}
+
+	if err := node.RemoveDevice(dev); err == nil {
+		t.Errorf("%s is removed twice", dev.Code())
+	}
+}
+
+func TestLocalNodeConcurrentAddRemoveDevice(t *testing.T) {
+	vnet := NewVirtualNetwork()
+
+	ctrl := NewController(WithControllerTransport(vnet.NewTransport()))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node, err := newTestSampleNodeWithConfig(newTestDefaultConfig(), WithLocalNodeTransport(vnet.NewTransport()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	const requestCount = 20
+
+	var wg sync.WaitGroup
+
+	// Serves the requests for the sample device while the other devices are added and removed.
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range requestCount {
+			reqMsg := NewMessage(
+				WithMessageDEOJ(testLightDeviceCode),
+				WithMessageESV(protocol.ESVReadRequest),
+				WithMessageProperties(NewProperty(WithPropertyCode(testLightPropertyPowerCode))),
+			)
+			resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if resMsg.ESV() != protocol.ESVReadResponse {
+				t.Errorf("%s != %02X", resMsg.ESV(), protocol.ESVReadResponse)
+			}
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range requestCount {
+			dev, err := NewDevice(WithDeviceCode(0x029001))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := node.AddDevice(dev); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := node.LookupObject(dev.Code()); err != nil {
+				t.Error(err)
+			}
+			if err := node.RemoveDevice(dev); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if devs := node.Devices(); len(devs) != 1 {
+		t.Errorf("%d != %d", len(devs), 1)
+	}
+}
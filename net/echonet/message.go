@@ -33,6 +33,12 @@ type Message interface {
 	Properties() []Property
 	// Property returns the n-th property of the message.
 	Property(n int) (Property, bool)
+	// IsFormat2 returns true when the message is an arbitrary message format (Format 2) message, otherwise false.
+	IsFormat2() bool
+	// Format2Data returns the EDATA of the arbitrary message format (Format 2) message.
+	Format2Data() []byte
+	// Validate returns an error when the message violates the rules of ECHONET Lite frames for the ESV.
+	Validate() error
 	// messageInternal is an interface to represent a message internal.
 	messageInternal
 }
@@ -68,6 +74,14 @@ func WithMessageProperties(props ...Property) MessageOptions {
 	}
 }
 
+// WithMessageFormat2Data sets the specified EDATA, and makes the message an arbitrary message format (Format 2) message.
+// The Format 2 message has no ESV, DEOJ and properties, and is delivered to the node listeners of the destination node.
+func WithMessageFormat2Data(data []byte) MessageOptions {
+	return func(msg *message) {
+		msg.SetFormat2Data(data)
+	}
+}
+
 // NewMessage returns a new message with the specified options. The ESV, DEOJ and properties for the message should be set at least.
 // Basically, the SEOJ, OPC, and TID do not need to be set because these fields are automatically filled by the controller when the message will be sent.
 func NewMessage(opts ...MessageOptions) Message {
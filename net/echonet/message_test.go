@@ -5,9 +5,225 @@
 package echonet
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 func TestNewMessage(t *testing.T) {
 	NewMessage()
 }
+
+type testFormat2Listener struct {
+	msgs chan *protocol.Message
+}
+
+func (l *testFormat2Listener) OnMessage(msg *protocol.Message) error {
+	if msg.IsFormat2() {
+		l.msgs <- msg
+	}
+	return nil
+}
+
+func TestFormat2Message(t *testing.T) {
+	edata := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	listener := &testFormat2Listener{msgs: make(chan *protocol.Message, 1)}
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeListener(listener))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	msg := NewMessage(WithMessageFormat2Data(edata))
+	if err := ctrl.SendMessage(context.Background(), node, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case recvMsg := <-listener.msgs:
+		if !bytes.Equal(recvMsg.Format2Data(), edata) {
+			t.Errorf("%X != %X", recvMsg.Format2Data(), edata)
+		}
+		if recvMsg.TID() != msg.TID() {
+			t.Errorf("%d != %d", recvMsg.TID(), msg.TID())
+		}
+	case <-time.After(testNodeRequestTimeout):
+		t.Error("format 2 message is not received")
+	}
+}
+
+func TestFormat2MessageRestriction(t *testing.T) {
+	edata := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+	testFormat2MessageRestriction := func(t *testing.T, nodeConf Config, opts []LocalNodeOption, nMsgs int, expectedMsgs int) {
+		t.Helper()
+
+		ctrl := NewController(WithControllerConfig(newTestDefaultConfig()))
+		if err := ctrl.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer ctrl.Stop()
+
+		listener := &testFormat2Listener{msgs: make(chan *protocol.Message, nMsgs)}
+		opts = append(opts, WithLocalNodeConfig(nodeConf), WithLocalNodeListener(listener))
+		node := NewLocalNode(opts...)
+		if err := node.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer node.Stop()
+
+		for range nMsgs {
+			msg := NewMessage(WithMessageFormat2Data(edata))
+			if err := ctrl.SendMessage(context.Background(), node, msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		time.Sleep(testNodeRequestTimeout)
+
+		if len(listener.msgs) != expectedMsgs {
+			t.Errorf("%d != %d", len(listener.msgs), expectedMsgs)
+		}
+	}
+
+	t.Run("access-policy", func(t *testing.T) {
+		var mutex sync.Mutex
+		audits := 0
+		policy := NewAccessPolicy(
+			WithAccessPolicyDefaultAction(AccessDeny),
+			WithAccessPolicyAuditHandler(func(ctx RequestContext, deniedProps []PropertyCode) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				audits++
+			}),
+		)
+		testFormat2MessageRestriction(t, newTestDefaultConfig(), []LocalNodeOption{WithLocalNodeAccessPolicy(policy)}, 1, 0)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if audits != 1 {
+			t.Errorf("%d != %d", audits, 1)
+		}
+	})
+
+	t.Run("rate-limit", func(t *testing.T) {
+		nodeConf := newTestDefaultConfig()
+		nodeConf.(*config).SetSourceRateLimit(RateLimit{Rate: 0.001, Burst: 1})
+		testFormat2MessageRestriction(t, nodeConf, []LocalNodeOption{}, 3, 1)
+	})
+}
+
+func TestMessageValidation(t *testing.T) {
+	dev, err := NewDevice(WithDeviceCode(testLightDeviceCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeDevices(dev))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{
+			"read request with EDT",
+			NewMessage(
+				WithMessageDEOJ(testLightDeviceCode),
+				WithMessageESV(protocol.ESVReadRequest),
+				WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOn}))),
+			),
+		},
+		{
+			"write request without EDT",
+			NewMessage(
+				WithMessageDEOJ(testLightDeviceCode),
+				WithMessageESV(protocol.ESVWriteRequestResponseRequired),
+				WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus))),
+			),
+		},
+		{
+			"response as request",
+			NewMessage(
+				WithMessageDEOJ(testLightDeviceCode),
+				WithMessageESV(protocol.ESVWriteResponse),
+				WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus))),
+			),
+		},
+		{
+			"unknown DEOJ",
+			NewMessage(
+				WithMessageDEOJ(0x000000),
+				WithMessageESV(protocol.ESVReadRequest),
+				WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus))),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ctrl.PostMessage(context.Background(), node, test.msg); !errors.Is(err, ErrInvalid) {
+				t.Errorf("%s is posted (%v)", test.msg, err)
+			}
+		})
+	}
+
+	if err := ctrl.SendMessage(context.Background(), node, tests[0].msg); !errors.Is(err, ErrInvalid) || !errors.Is(err, protocol.ErrInvalid) {
+		t.Errorf("%s is sent (%v)", tests[0].msg, err)
+	}
+
+	// The responses and SNA responses are rejected, but the notifications are sent as the outbound messages.
+
+	esvTests := []struct {
+		esv  protocol.ESV
+		sent bool
+	}{
+		{protocol.ESVReadResponse, false},
+		{protocol.ESVWriteResponse, false},
+		{protocol.ESVNotificationResponse, false},
+		{protocol.ESVWriteReadResponse, false},
+		{protocol.ESVReadRequestError, false},
+		{protocol.ESVWriteRequestResponseRequiredError, false},
+		{protocol.ESVNotification, true},
+		{protocol.ESVNotificationResponseRequired, true},
+	}
+
+	for _, test := range esvTests {
+		msg := NewMessage(
+			WithMessageDEOJ(testLightDeviceCode),
+			WithMessageESV(test.esv),
+			WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOn}))),
+		)
+		err := ctrl.SendMessage(context.Background(), node, msg)
+		if test.sent && err != nil {
+			t.Errorf("%s is not sent (%v)", test.esv, err)
+		}
+		if !test.sent && !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s is sent (%v)", test.esv, err)
+		}
+	}
+}
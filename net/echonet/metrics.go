@@ -0,0 +1,217 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"container/list"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+	"github.com/cybergarage/uecho-go/net/echonet/transport"
+)
+
+// SocketType represents a type of the sockets which the traffic counters are counted for.
+type SocketType = transport.SocketType
+
+const (
+	SocketMulticast = transport.SocketMulticast
+	SocketUDP       = transport.SocketUDP
+	SocketTCP       = transport.SocketTCP
+)
+
+// SocketMetrics represents the traffic counters of a socket type.
+type SocketMetrics = transport.SocketMetrics
+
+const (
+	// metricsMaxRequestDurationNodes is the maximum number of the destination nodes to track the request latency histograms.
+	// The histogram of the least recently answered node is removed when a new node exceeds the number.
+	metricsMaxRequestDurationNodes = 1024
+)
+
+// DefaultRequestDurationBuckets are the default upper bounds in seconds of the request round-trip latency histograms.
+var DefaultRequestDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Histogram represents a snapshot of a latency histogram.
+// Counts are the cumulative numbers of the observations which are less than or equal to the upper bounds of Buckets.
+type Histogram struct {
+	Buckets []float64
+	Counts  []uint64
+	Sum     float64
+	Count   uint64
+}
+
+// Metrics represents a snapshot of the counters and histograms of a node.
+type Metrics struct {
+	// Sockets are the traffic counters for each socket type.
+	Sockets map[SocketType]SocketMetrics
+	// SNAResponsesIn are the numbers of the received SNA responses for each ESV.
+	SNAResponsesIn map[protocol.ESV]uint64
+	// SNAResponsesOut are the numbers of the sent SNA responses for each ESV.
+	SNAResponsesOut map[protocol.ESV]uint64
+	// RequestDurations are the round-trip latency histograms of the answered requests for each destination node address.
+	// Only the recently answered nodes are tracked, so that the histograms do not grow with the short-lived nodes.
+	RequestDurations map[string]*Histogram
+	// Retries is the number of the requests which are sent again by UDP unicast after the TCP connection failures.
+	Retries uint64
+	// Timeouts is the number of the requests which are not answered within the request timeout.
+	Timeouts uint64
+	// HandlerErrors is the number of the errors which are returned by the listeners and property handlers.
+	HandlerErrors uint64
+}
+
+// MetricsSource is an interface to get the metrics snapshot such as LocalNode and Controller.
+type MetricsSource interface {
+	Metrics() *Metrics
+}
+
+// histogram represents a latency histogram of a destination node.
+type histogram struct {
+	node   string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// nodeMetrics represents the goroutine-safe counters and histograms of a node.
+type nodeMetrics struct {
+	sync.Mutex
+	snaIn            map[protocol.ESV]uint64
+	snaOut           map[protocol.ESV]uint64
+	requestDurations map[string]*list.Element
+	requestNodeList  *list.List
+	retries          uint64
+	timeouts         uint64
+	handlerErrors    uint64
+}
+
+// newNodeMetrics returns new zero counters.
+func newNodeMetrics() *nodeMetrics {
+	return &nodeMetrics{
+		Mutex:            sync.Mutex{},
+		snaIn:            map[protocol.ESV]uint64{},
+		snaOut:           map[protocol.ESV]uint64{},
+		requestDurations: map[string]*list.Element{},
+		requestNodeList:  list.New(),
+		retries:          0,
+		timeouts:         0,
+		handlerErrors:    0,
+	}
+}
+
+// countReceivedResponse counts the specified received message when it is a SNA response.
+func (m *nodeMetrics) countReceivedResponse(msg *protocol.Message) {
+	if msg == nil || !msg.ESV().IsImpossibleResponse() {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.snaIn[msg.ESV()]++
+}
+
+// countSentResponse counts the specified sent message when it is a SNA response.
+func (m *nodeMetrics) countSentResponse(msg *protocol.Message) {
+	if msg == nil || !msg.ESV().IsImpossibleResponse() {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.snaOut[msg.ESV()]++
+}
+
+// observeRequestDuration observes the round-trip latency of the request which is answered by the specified node.
+func (m *nodeMetrics) observeRequestDuration(dstNode Node, d time.Duration) {
+	key := net.JoinHostPort(dstNode.Address(), strconv.Itoa(dstNode.Port()))
+	secs := d.Seconds()
+
+	m.Lock()
+	defer m.Unlock()
+	h := m.requestDurationHistogram(key)
+	for n, bound := range DefaultRequestDurationBuckets {
+		if secs <= bound {
+			h.counts[n]++
+		}
+	}
+	h.sum += secs
+	h.count++
+}
+
+// requestDurationHistogram returns the histogram of the specified node, and adds a new histogram removing the least recently answered node if needed.
+func (m *nodeMetrics) requestDurationHistogram(node string) *histogram {
+	if elem, ok := m.requestDurations[node]; ok {
+		m.requestNodeList.MoveToFront(elem)
+		return elem.Value.(*histogram)
+	}
+	for metricsMaxRequestDurationNodes <= m.requestNodeList.Len() {
+		oldest := m.requestNodeList.Back()
+		m.requestNodeList.Remove(oldest)
+		delete(m.requestDurations, oldest.Value.(*histogram).node)
+	}
+	h := &histogram{
+		node:   node,
+		counts: make([]uint64, len(DefaultRequestDurationBuckets)),
+		sum:    0,
+		count:  0,
+	}
+	m.requestDurations[node] = m.requestNodeList.PushFront(h)
+	return h
+}
+
+// countRetry counts a request which is sent again by UDP unicast.
+func (m *nodeMetrics) countRetry() {
+	m.Lock()
+	defer m.Unlock()
+	m.retries++
+}
+
+// countTimeout counts a request which is not answered within the request timeout.
+func (m *nodeMetrics) countTimeout() {
+	m.Lock()
+	defer m.Unlock()
+	m.timeouts++
+}
+
+// countHandlerError counts the specified error of the listeners and property handlers when it is not nil.
+func (m *nodeMetrics) countHandlerError(err error) {
+	if err == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.handlerErrors++
+}
+
+// Snapshot returns the current counters and histograms with the specified traffic counters of the transport.
+func (m *nodeMetrics) Snapshot(tm *transport.Metrics) *Metrics {
+	m.Lock()
+	defer m.Unlock()
+
+	metrics := &Metrics{
+		Sockets:          map[SocketType]SocketMetrics{},
+		SNAResponsesIn:   maps.Clone(m.snaIn),
+		SNAResponsesOut:  maps.Clone(m.snaOut),
+		RequestDurations: map[string]*Histogram{},
+		Retries:          m.retries,
+		Timeouts:         m.timeouts,
+		HandlerErrors:    m.handlerErrors,
+	}
+	if tm != nil {
+		metrics.Sockets = maps.Clone(tm.Sockets)
+	}
+	for elem := m.requestNodeList.Front(); elem != nil; elem = elem.Next() {
+		h := elem.Value.(*histogram)
+		metrics.RequestDurations[h.node] = &Histogram{
+			Buckets: slices.Clone(DefaultRequestDurationBuckets),
+			Counts:  slices.Clone(h.counts),
+			Sum:     h.sum,
+			Count:   h.count,
+		}
+	}
+	return metrics
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+const (
+	// MetricsContentType is the content type of the Prometheus text exposition format.
+	MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// WritePrometheus writes the metrics to the specified writer in the Prometheus text exposition format.
+func (metrics *Metrics) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	writeHeader := func(name string, typ string, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	socketTypes := []SocketType{SocketMulticast, SocketUDP, SocketTCP}
+
+	writeHeader("echonet_packets_total", "counter", "Number of the sent and received message frames.")
+	for _, t := range socketTypes {
+		m := metrics.Sockets[t]
+		fmt.Fprintf(bw, "echonet_packets_total{socket=%q,direction=\"in\"} %d\n", t.String(), m.PacketsIn)
+		fmt.Fprintf(bw, "echonet_packets_total{socket=%q,direction=\"out\"} %d\n", t.String(), m.PacketsOut)
+	}
+
+	writeHeader("echonet_bytes_total", "counter", "Number of the sent and received bytes.")
+	for _, t := range socketTypes {
+		m := metrics.Sockets[t]
+		fmt.Fprintf(bw, "echonet_bytes_total{socket=%q,direction=\"in\"} %d\n", t.String(), m.BytesIn)
+		fmt.Fprintf(bw, "echonet_bytes_total{socket=%q,direction=\"out\"} %d\n", t.String(), m.BytesOut)
+	}
+
+	writeHeader("echonet_parse_errors_total", "counter", "Number of the received message frames which could not be parsed.")
+	for _, t := range socketTypes {
+		fmt.Fprintf(bw, "echonet_parse_errors_total{socket=%q} %d\n", t.String(), metrics.Sockets[t].ParseErrors)
+	}
+
+	writeHeader("echonet_sna_responses_total", "counter", "Number of the sent and received SNA responses.")
+	for _, esv := range sortedKeys(metrics.SNAResponsesIn) {
+		fmt.Fprintf(bw, "echonet_sna_responses_total{esv=%q,direction=\"in\"} %d\n", esv.String(), metrics.SNAResponsesIn[esv])
+	}
+	for _, esv := range sortedKeys(metrics.SNAResponsesOut) {
+		fmt.Fprintf(bw, "echonet_sna_responses_total{esv=%q,direction=\"out\"} %d\n", esv.String(), metrics.SNAResponsesOut[esv])
+	}
+
+	writeHeader("echonet_request_duration_seconds", "histogram", "Round-trip latency of the answered requests.")
+	for _, node := range sortedKeys(metrics.RequestDurations) {
+		h := metrics.RequestDurations[node]
+		for n, bound := range h.Buckets {
+			fmt.Fprintf(bw, "echonet_request_duration_seconds_bucket{node=%q,le=%q} %d\n", node, strconv.FormatFloat(bound, 'g', -1, 64), h.Counts[n])
+		}
+		fmt.Fprintf(bw, "echonet_request_duration_seconds_bucket{node=%q,le=\"+Inf\"} %d\n", node, h.Count)
+		fmt.Fprintf(bw, "echonet_request_duration_seconds_sum{node=%q} %s\n", node, strconv.FormatFloat(h.Sum, 'g', -1, 64))
+		fmt.Fprintf(bw, "echonet_request_duration_seconds_count{node=%q} %d\n", node, h.Count)
+	}
+
+	writeHeader("echonet_request_retries_total", "counter", "Number of the requests which are sent again by UDP unicast after the TCP connection failures.")
+	fmt.Fprintf(bw, "echonet_request_retries_total %d\n", metrics.Retries)
+
+	writeHeader("echonet_request_timeouts_total", "counter", "Number of the requests which are not answered within the request timeout.")
+	fmt.Fprintf(bw, "echonet_request_timeouts_total %d\n", metrics.Timeouts)
+
+	writeHeader("echonet_handler_errors_total", "counter", "Number of the errors which are returned by the listeners and property handlers.")
+	fmt.Fprintf(bw, "echonet_handler_errors_total %d\n", metrics.HandlerErrors)
+
+	return bw.Flush()
+}
+
+// NewMetricsHandler returns a HTTP handler which serves the metrics of the specified source in the Prometheus text exposition format.
+func NewMetricsHandler(src MetricsSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MetricsContentType)
+		if err := src.Metrics().WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// sortedKeys returns the sorted keys of the specified map to write the metrics in a stable order.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestNodeMetricsHistogram(t *testing.T) {
+	m := newNodeMetrics()
+	node := newLocalNode()
+	m.observeRequestDuration(node, time.Millisecond*20)
+	m.observeRequestDuration(node, time.Second*20)
+
+	metrics := m.Snapshot(nil)
+	key := net.JoinHostPort(node.Address(), strconv.Itoa(node.Port()))
+	h, ok := metrics.RequestDurations[key]
+	if !ok {
+		t.Fatalf("%s is not found", key)
+	}
+	if h.Count != 2 {
+		t.Errorf("%d != %d", h.Count, 2)
+	}
+	for n, bound := range h.Buckets {
+		expected := uint64(0)
+		if 0.02 <= bound {
+			expected = 1
+		}
+		if h.Counts[n] != expected {
+			t.Errorf("%v : %d != %d", bound, h.Counts[n], expected)
+		}
+	}
+}
+
+func TestNodeMetricsHistogramEviction(t *testing.T) {
+	newTestNode := func(addr string) Node {
+		node := newRemoteNode()
+		node.SetAddress(addr)
+		node.SetPort(3610)
+		return node
+	}
+
+	m := newNodeMetrics()
+	m.observeRequestDuration(newTestNode("192.168.1.10"), time.Millisecond)
+	for n := range metricsMaxRequestDurationNodes {
+		m.observeRequestDuration(newTestNode(fmt.Sprintf("10.0.%d.%d", n/256, n%256)), time.Millisecond)
+	}
+
+	// The histogram of the least recently answered node is removed.
+
+	metrics := m.Snapshot(nil)
+	if n := len(metrics.RequestDurations); n != metricsMaxRequestDurationNodes {
+		t.Errorf("%d != %d", n, metricsMaxRequestDurationNodes)
+	}
+	if _, ok := metrics.RequestDurations["192.168.1.10:3610"]; ok {
+		t.Errorf("%s is not removed", "192.168.1.10:3610")
+	}
+	if _, ok := metrics.RequestDurations["10.0.0.0:3610"]; !ok {
+		t.Errorf("%s is removed", "10.0.0.0:3610")
+	}
+}
+
+func TestLocalNodeMetrics(t *testing.T) {
+	vnet := NewVirtualNetwork()
+
+	conf := NewDefaultConfig()
+	conf.TransportConfig().SetRequestTimeout(time.Millisecond * 100)
+
+	ctrl := NewController(
+		WithControllerConfig(conf),
+		WithControllerTransport(vnet.NewTransport()),
+	)
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node, err := newTestSampleNodeWithConfig(conf, WithLocalNodeTransport(vnet.NewTransport()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	// Get
+
+	reqMsg := NewMessage(
+		WithMessageDEOJ(testLightDeviceCode),
+		WithMessageESV(protocol.ESVReadRequest),
+		WithMessageProperties(NewProperty(WithPropertyCode(testLightPropertyPowerCode))),
+	)
+	if _, err := ctrl.PostMessage(context.Background(), node, reqMsg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Get_SNA
+
+	reqMsg = NewMessage(
+		WithMessageDEOJ(testLightDeviceCode),
+		WithMessageESV(protocol.ESVReadRequest),
+		WithMessageProperties(NewProperty(WithPropertyCode(0xFF))),
+	)
+	resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resMsg.ESV() != protocol.ESVReadRequestError {
+		t.Errorf("%s != %02X", resMsg.ESV(), protocol.ESVReadRequestError)
+	}
+
+	// Timeout by the transport without any handler
+
+	silentTransport := vnet.NewTransport()
+	if err := silentTransport.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer silentTransport.Stop()
+	silentNode := newRemoteNode()
+	silentNode.SetAddress(silentTransport.Address())
+	silentNode.SetPort(silentTransport.Port())
+	if _, err := ctrl.PostMessage(context.Background(), silentNode, reqMsg); !errors.Is(err, ErrTimeout) {
+		t.Errorf("request is not timed out (%v)", err)
+	}
+
+	ctrlMetrics := ctrl.Metrics()
+	if m := ctrlMetrics.Sockets[SocketUDP]; m.PacketsOut < 3 || m.PacketsIn < 2 {
+		t.Errorf("%+v", m)
+	}
+	if n := ctrlMetrics.SNAResponsesIn[protocol.ESVReadRequestError]; n != 1 {
+		t.Errorf("%d != %d", n, 1)
+	}
+	nodeKey := net.JoinHostPort(node.Address(), strconv.Itoa(node.Port()))
+	if h, ok := ctrlMetrics.RequestDurations[nodeKey]; !ok || h.Count != 2 {
+		t.Errorf("%s : %v", nodeKey, h)
+	}
+	if ctrlMetrics.Timeouts != 1 {
+		t.Errorf("%d != %d", ctrlMetrics.Timeouts, 1)
+	}
+	if ctrlMetrics.Retries != 0 {
+		t.Errorf("%d != %d", ctrlMetrics.Retries, 0)
+	}
+
+	nodeMetrics := node.Metrics()
+	if n := nodeMetrics.SNAResponsesOut[protocol.ESVReadRequestError]; n != 1 {
+		t.Errorf("%d != %d", n, 1)
+	}
+
+	t.Run("Prometheus", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := ctrlMetrics.WritePrometheus(&buf); err != nil {
+			t.Fatal(err)
+		}
+		expectedLines := []string{
+			"# TYPE echonet_packets_total counter",
+			"echonet_sna_responses_total{esv=\"52\",direction=\"in\"} 1",
+			"echonet_request_duration_seconds_count{node=\"" + nodeKey + "\"} 2",
+			"echonet_request_duration_seconds_bucket{node=\"" + nodeKey + "\",le=\"+Inf\"} 2",
+			"echonet_request_timeouts_total 1",
+		}
+		for _, line := range expectedLines {
+			if !strings.Contains(buf.String(), line+"\n") {
+				t.Errorf("%s is not found", line)
+			}
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewMetricsHandler(ctrl).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%d != %d", rec.Code, http.StatusOK)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != MetricsContentType {
+			t.Errorf("%s != %s", ct, MetricsContentType)
+		}
+		if !strings.Contains(rec.Body.String(), "echonet_handler_errors_total") {
+			t.Errorf("%s", rec.Body.String())
+		}
+	})
+}
@@ -13,5 +13,6 @@ type NodeListener interface {
 	// OnMessage is called when a message is received.
 	// The node returns the standard responses of Echonet when the listener function returns no error.
 	// Otherwise, the node does not return any responses when the listener function returns an error.
+	// The arbitrary message format (Format 2) messages are delivered only to the listener, and the node never returns any responses for them.
 	OnMessage(*protocol.Message) error
 }
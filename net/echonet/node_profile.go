@@ -5,6 +5,8 @@
 package echonet
 
 import (
+	"fmt"
+
 	"github.com/cybergarage/uecho-go/net/echonet/encoding"
 )
 
@@ -108,20 +110,38 @@ func (prof *nodeProfile) SetVersion(major int, minor int) error {
 	return prof.SetPropertyData(NodeProfileClassVersionInformation, verBytes)
 }
 
-// SetID sets a ID to the profile.
+// SetID sets a ID with the specified manufacture code to the profile.
+// The node ID of the current identification number is kept, or the node ID is filled with zeros when the profile has no identification number.
 func (prof *nodeProfile) SetID(manufactureCode uint) error {
-	manufactureCodeBytes := make([]byte, NodeProfileClassIdentificationManufacturerCodeSize)
-	encoding.IntegerToByte(manufactureCode, manufactureCodeBytes)
+	nodeID := make([]byte, IdentificationNodeIDSize)
+	if idBytes, err := prof.LookupPropertyData(NodeProfileClassIdentificationNumber); err == nil && len(idBytes) == NodeProfileClassIdentificationNumberSize {
+		offset := 1 + NodeProfileClassIdentificationManufacturerCodeSize
+		copy(nodeID, idBytes[offset:offset+IdentificationNodeIDSize])
+	}
+	return prof.SetIDWithNodeID(manufactureCode, nodeID)
+}
 
-	// TODO : Set a unique number
-	uniqID := make([]byte, NodeProfileClassIdentificationUniqueIDSize)
+// SetIDWithNodeID sets a ID with the specified manufacture code and node ID to the profile.
+func (prof *nodeProfile) SetIDWithNodeID(manufactureCode uint, nodeID []byte) error {
+	return prof.SetIdentificationNumber(manufactureCode, nodeID)
+}
 
-	return prof.SetPropertyData(NodeProfileClassIdentificationNumber, append(manufactureCodeBytes, uniqID...))
+// setPropertyData sets a specified data to the property without the autonomous notification.
+// The node announces the instance list notification by itself when the instance list is changed.
+func (prof *nodeProfile) setPropertyData(propCode PropertyCode, propData []byte) error {
+	prop, ok := prof.LookupProperty(propCode)
+	if !ok {
+		return fmt.Errorf(errPropertyNotFound, ErrNotFound, uint(propCode))
+	}
+	prop.setData(propData)
+	return nil
 }
 
 // SetInstanceCount sets a instance count in a node.
 func (prof *nodeProfile) SetInstanceCount(count uint) error {
-	return prof.SetPropertyInteger(NodeProfileClassNumberOfSelfNodeInstances, count, NodeProfileClassNumberOfSelfNodeInstancesSize)
+	countBytes := make([]byte, NodeProfileClassNumberOfSelfNodeInstancesSize)
+	encoding.IntegerToByte(count, countBytes)
+	return prof.setPropertyData(NodeProfileClassNumberOfSelfNodeInstances, countBytes)
 }
 
 // SetInstanceList sets a instance list in a node.
@@ -137,12 +157,12 @@ func (prof *nodeProfile) SetInstanceList(devices []Device) error {
 		instanceList = append(instanceList, dev.Codes()...)
 	}
 
-	err := prof.SetPropertyData(NodeProfileClassInstanceListNotification, instanceList)
+	err := prof.setPropertyData(NodeProfileClassInstanceListNotification, instanceList)
 	if err != nil {
 		return err
 	}
 
-	err = prof.SetPropertyData(NodeProfileClassSelfNodeInstanceListS, instanceList)
+	err = prof.setPropertyData(NodeProfileClassSelfNodeInstanceListS, instanceList)
 	if err != nil {
 		return err
 	}
@@ -152,7 +172,9 @@ func (prof *nodeProfile) SetInstanceList(devices []Device) error {
 
 // SetClassCount sets a class count in a node.
 func (prof *nodeProfile) SetClassCount(count uint) error {
-	return prof.SetPropertyInteger(NodeProfileClassNumberOfSelfNodeClasses, count, NodeProfileClassNumberOfSelfNodeClassesSize)
+	countBytes := make([]byte, NodeProfileClassNumberOfSelfNodeClassesSize)
+	encoding.IntegerToByte(count, countBytes)
+	return prof.setPropertyData(NodeProfileClassNumberOfSelfNodeClasses, countBytes)
 }
 
 // SetClassList sets a class list in a node.
@@ -167,5 +189,5 @@ func (prof *nodeProfile) SetClassList(classes []Class) error {
 	for _, class := range classes {
 		classList = append(classList, class.Bytes()...)
 	}
-	return prof.SetPropertyData(NodeProfileClassSelfNodeClassListS, classList)
+	return prof.setPropertyData(NodeProfileClassSelfNodeClassListS, classList)
 }
@@ -40,3 +40,36 @@ func TestNewNodeProfile(t *testing.T) {
 
 	testObjectPropertyMaps(t, prof)
 }
+
+func TestNodeProfileID(t *testing.T) {
+	prof, ok := NewNodeProfile().(*nodeProfile)
+	if !ok {
+		t.Fatalf("%T", prof)
+	}
+
+	nodeID := make([]byte, IdentificationNodeIDSize)
+	for n := range nodeID {
+		nodeID[n] = byte(n + 1)
+	}
+	if err := prof.SetIDWithNodeID(0x000005, nodeID); err != nil {
+		t.Fatal(err)
+	}
+
+	// SetID updates the manufacture code, and keeps the node ID.
+
+	if err := prof.SetID(0x000006); err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := newIdentificationNumber(0x000006, nodeID, prof.Code())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idBytes, err := prof.LookupPropertyData(NodeProfileClassIdentificationNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(idBytes) != string(expected) {
+		t.Errorf("%X != %X", idBytes, expected)
+	}
+}
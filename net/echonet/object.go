@@ -91,12 +91,16 @@ type ObjectHelper interface {
 	SetPropertyByte(propCode PropertyCode, propData byte) error
 	// SetPropertyInteger sets a integer to the existing property.
 	SetPropertyInteger(propCode PropertyCode, propData uint, propSize uint) error
+	// SetPropertiesData sets the data to the existing properties atomically.
+	SetPropertiesData(propData map[PropertyCode][]byte) error
+	// LookupPropertiesData returns the specified properties data atomically in the object.
+	LookupPropertiesData(propCodes ...PropertyCode) ([][]byte, error)
 }
 
 // objectInternal is an interface for internal use of the object.
 type objectInternal interface {
 	// notifyPropertyRequest notifies a request to the object listener.
-	notifyPropertyRequest(esv protocol.ESV, prop protocol.Property) error
+	notifyPropertyRequest(ctx RequestContext, esv protocol.ESV, prop protocol.Property) error
 }
 
 type object struct {
@@ -243,13 +247,13 @@ func (obj *object) SetRequestHandler(h ObjectRequestHandler) {
 }
 
 // notifyPropertyRequest notifies a request to the object listener.
-func (obj *object) notifyPropertyRequest(esv protocol.ESV, prop protocol.Property) error {
+func (obj *object) notifyPropertyRequest(ctx RequestContext, esv protocol.ESV, prop protocol.Property) error {
 	var err error
 	if obj.listener != nil {
-		err = errors.Join(obj.listener.OnRequest(obj, esv, prop))
+		err = errors.Join(obj.listener.OnRequest(ctx, obj, esv, prop))
 	}
 	if obj.reqHandler != nil {
-		err = errors.Join(err, obj.reqHandler(obj, esv, prop))
+		err = errors.Join(err, obj.reqHandler(ctx, obj, esv, prop))
 	}
 	return err
 }
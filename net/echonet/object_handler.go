@@ -11,12 +11,13 @@ import (
 // ObjectRequestHandler is called when a property request is received.
 // The node returns the standard responses of Echonet when the listener function returns no error.
 // Otherwise, the node does not return any responses when the listener function returns an error.
-type ObjectRequestHandler func(obj Object, esv protocol.ESV, prop protocol.Property) error
+// The request context carries the source node, the whole request message and the transport details of the request.
+type ObjectRequestHandler func(ctx RequestContext, obj Object, esv protocol.ESV, prop protocol.Property) error
 
 // ObjectHandler is an interface for Echonet requests.
 type ObjectHandler interface {
 	// OnRequest is called when a property request is received.
 	// The node returns the standard responses of Echonet when the listener function returns no error.
 	// Otherwise, the node does not return any responses when the listener function returns an error.
-	OnRequest(obj Object, esv protocol.ESV, prop protocol.Property) error
+	OnRequest(ctx RequestContext, obj Object, esv protocol.ESV, prop protocol.Property) error
 }
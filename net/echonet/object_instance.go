@@ -0,0 +1,95 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"fmt"
+)
+
+const (
+	// ObjectInstanceCodeAll is the instance code to specify all instances of the class.
+	ObjectInstanceCodeAll = 0x00
+	// ObjectInstanceCodeMin is the minimum instance code of the object.
+	ObjectInstanceCodeMin = 0x01
+	// ObjectInstanceCodeMax is the maximum instance code of the object.
+	ObjectInstanceCodeMax = 0x7F
+)
+
+// InstanceCodePolicy represents a policy for the instance code collision of the added devices.
+type InstanceCodePolicy int
+
+const (
+	// InstanceCodePolicyAllocate assigns the next free instance code to the added device when the instance code is collided or invalid.
+	InstanceCodePolicyAllocate InstanceCodePolicy = iota
+	// InstanceCodePolicyReject rejects the added device when the instance code is collided or invalid.
+	InstanceCodePolicyReject
+)
+
+const (
+	errObjectDuplicated          = "%w: object (%s) is already added"
+	errObjectInvalidInstanceCode = "%w: instance code (%s)"
+	errObjectNoFreeInstanceCode  = "%w: no free instance code (%s)"
+)
+
+// String returns the string representation of the policy.
+func (policy InstanceCodePolicy) String() string {
+	switch policy {
+	case InstanceCodePolicyAllocate:
+		return "allocate"
+	case InstanceCodePolicyReject:
+		return "reject"
+	}
+	return ""
+}
+
+// isValidInstanceCode returns true when the specified instance code is a valid instance code for a device, otherwise false.
+func isValidInstanceCode(code byte) bool {
+	return ObjectInstanceCodeMin <= code && code <= ObjectInstanceCodeMax
+}
+
+// isAllInstanceObjectCode returns true when the specified object code specifies all instances of the class, otherwise false.
+func isAllInstanceObjectCode(code ObjectCode) bool {
+	return code.Bytes()[2] == ObjectInstanceCodeAll
+}
+
+// allocateInstanceCode checks the instance code of the specified device with the specified devices, and assigns the next free instance code
+// to the device when the policy is InstanceCodePolicyAllocate and the instance code is collided or invalid.
+func allocateInstanceCode(dev Device, devs []Device, policy InstanceCodePolicy) error {
+	isUsedInstanceCode := func(code byte) bool {
+		for _, other := range devs {
+			if other.ClassGroupCode() != dev.ClassGroupCode() || other.ClassCode() != dev.ClassCode() {
+				continue
+			}
+			if other.InstanceCode() == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	instanceCode := dev.InstanceCode()
+	isValid := isValidInstanceCode(instanceCode)
+	isUsed := isUsedInstanceCode(instanceCode)
+	if isValid && !isUsed {
+		return nil
+	}
+
+	if policy == InstanceCodePolicyReject {
+		if !isValid {
+			return fmt.Errorf(errObjectInvalidInstanceCode, ErrInvalid, dev.Code())
+		}
+		return fmt.Errorf(errObjectDuplicated, ErrDuplicated, dev.Code())
+	}
+
+	for code := byte(ObjectInstanceCodeMin); code <= ObjectInstanceCodeMax; code++ {
+		if isUsedInstanceCode(code) {
+			continue
+		}
+		dev.SetInstanceCode(code)
+		return nil
+	}
+
+	return fmt.Errorf(errObjectNoFreeInstanceCode, ErrInvalid, dev.Code())
+}
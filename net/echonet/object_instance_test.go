@@ -0,0 +1,149 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestLocalNodeInstanceCodePolicy(t *testing.T) {
+	newTestDevices := func(t *testing.T, codes ...ObjectCode) []Device {
+		t.Helper()
+		devs := make([]Device, 0, len(codes))
+		for _, code := range codes {
+			dev, err := NewDevice(WithDeviceCode(code))
+			if err != nil {
+				t.Fatal(err)
+			}
+			devs = append(devs, dev)
+		}
+		return devs
+	}
+
+	t.Run("allocate", func(t *testing.T) {
+		node := NewLocalNode()
+		devs := newTestDevices(t, 0x029101, 0x029101, 0x029100, 0x029101)
+		for _, dev := range devs {
+			if err := node.AddDevice(dev); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for n, dev := range devs {
+			if dev.InstanceCode() != byte(n+1) {
+				t.Errorf("%s != %02X", dev.Code(), n+1)
+			}
+			if _, err := node.LookupDevice(dev.Code()); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		node := NewLocalNode(WithLocalNodeConfig(NewDefaultConfig(WithConfigInstanceCodePolicy(InstanceCodePolicyReject))))
+		devs := newTestDevices(t, 0x029101, 0x029101, 0x029100, 0x029180)
+		if err := node.AddDevice(devs[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err := node.AddDevice(devs[1]); !errors.Is(err, ErrDuplicated) {
+			t.Errorf("%s is added (%v)", devs[1].Code(), err)
+		}
+		for _, dev := range devs[2:] {
+			if err := node.AddDevice(dev); !errors.Is(err, ErrInvalid) {
+				t.Errorf("%s is added (%v)", dev.Code(), err)
+			}
+		}
+		if n := len(node.Devices()); n != 1 {
+			t.Errorf("%d != %d", n, 1)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		node := NewLocalNode()
+		for range ObjectInstanceCodeMax {
+			if err := node.AddDevice(newTestDevices(t, 0x029100)[0]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := node.AddDevice(newTestDevices(t, 0x029100)[0]); err == nil {
+			t.Error("no free instance code is allocated")
+		}
+	})
+}
+
+type testAllInstanceController struct {
+	sync.Mutex
+	responses map[ObjectCode]bool
+}
+
+func (ctrl *testAllInstanceController) ControllerMessageReceived(msg *protocol.Message) {
+	if msg.ESV() != protocol.ESVReadResponse {
+		return
+	}
+	ctrl.Lock()
+	defer ctrl.Unlock()
+	ctrl.responses[msg.SEOJ()] = true
+}
+
+func (ctrl *testAllInstanceController) ControllerNewNodeFound(Node) {
+}
+
+func TestLocalNodeAllInstanceRequest(t *testing.T) {
+	conf := newTestDefaultConfig()
+
+	listener := &testAllInstanceController{
+		Mutex:     sync.Mutex{},
+		responses: map[ObjectCode]bool{},
+	}
+	ctrl := NewController(WithControllerConfig(conf))
+	ctrl.SetListener(listener)
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf))
+	devCodes := []ObjectCode{0x029101, 0x029102, 0x013001}
+	for _, code := range devCodes {
+		dev, err := NewDevice(WithDeviceCode(code))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := node.AddDevice(dev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	msg := NewMessage(
+		WithMessageDEOJ(0x029100),
+		WithMessageESV(protocol.ESVReadRequest),
+		WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus))),
+	)
+	if err := ctrl.SendMessage(context.Background(), node, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(testNodeRequestSleep)
+
+	listener.Lock()
+	defer listener.Unlock()
+	for _, code := range devCodes[:2] {
+		if !listener.responses[code] {
+			t.Errorf("%s is not responding", code)
+		}
+	}
+	if listener.responses[devCodes[2]] {
+		t.Errorf("%s is responding", devCodes[2])
+	}
+}
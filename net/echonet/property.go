@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sync"
 
 	"github.com/cybergarage/uecho-go/net/echonet/encoding"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
@@ -66,12 +67,26 @@ type Property interface {
 	IsWriteOnly() bool
 	// IsAvailableService returns true whether the specified service can execute, otherwise false.
 	IsAvailableService(esv protocol.ESV) bool
+	// DataType returns the standard data type of the property such as "state" and "number".
+	DataType() string
+	// Capacity returns the standard data size of the property, or zero when the size is variable or unknown.
+	Capacity() int
+	// Enums returns the standard enumerated values of the property data, or nil when the property has no enumeration.
+	Enums() [][]byte
 	// Size return the property data size.
 	Size() int
 	// SetData sets a specified data to the property.
 	SetData(data []byte) Property
 	// Data returns the property data.
 	Data() []byte
+	// SetReadHandler sets a handler to provide the property data for read requests.
+	SetReadHandler(h PropertyReadHandler) Property
+	// ReadHandler returns the handler to provide the property data for read requests.
+	ReadHandler() PropertyReadHandler
+	// SetWriteHandler sets a handler to accept the property data of write requests.
+	SetWriteHandler(h PropertyWriteHandler) Property
+	// WriteHandler returns the handler to accept the property data of write requests.
+	WriteHandler() PropertyWriteHandler
 	// Clear clears the property data.
 	Clear()
 	// PropertyMapData returns a property map.
@@ -108,17 +123,26 @@ type propertyInternal interface {
 	Copy() Property
 	// Equals returns true if the specified property is same, otherwise false.
 	Equals(otherProp *property) bool
+	// setData sets a specified data to the property without the autonomous notification.
+	setData(data []byte)
 }
 
 // property is an instance for Echonet property.
+// The property data is replaced with a new copy when it is set, so that the returned data is never modified by other goroutines.
 type property struct {
+	mutex        *sync.RWMutex
 	name         string
 	code         PropertyCode
+	dataType     string
+	capacity     int
+	enums        [][]byte
 	data         []byte
 	parentObject Object
 	getAttr      PropertyAttribute
 	setAttr      PropertyAttribute
 	annoAttr     PropertyAttribute
+	readHandler  PropertyReadHandler
+	writeHandler PropertyWriteHandler
 }
 
 // WithPropertyObject sets a parent object into the property.
@@ -163,6 +187,27 @@ func WithPropertyCode(code PropertyCode) PropertyOption {
 	}
 }
 
+// WithPropertyDataType sets a standard data type to the property.
+func WithPropertyDataType(dataType string) PropertyOption {
+	return func(prop *property) {
+		prop.dataType = dataType
+	}
+}
+
+// WithPropertyCapacity sets a standard data size to the property.
+func WithPropertyCapacity(capacity int) PropertyOption {
+	return func(prop *property) {
+		prop.capacity = capacity
+	}
+}
+
+// WithPropertyEnums sets standard enumerated values of the property data to the property.
+func WithPropertyEnums(enums ...[]byte) PropertyOption {
+	return func(prop *property) {
+		prop.enums = enums
+	}
+}
+
 // WithPropertyData sets an attribute to the read property.
 func WithPropertyData(data []byte) PropertyOption {
 	return func(prop *property) {
@@ -171,6 +216,20 @@ func WithPropertyData(data []byte) PropertyOption {
 	}
 }
 
+// WithPropertyReadHandler sets a handler to provide the property data for read requests.
+func WithPropertyReadHandler(h PropertyReadHandler) PropertyOption {
+	return func(prop *property) {
+		prop.readHandler = h
+	}
+}
+
+// WithPropertyWriteHandler sets a handler to accept the property data of write requests.
+func WithPropertyWriteHandler(h PropertyWriteHandler) PropertyOption {
+	return func(prop *property) {
+		prop.writeHandler = h
+	}
+}
+
 // NewProperty returns a new property.
 func NewProperty(opts ...PropertyOption) Property {
 	prop := newProperty()
@@ -182,23 +241,33 @@ func NewProperty(opts ...PropertyOption) Property {
 
 func newProperty() *property {
 	return &property{
+		mutex:        &sync.RWMutex{},
 		name:         "",
 		code:         0,
+		dataType:     "",
+		capacity:     0,
+		enums:        nil,
 		data:         make([]byte, 0),
 		parentObject: nil,
 		getAttr:      Prohibited,
 		setAttr:      Prohibited,
 		annoAttr:     Prohibited,
+		readHandler:  nil,
+		writeHandler: nil,
 	}
 }
 
 // SetObject sets a parent object into the property.
 func (prop *property) SetObject(obj Object) {
+	prop.mutex.Lock()
+	defer prop.mutex.Unlock()
 	prop.parentObject = obj
 }
 
 // Object returns the parent object.
 func (prop *property) Object() Object {
+	prop.mutex.RLock()
+	defer prop.mutex.RUnlock()
 	return prop.parentObject
 }
 
@@ -233,14 +302,59 @@ func (prop *property) Code() PropertyCode {
 	return prop.code
 }
 
+// DataType returns the standard data type of the property such as "state" and "number".
+func (prop *property) DataType() string {
+	return prop.dataType
+}
+
+// Capacity returns the standard data size of the property, or zero when the size is variable or unknown.
+func (prop *property) Capacity() int {
+	return prop.capacity
+}
+
+// Enums returns the standard enumerated values of the property data, or nil when the property has no enumeration.
+func (prop *property) Enums() [][]byte {
+	return prop.enums
+}
+
+// SetReadHandler sets a handler to provide the property data for read requests.
+func (prop *property) SetReadHandler(h PropertyReadHandler) Property {
+	prop.mutex.Lock()
+	defer prop.mutex.Unlock()
+	prop.readHandler = h
+	return prop
+}
+
+// ReadHandler returns the handler to provide the property data for read requests.
+func (prop *property) ReadHandler() PropertyReadHandler {
+	prop.mutex.RLock()
+	defer prop.mutex.RUnlock()
+	return prop.readHandler
+}
+
+// SetWriteHandler sets a handler to accept the property data of write requests.
+func (prop *property) SetWriteHandler(h PropertyWriteHandler) Property {
+	prop.mutex.Lock()
+	defer prop.mutex.Unlock()
+	prop.writeHandler = h
+	return prop
+}
+
+// WriteHandler returns the handler to accept the property data of write requests.
+func (prop *property) WriteHandler() PropertyWriteHandler {
+	prop.mutex.RLock()
+	defer prop.mutex.RUnlock()
+	return prop.writeHandler
+}
+
 // Clear clears the property data.
 func (prop *property) Clear() {
-	prop.data = make([]byte, 0)
+	prop.setData(make([]byte, 0))
 }
 
 // Size return the property data size.
 func (prop *property) Size() int {
-	return len(prop.data)
+	return len(prop.Data())
 }
 
 // SetReadAttribute sets an attribute to the read property.
@@ -367,8 +481,7 @@ func (prop *property) IsAvailableService(esv protocol.ESV) bool {
 
 // SetData sets a specified data to the property.
 func (prop *property) SetData(data []byte) Property {
-	prop.data = make([]byte, len(data))
-	copy(prop.data, data)
+	prop.setData(data)
 
 	// (D) Basic sequence for autonomous notification.
 
@@ -379,6 +492,15 @@ func (prop *property) SetData(data []byte) Property {
 	return prop
 }
 
+// setData sets a specified data to the property without the autonomous notification.
+func (prop *property) setData(data []byte) {
+	newData := make([]byte, len(data))
+	copy(newData, data)
+	prop.mutex.Lock()
+	defer prop.mutex.Unlock()
+	prop.data = newData
+}
+
 // SetByte is an alias of SetData.
 func (prop *property) SetByte(data []byte) Property {
 	return prop.SetData(data)
@@ -393,63 +515,69 @@ func (prop *property) SetInteger(data uint, size uint) Property {
 
 // Data returns the property data.
 func (prop *property) Data() []byte {
+	prop.mutex.RLock()
+	defer prop.mutex.RUnlock()
 	return prop.data
 }
 
 // AsByte returns a byte value of the property byte data.
 func (prop *property) AsByte() (byte, error) {
-	switch len(prop.data) {
+	data := prop.Data()
+	switch len(data) {
 	case 0:
 		return 0, ErrNoData
 	case 1:
-		return prop.data[0], nil
+		return data[0], nil
 		// ok
 	default:
-		return 0, fmt.Errorf("%w data size (%d)", ErrInvalid, len(prop.data))
+		return 0, fmt.Errorf("%w data size (%d)", ErrInvalid, len(data))
 	}
 }
 
 // AsString returns a byte value of the property string data.
 func (prop *property) AsString() (string, error) {
-	if len(prop.data) == 0 {
+	data := prop.Data()
+	if len(data) == 0 {
 		return "", ErrNoData
 	}
-	return string(prop.data), nil
+	return string(data), nil
 }
 
 // AsInteger returns a integer value of the property integer data.
 func (prop *property) AsInteger() (uint, error) {
-	if len(prop.data) == 0 {
+	data := prop.Data()
+	if len(data) == 0 {
 		return 0, ErrNoData
 	}
-	return encoding.ByteToInteger(prop.Data()), nil
+	return encoding.ByteToInteger(data), nil
 }
 
 // PropertyMapData returns a property map.
 func (prop *property) PropertyMapData() ([]PropertyCode, error) {
 	switch prop.code {
 	case ObjectGetPropertyMap, ObjectSetPropertyMap, ObjectAnnoPropertyMap:
-		if len(prop.data) == 0 {
+		data := prop.Data()
+		if len(data) == 0 {
 			return nil, fmt.Errorf(errInvalidPropertyMapData, ErrInvalid, "")
 		}
-		propMapCount := int(prop.data[0])
+		propMapCount := int(data[0])
 		switch {
 		case isPropertyMapDescriptionFormat1(propMapCount):
-			if len(prop.data) != (propMapCount + 1) {
-				return nil, fmt.Errorf(errInvalidPropertyMapData, ErrInvalid, hex.EncodeToString(prop.data))
+			if len(data) != (propMapCount + 1) {
+				return nil, fmt.Errorf(errInvalidPropertyMapData, ErrInvalid, hex.EncodeToString(data))
 			}
 			codes := make([]PropertyCode, 0)
 			for n := range propMapCount {
-				codes = append(codes, PropertyCode(prop.data[n+1]))
+				codes = append(codes, PropertyCode(data[n+1]))
 			}
 			return codes, nil
 		case isPropertyMapDescriptionFormat2(propMapCount):
-			if len(prop.data) != (PropertyMapFormat2MapSize + 1) {
-				return nil, fmt.Errorf(errInvalidPropertyMapData, ErrInvalid, hex.EncodeToString(prop.data))
+			if len(data) != (PropertyMapFormat2MapSize + 1) {
+				return nil, fmt.Errorf(errInvalidPropertyMapData, ErrInvalid, hex.EncodeToString(data))
 			}
 			codes := make([]PropertyCode, 0)
 			for n := range PropertyMapFormat2MapSize {
-				codes = append(codes, propertyMapFormat2ByteToCodes(n, prop.data[n+1])...)
+				codes = append(codes, propertyMapFormat2ByteToCodes(n, data[n+1])...)
 			}
 			return codes, nil
 		}
@@ -490,12 +618,18 @@ func (prop *property) Equals(otherProp *property) bool {
 // Copy copies the property instance without the data.
 func (prop *property) Copy() Property {
 	return &property{
+		mutex:        &sync.RWMutex{},
 		name:         prop.name,
 		code:         prop.code,
+		dataType:     prop.dataType,
+		capacity:     prop.capacity,
+		enums:        prop.enums,
 		getAttr:      prop.getAttr,
 		setAttr:      prop.setAttr,
 		annoAttr:     prop.annoAttr,
 		data:         make([]byte, 0),
 		parentObject: nil,
+		readHandler:  nil,
+		writeHandler: nil,
 	}
 }
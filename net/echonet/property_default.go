@@ -0,0 +1,50 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"bytes"
+)
+
+const (
+	propertyDataTypeNumber = "number"
+)
+
+// newStandardPropertyDefaultData returns the default data of the specified required standard property for the specified object code,
+// or nil when the property has no default data.
+//
+// The default data of the enumerated properties such as the state properties is the first enumerated value of the MRA,
+// and the default data of the number properties whose sizes are fixed is filled with zero. The other properties have no default data
+// because zero is not always a valid value for them.
+func newStandardPropertyDefaultData(objCode ObjectCode, prop Property) []byte {
+	if !prop.IsReadRequired() {
+		return nil
+	}
+
+	if prop.Code() == ObjectOperatingStatus {
+		return []byte{ObjectOperatingStatusOn}
+	}
+
+	if !isProfileObjectCode(objCode.Bytes()[0]) {
+		switch prop.Code() {
+		case DeviceInstallationLocation:
+			return []byte{DeviceInstallationLocationUnknown}
+		case DeviceStandardVersion:
+			return []byte{0x00, 0x00, DeviceDefaultVersionAppendix, 0x00}
+		case DeviceFaultStatus:
+			return []byte{DeviceNoFaultOccurred}
+		}
+	}
+
+	if enums := prop.Enums(); 0 < len(enums) {
+		return bytes.Clone(enums[0])
+	}
+
+	if prop.DataType() != propertyDataTypeNumber || prop.Capacity() <= 0 {
+		return nil
+	}
+
+	return make([]byte, prop.Capacity())
+}
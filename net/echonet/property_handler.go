@@ -0,0 +1,19 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+)
+
+// PropertyReadHandler is called as OnGet when the node builds a response for a read request of the property.
+// The returned data is used for the response instead of the stored data of the property.
+// The node returns a SNA response with the property when the handler returns an error.
+type PropertyReadHandler func(ctx context.Context, prop Property) ([]byte, error)
+
+// PropertyWriteHandler is called as OnSet when a write request of the property is received.
+// The node stores the requested data into the property when the handler returns no error.
+// Otherwise, the node returns a SNA response with the property when the handler returns an error.
+type PropertyWriteHandler func(ctx context.Context, prop Property, data []byte) error
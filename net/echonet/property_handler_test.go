@@ -0,0 +1,130 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestPropertyHandlers(t *testing.T) {
+	powerConsumption := []byte{0x00, 0x64}
+	errReadRejected := errors.New("read rejected")
+	errWriteRejected := errors.New("write rejected")
+
+	dev, err := NewDevice(
+		WithDeviceCode(testLightDeviceCode),
+		WithDevicePropertyReadHandler(DeviceMeasuredInstantaneousPowerConsumption, func(ctx context.Context, prop Property) ([]byte, error) {
+			return powerConsumption, nil
+		}),
+		WithDevicePropertyReadHandler(DeviceMeasuredCumulativePowerConsumption, func(ctx context.Context, prop Property) ([]byte, error) {
+			return nil, errReadRejected
+		}),
+		WithDevicePropertyWriteHandler(DeviceOperatingStatus, func(ctx context.Context, prop Property, data []byte) error {
+			if data[0] != DeviceOperatingStatusOff {
+				return errWriteRejected
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeDevices(dev))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	postMessage := func(t *testing.T, esv protocol.ESV, props ...Property) Message {
+		t.Helper()
+		reqMsg := NewMessage(
+			WithMessageDEOJ(testLightDeviceCode),
+			WithMessageESV(esv),
+			WithMessageProperties(props...),
+		)
+		resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resMsg
+	}
+
+	t.Run("OnGet", func(t *testing.T) {
+		resMsg := postMessage(t, protocol.ESVReadRequest,
+			NewProperty(WithPropertyCode(DeviceMeasuredInstantaneousPowerConsumption)),
+		)
+		if resMsg.ESV() != protocol.ESVReadResponse {
+			t.Fatalf("%s", resMsg)
+		}
+		resProp, ok := resMsg.Property(0)
+		if !ok || !bytes.Equal(resProp.Data(), powerConsumption) {
+			t.Errorf("%s", resMsg)
+		}
+	})
+
+	t.Run("OnGetError", func(t *testing.T) {
+		resMsg := postMessage(t, protocol.ESVReadRequest,
+			NewProperty(WithPropertyCode(DeviceMeasuredInstantaneousPowerConsumption)),
+			NewProperty(WithPropertyCode(DeviceMeasuredCumulativePowerConsumption)),
+		)
+		if resMsg.ESV() != protocol.ESVReadRequestError {
+			t.Fatalf("%s", resMsg)
+		}
+		resProps := resMsg.Properties()
+		if len(resProps) != 2 {
+			t.Fatalf("%s", resMsg)
+		}
+		if !bytes.Equal(resProps[0].Data(), powerConsumption) {
+			t.Errorf("%s", resMsg)
+		}
+		if resProps[1].Size() != 0 {
+			t.Errorf("%s", resMsg)
+		}
+	})
+
+	t.Run("OnSet", func(t *testing.T) {
+		resMsg := postMessage(t, protocol.ESVWriteRequestResponseRequired,
+			NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOff})),
+		)
+		if resMsg.ESV() != protocol.ESVWriteResponse {
+			t.Fatalf("%s", resMsg)
+		}
+		status, err := dev.LookupPropertyByte(DeviceOperatingStatus)
+		if err != nil || status != DeviceOperatingStatusOff {
+			t.Errorf("%X != %X", status, DeviceOperatingStatusOff)
+		}
+	})
+
+	t.Run("OnSetError", func(t *testing.T) {
+		resMsg := postMessage(t, protocol.ESVWriteRequestResponseRequired,
+			NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOn})),
+		)
+		if resMsg.ESV() != protocol.ESVWriteRequestResponseRequiredError {
+			t.Fatalf("%s", resMsg)
+		}
+		resProp, ok := resMsg.Property(0)
+		if !ok || !bytes.Equal(resProp.Data(), []byte{DeviceOperatingStatusOn}) {
+			t.Errorf("%s", resMsg)
+		}
+		status, err := dev.LookupPropertyByte(DeviceOperatingStatus)
+		if err != nil || status != DeviceOperatingStatusOff {
+			t.Errorf("%X != %X", status, DeviceOperatingStatusOff)
+		}
+	})
+}
@@ -6,7 +6,9 @@ package echonet
 
 import (
 	"fmt"
+	"maps"
 	"slices"
+	"sync"
 	"time"
 )
 
@@ -19,7 +21,9 @@ const (
 )
 
 // propertyMap represents a property map.
+// The mutex guards the properties, and serializes the multi-property updates and lookups so that they are applied atomically.
 type propertyMap struct {
+	mutex        *sync.RWMutex
 	properties   map[PropertyCode]Property
 	parentObject Object
 }
@@ -27,6 +31,7 @@ type propertyMap struct {
 // newPropertyMap returns a new property map.
 func newPropertyMap() *propertyMap {
 	propMap := &propertyMap{
+		mutex:        &sync.RWMutex{},
 		properties:   map[PropertyCode]Property{},
 		parentObject: nil,
 	}
@@ -36,6 +41,8 @@ func newPropertyMap() *propertyMap {
 
 // SetObject sets a parent object.
 func (propMap *propertyMap) SetObject(obj Object) {
+	propMap.mutex.Lock()
+	defer propMap.mutex.Unlock()
 	propMap.parentObject = obj
 	for _, prop := range propMap.properties {
 		prop.SetObject(obj)
@@ -44,17 +51,23 @@ func (propMap *propertyMap) SetObject(obj Object) {
 
 // Object returns a parent object.
 func (propMap *propertyMap) Object() Object {
+	propMap.mutex.RLock()
+	defer propMap.mutex.RUnlock()
 	return propMap.parentObject
 }
 
 // AddProperty adds a new property into the property map.
 func (propMap *propertyMap) AddProperty(prop Property) {
+	propMap.mutex.Lock()
+	defer propMap.mutex.Unlock()
 	propMap.properties[prop.Code()] = prop
 	prop.SetObject(propMap.parentObject)
 }
 
 // ClearAllProperties removes all properties in the property map.
 func (propMap *propertyMap) ClearAllProperties(prop Property) {
+	propMap.mutex.Lock()
+	defer propMap.mutex.Unlock()
 	for code := range propMap.properties {
 		delete(propMap.properties, code)
 	}
@@ -62,6 +75,8 @@ func (propMap *propertyMap) ClearAllProperties(prop Property) {
 
 // Properties returns the all properties in the property map.
 func (propMap *propertyMap) Properties() []Property {
+	propMap.mutex.RLock()
+	defer propMap.mutex.RUnlock()
 	codes := make([]PropertyCode, len(propMap.properties))
 	n := 0
 	for code := range propMap.properties {
@@ -84,6 +99,8 @@ func (propMap *propertyMap) Properties() []Property {
 
 // LookupProperty returns the specified property in the property map.
 func (propMap *propertyMap) LookupProperty(code PropertyCode) (Property, bool) {
+	propMap.mutex.RLock()
+	defer propMap.mutex.RUnlock()
 	prop, ok := propMap.properties[code]
 	return prop, ok
 }
@@ -102,6 +119,8 @@ func (propMap *propertyMap) FindPropertyWait(code PropertyCode, waitTime time.Du
 
 // PropertyCount returns the property count in the property map.
 func (propMap *propertyMap) PropertyCount() int {
+	propMap.mutex.RLock()
+	defer propMap.mutex.RUnlock()
 	return len(propMap.properties)
 }
 
@@ -115,6 +134,44 @@ func (propMap *propertyMap) SetPropertyData(propCode PropertyCode, propData []by
 	return nil
 }
 
+// SetPropertiesData sets the data to the existing properties atomically.
+// No data is set when any of the properties does not exist, and the announceable properties are announced together after all data is set.
+func (propMap *propertyMap) SetPropertiesData(propData map[PropertyCode][]byte) error {
+	codes := slices.Sorted(maps.Keys(propData))
+
+	propMap.mutex.Lock()
+	props := make([]Property, 0, len(codes))
+	for _, code := range codes {
+		prop, ok := propMap.properties[code]
+		if !ok {
+			propMap.mutex.Unlock()
+			return fmt.Errorf(errPropertyNotFound, ErrNotFound, uint(code))
+		}
+		props = append(props, prop)
+	}
+	for _, prop := range props {
+		prop.setData(propData[prop.Code()])
+	}
+	propMap.mutex.Unlock()
+
+	// (D) Basic sequence for autonomous notification.
+
+	annoProps := make([]Property, 0, len(props))
+	for _, prop := range props {
+		if prop.IsAnnounceable() {
+			annoProps = append(annoProps, prop)
+		}
+	}
+	if len(annoProps) == 0 {
+		return nil
+	}
+	parentNode, ok := annoProps[0].Node().(localNodeHelper)
+	if !ok || parentNode == nil || !parentNode.IsRunning() {
+		return nil
+	}
+	return parentNode.AnnounceProperties(annoProps...)
+}
+
 // SetPropertyByte sets a byte to the existing property.
 func (propMap *propertyMap) SetPropertyByte(propCode PropertyCode, propData byte) error {
 	prop, ok := propMap.LookupProperty(propCode)
@@ -159,6 +216,21 @@ func (propMap *propertyMap) LookupPropertyData(propCode PropertyCode) ([]byte, e
 	return prop.Data(), nil
 }
 
+// LookupPropertiesData returns the specified properties data atomically in the property map.
+func (propMap *propertyMap) LookupPropertiesData(propCodes ...PropertyCode) ([][]byte, error) {
+	propMap.mutex.RLock()
+	defer propMap.mutex.RUnlock()
+	propData := make([][]byte, 0, len(propCodes))
+	for _, code := range propCodes {
+		prop, ok := propMap.properties[code]
+		if !ok {
+			return nil, fmt.Errorf(errPropertyNotFound, ErrNotFound, uint(code))
+		}
+		propData = append(propData, prop.Data())
+	}
+	return propData, nil
+}
+
 // LookupPropertyByte return the specified property byte data in the property map.
 func (propMap *propertyMap) LookupPropertyByte(propCode PropertyCode) (byte, error) {
 	prop, ok := propMap.LookupProperty(propCode)
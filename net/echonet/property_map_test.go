@@ -5,8 +5,10 @@
 package echonet
 
 import (
+	"bytes"
 	"fmt"
 	"slices"
+	"sync"
 	"testing"
 )
 
@@ -189,3 +191,87 @@ func TestObjectPropertyMap(t *testing.T) {
 		})
 	}
 }
+
+func TestPropertyMapConcurrentAccess(t *testing.T) {
+	// Run with -race to check that the property storage is goroutine-safe.
+
+	dev, err := NewDevice(WithDeviceCode(testLightDeviceCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	propCodes := []PropertyCode{DeviceOperatingStatus, DeviceInstallationLocation}
+
+	const testLoopCount = 1000
+
+	for _, code := range propCodes {
+		if err := dev.SetPropertyByte(code, 0x00); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		for n := range testLoopCount {
+			propData := map[PropertyCode][]byte{}
+			for _, code := range propCodes {
+				propData[code] = []byte{byte(n)}
+			}
+			if err := dev.SetPropertiesData(propData); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+
+	wg.Go(func() {
+		for range testLoopCount {
+			propData, err := dev.LookupPropertiesData(propCodes...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, data := range propData[1:] {
+				if !bytes.Equal(data, propData[0]) {
+					t.Errorf("%X != %X", data, propData[0])
+					return
+				}
+			}
+		}
+	})
+
+	wg.Go(func() {
+		for n := range testLoopCount {
+			if err := dev.SetPropertyByte(DeviceFaultStatus, byte(n)); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := dev.LookupPropertyByte(DeviceFaultStatus); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+
+	wg.Go(func() {
+		for n := range testLoopCount {
+			dev.AddProperty(NewProperty(WithPropertyCode(PropertyCode(0xF0 + n%0x10))))
+			for _, prop := range dev.Properties() {
+				prop.Data()
+			}
+		}
+	})
+
+	wg.Wait()
+
+	if err := dev.SetPropertyByte(DeviceOperatingStatus, DeviceOperatingStatusOn); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetPropertiesData(map[PropertyCode][]byte{DeviceOperatingStatus: {DeviceOperatingStatusOff}, 0x00: {0x00}}); err == nil {
+		t.Error("unknown property is set")
+	}
+	if status, err := dev.LookupPropertyByte(DeviceOperatingStatus); err != nil || status != DeviceOperatingStatusOn {
+		t.Errorf("partial update is applied (%X)", status)
+	}
+}
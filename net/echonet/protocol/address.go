@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 )
 
 // Address represents the address of the message end point.
@@ -44,7 +45,9 @@ func (addr *Address) ParseString(addrStr string) error {
 		return err
 	}
 
+	hostStr, zoneStr, _ := strings.Cut(hostStr, "%")
 	addr.IP = net.ParseIP(hostStr)
+	addr.Zone = zoneStr
 
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
@@ -55,7 +58,15 @@ func (addr *Address) ParseString(addrStr string) error {
 	return nil
 }
 
+// Host returns the IP address string with the IPv6 scoped addressing zone such as "fe80::1%eth0".
+func (addr *Address) Host() string {
+	if len(addr.Zone) == 0 {
+		return addr.IP.String()
+	}
+	return addr.IP.String() + "%" + addr.Zone
+}
+
 // String returns the node string representation.
 func (addr *Address) String() string {
-	return net.JoinHostPort(addr.IP.String(), strconv.Itoa(addr.Port))
+	return net.JoinHostPort(addr.Host(), strconv.Itoa(addr.Port))
 }
@@ -49,3 +49,29 @@ func TestNewAddressParse(t *testing.T) {
 		}
 	}
 }
+
+func TestAddressZone(t *testing.T) {
+	testAddr := "[fe80::1%eth0]:3610"
+
+	addr, err := NewAddressWithString(testAddr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if addr.IP.String() != "fe80::1" {
+		t.Errorf("%s != %s", addr.IP.String(), "fe80::1")
+	}
+
+	if addr.Zone != "eth0" {
+		t.Errorf("%s != %s", addr.Zone, "eth0")
+	}
+
+	if addr.Host() != "fe80::1%eth0" {
+		t.Errorf("%s != %s", addr.Host(), "fe80::1%eth0")
+	}
+
+	if addr.String() != testAddr {
+		t.Errorf("%s != %s", addr.String(), testAddr)
+	}
+}
@@ -6,6 +6,7 @@ package protocol
 
 import (
 	"errors"
+	"fmt"
 )
 
 // ErrNoData is returned when there is no data.
@@ -14,9 +15,32 @@ var ErrNoData = errors.New("no data")
 // ErrInvalid is returned when the value is invalid.
 var ErrInvalid = errors.New("invalid")
 
+// ErrTruncated is returned when the message frame is shorter than the header or the property fields.
+var ErrTruncated = fmt.Errorf("%w: truncated frame", ErrInvalid)
+
+// ErrOPCMismatch is returned when the message frame has fewer properties than the OPC.
+var ErrOPCMismatch = fmt.Errorf("%w: OPC mismatch", ErrInvalid)
+
+// ErrTrailingData is returned when the message frame has extra bytes after the properties.
+var ErrTrailingData = fmt.Errorf("%w: trailing data", ErrInvalid)
+
+// ErrNoProperty is returned when the request message has no property.
+var ErrNoProperty = fmt.Errorf("%w: no property", ErrInvalid)
+
 const (
 	errInvalidMessageSize   = "%w: message length : %d < %d"
 	errInvalidMessageHeader = "%w: message header [%d] : %02X != %02X"
 	errInvalidAddress       = "%w: address string : %s"
 	errInvalidObjectCodes   = "%w: object code : %s"
+	errTruncatedProperty    = "%w: property [%d] : %d < %d"
+	errOPCMismatch          = "%w: OPC (%d) != %d"
+	errTrailingData         = "%w: %d bytes after %d properties"
+	errNoProperty           = "%w: ESV (%s) with OPC (0)"
+	errMessageTooLarge      = "%w: message size : %d > %d"
+	errInvalidESV           = "%w: ESV (%s)"
+	errInvalidObjectCode    = "%w: %s (%s) : class (%02X%02X)"
+	errInvalidInstanceCode  = "%w: %s (%s) : instance (%02X)"
+	errEDTRequired          = "%w: ESV (%s) requires EDT : property [%d] (%02X)"
+	errEDTProhibited        = "%w: ESV (%s) prohibits EDT : property [%d] (%02X) has %d bytes"
+	errEDTTooLarge          = "%w: property [%d] (%02X) EDT size : %d > %d"
 )
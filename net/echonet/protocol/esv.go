@@ -31,6 +31,25 @@ const (
 	ESVWriteReadRequestError             = 0x5E
 )
 
+var esvNames = map[ESV]string{
+	ESVWriteRequest:                      "SetI",
+	ESVWriteRequestResponseRequired:      "SetC",
+	ESVReadRequest:                       "Get",
+	ESVNotificationRequest:               "INF_REQ",
+	ESVWriteReadRequest:                  "SetGet",
+	ESVWriteResponse:                     "Set_Res",
+	ESVReadResponse:                      "Get_Res",
+	ESVNotification:                      "INF",
+	ESVNotificationResponseRequired:      "INFC",
+	ESVNotificationResponse:              "INFC_Res",
+	ESVWriteReadResponse:                 "SetGet_Res",
+	ESVWriteRequestError:                 "SetI_SNA",
+	ESVWriteRequestResponseRequiredError: "SetC_SNA",
+	ESVReadRequestError:                  "Get_SNA",
+	ESVNotificationRequestError:          "INF_SNA",
+	ESVWriteReadRequestError:             "SetGet_SNA",
+}
+
 // IsValid returns true when the ESV is valid, otherwise false.
 func (esv ESV) IsValid() bool {
 	validCodes := []ESV{
@@ -55,6 +74,20 @@ func (esv ESV) IsValid() bool {
 	return slices.Contains(validCodes, esv)
 }
 
+// IsRequest returns true whether the specified code is a request type, otherwise false.
+func (esv ESV) IsRequest() bool {
+	return esv.IsWriteRequest() || esv.IsReadRequest() || esv.IsNotificationRequest()
+}
+
+// IsWriteReadService returns true whether the specified code is a write and read service which has the two property lists, otherwise false.
+func (esv ESV) IsWriteReadService() bool {
+	switch esv {
+	case ESVWriteReadRequest, ESVWriteReadResponse, ESVWriteReadRequestError:
+		return true
+	}
+	return false
+}
+
 // IsWriteRequest returns true whether the specified code is a write request type, otherwise false.
 func (esv ESV) IsWriteRequest() bool {
 	switch esv {
@@ -130,6 +163,15 @@ func (esv ESV) IsNotificationResponse() bool {
 	return false
 }
 
+// IsImpossibleResponse returns true whether the specified code is a SNA (service not available) response type, otherwise false.
+func (esv ESV) IsImpossibleResponse() bool {
+	switch esv {
+	case ESVWriteRequestError, ESVWriteRequestResponseRequiredError, ESVReadRequestError, ESVNotificationRequestError, ESVWriteReadRequestError:
+		return true
+	}
+	return false
+}
+
 // IsResponseRequired returns true whether the ESV requires the response, otherwise false.
 func (esv ESV) IsResponseRequired() bool {
 	switch esv {
@@ -147,7 +189,31 @@ func (esv ESV) IsResponseRequired() bool {
 	return false
 }
 
+// IsResponseOf returns true whether the ESV is a response or an error response of the specified request ESV, otherwise false.
+func (esv ESV) IsResponseOf(reqESV ESV) bool {
+	switch reqESV {
+	case ESVWriteRequest:
+		return esv == ESVWriteRequestError
+	case ESVWriteRequestResponseRequired:
+		return esv == ESVWriteResponse || esv == ESVWriteRequestResponseRequiredError
+	case ESVReadRequest:
+		return esv == ESVReadResponse || esv == ESVReadRequestError
+	case ESVNotificationRequest:
+		return esv == ESVNotification || esv == ESVNotificationRequestError
+	case ESVWriteReadRequest:
+		return esv == ESVWriteReadResponse || esv == ESVWriteReadRequestError
+	case ESVNotificationResponseRequired:
+		return esv == ESVNotificationResponse
+	}
+	return false
+}
+
 // String returns the node string representation.
 func (esv ESV) String() string {
 	return fmt.Sprintf("%02X", uint(esv))
 }
+
+// Name returns the service name of the ESV such as "Get" and "SetC_SNA", or an empty string when the ESV is unknown.
+func (esv ESV) Name() string {
+	return esvNames[esv]
+}
@@ -11,3 +11,64 @@ import (
 func TestESV(t *testing.T) {
 	ESV(0x00).IsValid()
 }
+
+func TestESVIsResponseOf(t *testing.T) {
+	tests := []struct {
+		reqESV ESV
+		resESV ESV
+		ok     bool
+	}{
+		{ESVWriteRequest, ESVWriteRequestError, true},
+		{ESVWriteRequest, ESVWriteResponse, false},
+		{ESVWriteRequestResponseRequired, ESVWriteResponse, true},
+		{ESVWriteRequestResponseRequired, ESVWriteRequestResponseRequiredError, true},
+		{ESVWriteRequestResponseRequired, ESVNotification, false},
+		{ESVReadRequest, ESVReadResponse, true},
+		{ESVReadRequest, ESVReadRequestError, true},
+		{ESVReadRequest, ESVReadRequest, false},
+		{ESVNotificationRequest, ESVNotification, true},
+		{ESVWriteReadRequest, ESVWriteReadResponse, true},
+		{ESVNotificationResponseRequired, ESVNotificationResponse, true},
+		{ESVNotification, ESVNotification, false},
+	}
+	for _, test := range tests {
+		if test.resESV.IsResponseOf(test.reqESV) != test.ok {
+			t.Errorf("%02X -> %02X != %t", test.reqESV, test.resESV, test.ok)
+		}
+	}
+}
+
+func TestESVName(t *testing.T) {
+	tests := []struct {
+		esv  ESV
+		name string
+	}{
+		{ESVReadRequest, "Get"},
+		{ESVWriteRequestResponseRequiredError, "SetC_SNA"},
+		{ESVNotificationResponse, "INFC_Res"},
+		{ESV(0x00), ""},
+	}
+	for _, test := range tests {
+		if test.esv.Name() != test.name {
+			t.Errorf("%02X : %s != %s", test.esv, test.esv.Name(), test.name)
+		}
+	}
+}
+
+func TestESVIsImpossibleResponse(t *testing.T) {
+	tests := []struct {
+		esv ESV
+		ok  bool
+	}{
+		{ESVWriteRequestError, true},
+		{ESVReadRequestError, true},
+		{ESVWriteReadRequestError, true},
+		{ESVReadResponse, false},
+		{ESVReadRequest, false},
+	}
+	for _, test := range tests {
+		if test.esv.IsImpossibleResponse() != test.ok {
+			t.Errorf("%02X != %t", test.esv, test.ok)
+		}
+	}
+}
@@ -20,9 +20,13 @@ const (
 	Format1PropertyHeaderSize = 2
 	EHD1Echonet               = 0x10
 	EHD2Format1               = 0x81
+	EHD2Format2               = 0x82
 	TIDSize                   = 2
 	TIDMax                    = 65535
 	EOJSize                   = 3
+	OPCMax                    = 0xFF
+	PDCMax                    = 0xFF
+	MaxMessageSize            = 1024
 )
 
 const (
@@ -34,16 +38,23 @@ const (
 )
 
 // Message is an instance for Echonet message.
+// The message is a specified message format (Format 1) message by default, and is an arbitrary message format (Format 2) message
+// when the EHD2 is EHD2Format2. The Format 2 message has only the free-form EDATA after the TID.
 type Message struct {
 	EHD1Echonet byte
 	EHD2Format1 byte
-	tid         []byte
-	seoj        []byte
-	deoj        []byte
+	tid         [TIDSize]byte
+	seoj        [EOJSize]byte
+	deoj        [EOJSize]byte
 	esv         ESV
 	opc         byte
 	ep          []Property
+	edata       []byte
+	buf         []byte
+	props       []property
+	parseMode   ParseMode
 	From        *Address
+	from        Address
 	pktType     int
 	Interface   *net.Interface
 }
@@ -53,16 +64,30 @@ func NewMessage() *Message {
 	msg := &Message{
 		EHD1Echonet: EHD1Echonet,
 		EHD2Format1: EHD2Format1,
-		tid:         make([]byte, TIDSize),
-		seoj:        make([]byte, EOJSize),
-		deoj:        make([]byte, EOJSize),
+		tid:         [TIDSize]byte{},
+		seoj:        [EOJSize]byte{},
+		deoj:        [EOJSize]byte{},
 		esv:         0,
 		opc:         0,
 		ep:          make([]Property, 0),
-		From:        NewAddress(),
+		edata:       nil,
+		buf:         nil,
+		props:       nil,
+		parseMode:   ParseModeStrict,
+		From:        nil,
+		from:        Address{IP: nil, Port: 0, Zone: ""},
 		pktType:     UnknownPacket,
 		Interface:   nil,
 	}
+	// The source address is allocated together with the message not to allocate it for each received message.
+	msg.From = &msg.from
+	return msg
+}
+
+// NewFormat2Message returns a new arbitrary message format (Format 2) message with the specified EDATA.
+func NewFormat2Message(data []byte) *Message {
+	msg := NewMessage()
+	msg.SetFormat2Data(data)
 	return msg
 }
 
@@ -86,13 +111,16 @@ func NewMessageWithBytes(data []byte) (*Message, error) {
 
 // NewMessageWithMessage copies the specified message.
 func NewMessageWithMessage(msg *Message) (*Message, error) {
-	copyMsg, err := NewMessageWithBytes(msg.Bytes())
-	if err != nil {
+	copyMsg := NewMessage()
+	copyMsg.SetParseMode(ParseModeLenient)
+	copyMsg.buf = msg.Bytes()
+	if err := copyMsg.parseBufferBytes(); err != nil {
 		return nil, err
 	}
 
-	from := *msg.From
-	copyMsg.From = &from
+	copyMsg.parseMode = msg.parseMode
+
+	*copyMsg.From = *msg.From
 
 	copyMsg.pktType = msg.pktType
 	copyMsg.Interface = msg.Interface
@@ -106,6 +134,8 @@ func NewResponseMessageWithMessage(reqMsg *Message) *Message {
 	msg.SetTID(reqMsg.TID())
 	msg.SetSEOJ(reqMsg.DEOJ())
 	msg.SetDEOJ(reqMsg.SEOJ())
+	// The response message is sent back over the interface which the request message arrived on.
+	msg.Interface = reqMsg.Interface
 
 	switch reqMsg.ESV() {
 	case ESVWriteRequestResponseRequired:
@@ -125,12 +155,25 @@ func NewResponseMessageWithMessage(reqMsg *Message) *Message {
 	return msg
 }
 
-// NewImpossibleMessageWithMessage returns a impossible message of the specified message.
+// NewImpossibleMessageWithMessage returns a impossible message of the specified message with the request properties.
 func NewImpossibleMessageWithMessage(reqMsg *Message) *Message {
+	msg := NewImpossibleResponseMessageWithMessage(reqMsg)
+	for _, reqProp := range reqMsg.Properties() {
+		prop := NewPropertyWithCode(reqProp.Code())
+		prop.SetData(reqProp.Data())
+		msg.AddProperty(prop)
+	}
+	return msg
+}
+
+// NewImpossibleResponseMessageWithMessage returns a impossible message of the specified message withtout the properties.
+func NewImpossibleResponseMessageWithMessage(reqMsg *Message) *Message {
 	msg := NewMessage()
 	msg.SetTID(reqMsg.TID())
 	msg.SetSEOJ(reqMsg.DEOJ())
 	msg.SetDEOJ(reqMsg.SEOJ())
+	// The response message is sent back over the interface which the request message arrived on.
+	msg.Interface = reqMsg.Interface
 
 	switch reqMsg.ESV() {
 	case ESVWriteRequest:
@@ -149,15 +192,41 @@ func NewImpossibleMessageWithMessage(reqMsg *Message) *Message {
 		msg.SetESV(0)
 	}
 
-	reqMsgOPC := reqMsg.OPC()
-	msg.SetOPC(reqMsgOPC)
-	for n := range reqMsgOPC {
-		reqProp := msg.Property(n)
-		msg.AddProperty(reqProp)
-	}
 	return msg
 }
 
+// Reset clears the message to reuse it for parsing or building another message.
+// The parse mode and internal buffers are kept, so that the properties and data of the previous message must not be used after Reset.
+func (msg *Message) Reset() {
+	msg.EHD1Echonet = EHD1Echonet
+	msg.EHD2Format1 = EHD2Format1
+	msg.tid = [TIDSize]byte{}
+	msg.seoj = [EOJSize]byte{}
+	msg.deoj = [EOJSize]byte{}
+	msg.esv = 0
+	msg.opc = 0
+	clear(msg.ep)
+	msg.ep = msg.ep[:0]
+	msg.edata = nil
+	msg.buf = msg.buf[:0]
+	if msg.From == nil {
+		msg.From = &msg.from
+	}
+	*msg.From = Address{}
+	msg.pktType = UnknownPacket
+	msg.Interface = nil
+}
+
+// SetParseMode sets the specified mode to parse the message bytes. The mode is ParseModeStrict by default.
+func (msg *Message) SetParseMode(mode ParseMode) {
+	msg.parseMode = mode
+}
+
+// ParseMode returns the mode to parse the message bytes.
+func (msg *Message) ParseMode() ParseMode {
+	return msg.parseMode
+}
+
 // SetTID sets the specified TID.
 func (msg *Message) SetTID(value uint) error {
 	if TIDMax < value {
@@ -173,6 +242,28 @@ func (msg *Message) EHD() [2]byte {
 	return [2]byte{msg.EHD1Echonet, msg.EHD2Format1}
 }
 
+// IsFormat1 returns true when the message is a specified message format (Format 1) message, otherwise false.
+func (msg *Message) IsFormat1() bool {
+	return msg.EHD2Format1 == EHD2Format1
+}
+
+// IsFormat2 returns true when the message is an arbitrary message format (Format 2) message, otherwise false.
+func (msg *Message) IsFormat2() bool {
+	return msg.EHD2Format1 == EHD2Format2
+}
+
+// SetFormat2Data sets the specified EDATA, and changes the message to an arbitrary message format (Format 2) message.
+func (msg *Message) SetFormat2Data(data []byte) {
+	msg.EHD2Format1 = EHD2Format2
+	msg.edata = make([]byte, len(data))
+	copy(msg.edata, data)
+}
+
+// Format2Data returns the EDATA of the arbitrary message format (Format 2) message.
+func (msg *Message) Format2Data() []byte {
+	return msg.edata
+}
+
 // TID returns the stored Transaction ID (TID).
 func (msg *Message) TID() uint {
 	return (((uint)(msg.tid[0]) << 8) + (uint)(msg.tid[1]))
@@ -185,12 +276,12 @@ func (msg *Message) IsTID(tid uint) bool {
 
 // SetSEOJ sets a source object (SEOJ) code.
 func (msg *Message) SetSEOJ(code ObjectCode) {
-	encoding.IntegerToByte(uint(code), msg.seoj)
+	encoding.IntegerToByte(uint(code), msg.seoj[:])
 }
 
 // SEOJ returns the source object (SEOJ) code.
 func (msg *Message) SEOJ() ObjectCode {
-	return ObjectCode(encoding.ByteToInteger(msg.seoj))
+	return ObjectCode(encoding.ByteToInteger(msg.seoj[:]))
 }
 
 // IsSEOJ returns true whether the specified value equals the message source object (SEOJ) code, otherwise false.
@@ -200,12 +291,12 @@ func (msg *Message) IsSEOJ(code ObjectCode) bool {
 
 // SetDEOJ sets a destination object (DEOJ) code.
 func (msg *Message) SetDEOJ(code ObjectCode) {
-	encoding.IntegerToByte(uint(code), msg.deoj)
+	encoding.IntegerToByte(uint(code), msg.deoj[:])
 }
 
 // DEOJ returns the destination object (DEOJ) code.
 func (msg *Message) DEOJ() ObjectCode {
-	return ObjectCode(encoding.ByteToInteger(msg.deoj))
+	return ObjectCode(encoding.ByteToInteger(msg.deoj[:]))
 }
 
 // IsDEOJ returns true whether the specified value equals the message destination object (DEOJ) code, otherwise false.
@@ -228,12 +319,23 @@ func (msg *Message) IsESV(esv ESV) bool {
 	return msg.esv == esv
 }
 
-// SetOPC sets the specified number of processing properties (OPC).
+// SetOPC sets the specified number of processing properties (OPC), and replaces the properties with the new empty properties.
+// The empty properties are allocated in the internal buffer which is reused when the message is parsed again.
 func (msg *Message) SetOPC(value int) error {
 	msg.opc = byte(value & 0xFF)
-	msg.ep = make([]Property, msg.opc)
-	for n := range msg.opc {
-		msg.ep[n] = NewProperty()
+	opc := int(msg.opc)
+	if cap(msg.props) < opc {
+		msg.props = make([]property, opc)
+	}
+	msg.props = msg.props[:opc]
+	clear(msg.ep)
+	if cap(msg.ep) < opc {
+		msg.ep = make([]Property, 0, opc)
+	}
+	msg.ep = msg.ep[:0]
+	for n := range opc {
+		msg.props[n] = property{code: 0, data: nil}
+		msg.ep = append(msg.ep, &msg.props[n])
 	}
 	return nil
 }
@@ -279,9 +381,9 @@ func (msg *Message) HasProperty(propCode PropertyCode) bool {
 	return false
 }
 
-// SourceAddress returns the source address of the message.
+// SourceAddress returns the source address of the message. The IPv6 link-local address has the scoped addressing zone.
 func (msg *Message) SourceAddress() string {
-	return msg.From.IP.String()
+	return msg.From.Host()
 }
 
 // SourcePort returns the source address of the message.
@@ -326,6 +428,10 @@ func (msg *Message) IsUDPUnicastPacket() bool {
 
 // Size return the byte size.
 func (msg *Message) Size() int {
+	if msg.IsFormat2() {
+		return FrameHeaderSize + len(msg.edata)
+	}
+
 	msgSize := Format1MinSize
 
 	for n := range msg.opc {
@@ -345,47 +451,37 @@ func (msg *Message) Bytes() []byte {
 	if msg == nil {
 		return make([]byte, 0)
 	}
+	return msg.AppendBytes(make([]byte, 0, msg.Size()))
+}
+
+// AppendBytes appends the message bytes to the specified buffer, and returns the extended buffer.
+// AppendBytes does not allocate any memory when the buffer has enough capacity for the message.
+func (msg *Message) AppendBytes(dst []byte) []byte {
+	if msg == nil {
+		return dst
+	}
+
+	dst = append(dst, msg.EHD1Echonet, msg.EHD2Format1, msg.tid[0], msg.tid[1])
+
+	if msg.IsFormat2() {
+		return append(dst, msg.edata...)
+	}
+
+	dst = append(dst, msg.seoj[:]...)
+	dst = append(dst, msg.deoj[:]...)
+	dst = append(dst, byte(msg.esv), msg.opc)
 
-	msgBytes := make([]byte, msg.Size())
-
-	msgBytes[0] = msg.EHD1Echonet
-	msgBytes[1] = msg.EHD2Format1
-	msgBytes[2] = msg.tid[0]
-	msgBytes[3] = msg.tid[1]
-	msgBytes[4] = msg.seoj[0]
-	msgBytes[5] = msg.seoj[1]
-	msgBytes[6] = msg.seoj[2]
-	msgBytes[7] = msg.deoj[0]
-	msgBytes[8] = msg.deoj[1]
-	msgBytes[9] = msg.deoj[2]
-	msgBytes[10] = byte(msg.esv)
-	msgBytes[11] = msg.opc
-
-	offset := 12
 	for n := range msg.opc {
 		prop := msg.Property(int(n))
 		if prop == nil {
 			continue
 		}
-		msgBytes[offset] = byte(prop.Code())
-		offset++
-
-		propSize := prop.Size()
-		msgBytes[offset] = byte(propSize)
-		offset++
-		if propSize == 0 {
-			continue
-		}
-
 		propData := prop.Data()
-		for i := range propSize {
-			msgBytes[offset+i] = propData[i]
-		}
-
-		offset += propSize
+		dst = append(dst, byte(prop.Code()), byte(len(propData)))
+		dst = append(dst, propData...)
 	}
 
-	return msgBytes
+	return dst
 }
 
 // Equals returns true whether the specified other message is same, otherwise false.
@@ -0,0 +1,71 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocol
+
+import (
+	"net"
+	"testing"
+)
+
+func BenchmarkMessageBytes(b *testing.B) {
+	msg, err := NewMessageWithBytes(testMessageBytes)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = msg.Bytes()
+	}
+}
+
+func BenchmarkMessageAppendBytes(b *testing.B) {
+	msg, err := NewMessageWithBytes(testMessageBytes)
+	if err != nil {
+		b.Fatal(err)
+	}
+	buf := make([]byte, 0, len(testMessageBytes))
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = msg.AppendBytes(buf[:0])
+	}
+}
+
+func BenchmarkNewMessageWithBytes(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewMessageWithBytes(testMessageBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageParseBytes(b *testing.B) {
+	msg := NewMessage()
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := msg.ParseBytes(testMessageBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkReceivedMessage holds the last received message to hand it over as the transport handlers do.
+var benchmarkReceivedMessage *Message
+
+func BenchmarkMessageReadPacket(b *testing.B) {
+	from := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 3610, Zone: ""}
+	b.ReportAllocs()
+	for b.Loop() {
+		msg := NewMessage()
+		msg.SetParseMode(ParseModeStrict)
+		if err := msg.ParseBytes(testMessageBytes); err != nil {
+			b.Fatal(err)
+		}
+		msg.From.IP = from.IP
+		msg.From.Port = from.Port
+		msg.From.Zone = from.Zone
+		benchmarkReceivedMessage = msg
+	}
+}
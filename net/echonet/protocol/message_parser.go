@@ -11,7 +11,7 @@ import (
 // parseFrameHeaderBytes parses the specified frame header bytes.
 func (msg *Message) parseFrameHeaderBytes(data []byte) error {
 	if headerSize := len(data); headerSize < FrameHeaderSize {
-		return fmt.Errorf(errInvalidMessageSize, ErrInvalid, headerSize, FrameHeaderSize)
+		return fmt.Errorf(errInvalidMessageSize, ErrTruncated, headerSize, FrameHeaderSize)
 	}
 
 	// Check Headers
@@ -20,7 +20,10 @@ func (msg *Message) parseFrameHeaderBytes(data []byte) error {
 		return fmt.Errorf(errInvalidMessageHeader, ErrInvalid, 0, data[0], EHD1Echonet)
 	}
 
-	if data[1] != EHD2Format1 {
+	switch data[1] {
+	case EHD2Format1, EHD2Format2:
+		msg.EHD2Format1 = data[1]
+	default:
 		return fmt.Errorf(errInvalidMessageHeader, ErrInvalid, 1, data[1], EHD2Format1)
 	}
 
@@ -35,7 +38,7 @@ func (msg *Message) parseFrameHeaderBytes(data []byte) error {
 // parseFormat1HeaderBytes parses the specified header bytes.
 func (msg *Message) parseFormat1HeaderBytes(data []byte) error {
 	if headerSize := len(data); headerSize < Format1HeaderSize {
-		return fmt.Errorf(errInvalidMessageSize, ErrInvalid, (headerSize + FrameHeaderSize), (Format1HeaderSize + FrameHeaderSize))
+		return fmt.Errorf(errInvalidMessageSize, ErrTruncated, (headerSize + FrameHeaderSize), (Format1HeaderSize + FrameHeaderSize))
 	}
 
 	// SEOJ
@@ -61,11 +64,22 @@ func (msg *Message) parseFormat1HeaderBytes(data []byte) error {
 		return err
 	}
 
+	if msg.isStrictParseMode() && msg.opc == 0 && msg.esv.IsRequest() {
+		return fmt.Errorf(errNoProperty, ErrNoProperty, msg.esv.String())
+	}
+
 	return nil
 }
 
+// isStrictParseMode returns true when the message is parsed in the strict mode, otherwise false.
+func (msg *Message) isStrictParseMode() bool {
+	return msg.parseMode == ParseModeStrict
+}
+
 // parseFormat1PropertyBytes parses the specified property bytes.
+// In the lenient mode, the truncated properties are left empty and the trailing bytes are ignored.
 func (msg *Message) parseFormat1PropertyBytes(data []byte) error {
+	strict := msg.isStrictParseMode()
 	dataSize := len(data)
 
 	offset := 0
@@ -78,6 +92,9 @@ func (msg *Message) parseFormat1PropertyBytes(data []byte) error {
 		// EPC
 
 		if (dataSize - 1) < offset {
+			if strict {
+				return fmt.Errorf(errOPCMismatch, ErrOPCMismatch, msg.opc, n)
+			}
 			continue
 		}
 
@@ -87,6 +104,9 @@ func (msg *Message) parseFormat1PropertyBytes(data []byte) error {
 		// PDC
 
 		if (dataSize - 1) < offset {
+			if strict {
+				return fmt.Errorf(errTruncatedProperty, ErrTruncated, n, dataSize, (offset + 1))
+			}
 			continue
 		}
 
@@ -96,19 +116,45 @@ func (msg *Message) parseFormat1PropertyBytes(data []byte) error {
 		// EDT
 
 		if (dataSize - 1) < (offset + propDataSize - 1) {
+			if strict {
+				return fmt.Errorf(errTruncatedProperty, ErrTruncated, n, dataSize, (offset + propDataSize))
+			}
 			continue
 		}
 
-		prop.SetData(data[offset:(offset + propDataSize)])
+		propData := data[offset:(offset + propDataSize):(offset + propDataSize)]
+		if parsedProp, ok := prop.(*property); ok {
+			parsedProp.data = propData
+		} else {
+			prop.SetData(propData)
+		}
 
 		offset += propDataSize
 	}
 
+	// The write and read services have the second property list for the read properties after the first list.
+
+	if strict && offset < dataSize && !msg.esv.IsWriteReadService() {
+		return fmt.Errorf(errTrailingData, ErrTrailingData, (dataSize - offset), msg.opc)
+	}
+
 	return nil
 }
 
-// ParseBytes parses the specified bytes.
+// ParseBytes parses the specified bytes into the message with the parse mode of the message.
+// The message copies the bytes into the internal buffer, and the parsed properties and data refer to the buffer.
+// The buffer and properties are reused when the message is parsed again, so that parsing into an existing message
+// does not allocate any memory once the buffers have grown enough, but the properties and data returned by the previous
+// parsing must not be used after that.
 func (msg *Message) ParseBytes(data []byte) error {
+	msg.buf = append(msg.buf[:0], data...)
+	return msg.parseBufferBytes()
+}
+
+// parseBufferBytes parses the internal buffer bytes.
+func (msg *Message) parseBufferBytes() error {
+	data := msg.buf
+
 	// Frame header
 
 	err := msg.parseFrameHeaderBytes(data)
@@ -116,8 +162,16 @@ func (msg *Message) ParseBytes(data []byte) error {
 		return err
 	}
 
+	// Echonet Format2 EDATA
+
+	if msg.IsFormat2() {
+		msg.edata = data[FrameHeaderSize:len(data):len(data)]
+		return nil
+	}
+
 	// Echonet Format1 Header
 
+	msg.edata = nil
 	err = msg.parseFormat1HeaderBytes(data[FrameHeaderSize:])
 	if err != nil {
 		return err
@@ -5,6 +5,7 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
 	"io"
 )
@@ -19,57 +20,74 @@ func (msg *Message) parseFormat1PropertyReader(reader io.Reader) error {
 			continue
 		}
 
-		nRead, err := reader.Read(propertyHeader)
+		nRead, err := io.ReadFull(reader, propertyHeader)
 		if err != nil {
+			switch {
+			case errors.Is(err, io.EOF) && msg.isStrictParseMode():
+				return fmt.Errorf(errOPCMismatch, ErrOPCMismatch, msg.opc, n)
+			case errors.Is(err, io.ErrUnexpectedEOF):
+				return fmt.Errorf(errTruncatedProperty, ErrTruncated, n, nRead, Format1PropertyHeaderSize)
+			}
 			return err
 		}
-		if nRead < Format1PropertyHeaderSize {
-			return fmt.Errorf(errInvalidMessageSize, ErrInvalid, n, Format1PropertyHeaderSize)
-		}
 		prop.SetCode(PropertyCode(propertyHeader[0]))
 
 		propDataSize := int(propertyHeader[1])
 		propData := make([]byte, propDataSize)
-		nRead, err = reader.Read(propData)
+		nRead, err = io.ReadFull(reader, propData)
 		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return fmt.Errorf(errTruncatedProperty, ErrTruncated, n, nRead, propDataSize)
+			}
 			return err
 		}
-		if nRead < propDataSize {
-			return fmt.Errorf(errInvalidMessageSize, ErrInvalid, n, propDataSize)
-		}
 		prop.SetData(propData)
 	}
 
 	return nil
 }
 
-// ParseReader parses the specified bytes.
+// ParseReader parses the specified bytes with the parse mode of the message.
+// The trailing bytes are not checked even in the strict mode because the reader is a stream.
 func (msg *Message) ParseReader(reader io.Reader) error {
 	// Frame header
 
 	frameHeader := make([]byte, FrameHeaderSize)
-	n, err := reader.Read(frameHeader)
+	n, err := io.ReadFull(reader, frameHeader)
 	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf(errInvalidMessageSize, ErrTruncated, n, FrameHeaderSize)
+		}
 		return err
 	}
-	if n < FrameHeaderSize {
-		return fmt.Errorf(errInvalidMessageSize, ErrInvalid, n, FrameHeaderSize)
-	}
 	err = msg.parseFrameHeaderBytes(frameHeader)
 	if err != nil {
 		return err
 	}
 
+	// Echonet Format2 EDATA
+	// The Format2 message has no length field, so that the remaining bytes of the stream are the EDATA.
+
+	if msg.IsFormat2() {
+		edata, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		msg.SetFormat2Data(edata)
+		return nil
+	}
+
 	// Echonet Format1 Header
 
+	msg.edata = nil
 	format1Header := make([]byte, Format1HeaderSize)
-	n, err = reader.Read(format1Header)
+	n, err = io.ReadFull(reader, format1Header)
 	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf(errInvalidMessageSize, ErrTruncated, (n + FrameHeaderSize), Format1MinSize)
+		}
 		return err
 	}
-	if n < Format1HeaderSize {
-		return fmt.Errorf(errInvalidMessageSize, ErrInvalid, n, Format1HeaderSize)
-	}
 	err = msg.parseFormat1HeaderBytes(format1Header)
 	if err != nil {
 		return err
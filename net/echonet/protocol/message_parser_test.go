@@ -6,6 +6,7 @@ package protocol
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 )
 
@@ -86,3 +87,128 @@ func TestParseMReaderMessage(t *testing.T) {
 	}
 	testParsedMessage(t, msg)
 }
+
+func TestParseFormat2Message(t *testing.T) {
+	edata := []byte{0x01, 0x02, 0x03, 0x04, 0x05}
+	msgBytes := append([]byte{EHD1Echonet, EHD2Format2, 0x12, 0x34}, edata...)
+
+	parsers := map[string]func(*Message) error{
+		"bytes":  func(msg *Message) error { return msg.ParseBytes(msgBytes) },
+		"reader": func(msg *Message) error { return msg.ParseReader(bytes.NewReader(msgBytes)) },
+	}
+
+	for name, parse := range parsers {
+		t.Run(name, func(t *testing.T) {
+			msg := NewMessage()
+			if err := parse(msg); err != nil {
+				t.Fatal(err)
+			}
+			if !msg.IsFormat2() || msg.IsFormat1() {
+				t.Errorf("%X is not a format 2 message", msg.EHD())
+			}
+			if msg.TID() != 0x1234 {
+				t.Errorf("%04X != %04X", msg.TID(), 0x1234)
+			}
+			if !bytes.Equal(msg.Format2Data(), edata) {
+				t.Errorf("%X != %X", msg.Format2Data(), edata)
+			}
+			if !bytes.Equal(msg.Bytes(), msgBytes) {
+				t.Errorf("%X != %X", msg.Bytes(), msgBytes)
+			}
+		})
+	}
+
+	msg := NewFormat2Message(edata)
+	msg.SetTID(0x1234)
+	if !bytes.Equal(msg.Bytes(), msgBytes) {
+		t.Errorf("%X != %X", msg.Bytes(), msgBytes)
+	}
+
+	if err := NewMessage().ParseBytes([]byte{EHD1Echonet, 0x83, 0x00, 0x00}); err == nil {
+		t.Error("unknown EHD2 is accepted")
+	}
+}
+
+func TestParseBytesIntoExistingMessage(t *testing.T) {
+	msg := NewMessage()
+	if err := msg.ParseBytes(NewFormat2Message([]byte{0x01}).Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := msg.ParseBytes(testMessageBytes); err != nil {
+		t.Fatal(err)
+	}
+	testParsedMessage(t, msg)
+	if msg.IsFormat2() || msg.Format2Data() != nil {
+		t.Errorf("%X is not a format 1 message", msg.EHD())
+	}
+
+	buf := make([]byte, 0, len(testMessageBytes))
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := msg.ParseBytes(testMessageBytes); err != nil {
+			t.Fatal(err)
+		}
+		buf = msg.AppendBytes(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("%f != %d", allocs, 0)
+	}
+	if !bytes.Equal(buf, testMessageBytes) {
+		t.Errorf("%X != %X", buf, testMessageBytes)
+	}
+
+	msg.Reset()
+	if msg.OPC() != 0 || len(msg.Properties()) != 0 || msg.ESV() != 0 {
+		t.Errorf("%s is not reset", msg)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	newTestMessageBytes := func(esv ESV, opc byte, props ...byte) []byte {
+		return append([]byte{EHD1Echonet, EHD2Format1, 0x00, 0x01, 0x05, 0xFF, 0x01, 0x02, 0x91, 0x01, byte(esv), opc}, props...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"valid", newTestMessageBytes(ESVWriteRequest, 1, 0x80, 0x01, 0x30), nil},
+		{"truncated header", newTestMessageBytes(ESVReadRequest, 1)[:Format1MinSize-1], ErrTruncated},
+		{"truncated PDC", newTestMessageBytes(ESVReadRequest, 1, 0x80), ErrTruncated},
+		{"truncated EDT", newTestMessageBytes(ESVWriteRequest, 1, 0x80, 0x02, 0x30), ErrTruncated},
+		{"OPC mismatch", newTestMessageBytes(ESVReadRequest, 2, 0x80, 0x00), ErrOPCMismatch},
+		{"trailing data", newTestMessageBytes(ESVReadRequest, 1, 0x80, 0x00, 0x81, 0x00), ErrTrailingData},
+		{"no property", newTestMessageBytes(ESVReadRequest, 0), ErrNoProperty},
+		{"no property response", newTestMessageBytes(ESVReadResponse, 0), nil},
+		{"write and read", newTestMessageBytes(ESVWriteReadRequest, 1, 0x80, 0x01, 0x30, 0x01, 0x80, 0x00), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := NewMessage()
+			err := msg.ParseBytes(test.data)
+			if !errors.Is(err, test.err) {
+				t.Errorf("%v != %v", err, test.err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalid) {
+				t.Errorf("%v is not %v", err, ErrInvalid)
+			}
+
+			if test.err == nil || errors.Is(test.err, ErrTrailingData) {
+				return
+			}
+			if err := NewMessage().ParseReader(bytes.NewReader(test.data)); !errors.Is(err, test.err) {
+				t.Errorf("%v != %v", err, test.err)
+			}
+
+			if errors.Is(test.err, ErrTruncated) && len(test.data) < Format1MinSize {
+				return
+			}
+			msg.SetParseMode(ParseModeLenient)
+			if err := msg.ParseBytes(test.data); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
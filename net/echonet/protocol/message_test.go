@@ -6,6 +6,7 @@ package protocol
 
 import (
 	"bytes"
+	"net"
 	"testing"
 
 	"github.com/cybergarage/uecho-go/net/echonet/encoding"
@@ -130,3 +131,40 @@ func TestMessageEquals(t *testing.T) {
 		t.Errorf("%s != %s", msg1.String(), msg2.String())
 	}
 }
+
+func TestNewImpossibleMessage(t *testing.T) {
+	reqMsg := NewMessage()
+	reqMsg.SetESV(ESVWriteRequestResponseRequired)
+	reqProp := NewPropertyWithCode(0x80)
+	reqProp.SetData([]byte{0x30})
+	reqMsg.AddProperty(reqProp)
+
+	msg := NewImpossibleMessageWithMessage(reqMsg)
+	if msg.ESV() != ESVWriteRequestResponseRequiredError {
+		t.Errorf("%s", msg)
+	}
+	if msg.OPC() != reqMsg.OPC() {
+		t.Errorf("%d != %d", msg.OPC(), reqMsg.OPC())
+	}
+	prop := msg.Property(0)
+	if prop == nil || prop.Code() != reqProp.Code() || !bytes.Equal(prop.Data(), reqProp.Data()) {
+		t.Errorf("%s", msg)
+	}
+}
+
+func TestResponseMessageInterface(t *testing.T) {
+	reqMsg := NewMessage()
+	reqMsg.SetESV(ESVReadRequest)
+	reqMsg.Interface = &net.Interface{Name: "eth1"}
+
+	resMsgs := []*Message{
+		NewResponseMessageWithMessage(reqMsg),
+		NewImpossibleResponseMessageWithMessage(reqMsg),
+		NewImpossibleMessageWithMessage(reqMsg),
+	}
+	for _, resMsg := range resMsgs {
+		if resMsg.Interface != reqMsg.Interface {
+			t.Errorf("%v != %v", resMsg.Interface, reqMsg.Interface)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocol
+
+import (
+	"fmt"
+)
+
+// edtRule represents a rule of the property data (EDT) for an ESV.
+type edtRule int
+
+const (
+	edtOptional edtRule = iota
+	edtRequired
+	edtProhibited
+)
+
+// edtRuleOf returns the rule of the property data (EDT) for the specified ESV.
+// The error responses have both the accepted properties without EDT and the rejected properties with EDT,
+// and so that the EDT is optional for them.
+func edtRuleOf(esv ESV) edtRule {
+	switch esv {
+	case ESVWriteRequest, ESVWriteRequestResponseRequired, ESVWriteReadRequest:
+		return edtRequired
+	case ESVReadResponse, ESVNotification, ESVNotificationResponseRequired:
+		return edtRequired
+	case ESVReadRequest, ESVNotificationRequest:
+		return edtProhibited
+	case ESVWriteResponse, ESVNotificationResponse:
+		return edtProhibited
+	}
+	return edtOptional
+}
+
+// Validate returns an error when the message violates the rules of ECHONET Lite frames.
+// Validate checks the frame size, the ESV, the OPC bounds, the class groups of the SEOJ and DEOJ,
+// and the presence of the property data (EDT) for the ESV.
+func (msg *Message) Validate() error {
+	if msg.EHD1Echonet != EHD1Echonet {
+		return fmt.Errorf(errInvalidMessageHeader, ErrInvalid, 0, msg.EHD1Echonet, EHD1Echonet)
+	}
+
+	if size := msg.Size(); MaxMessageSize < size {
+		return fmt.Errorf(errMessageTooLarge, ErrInvalid, size, MaxMessageSize)
+	}
+
+	if msg.IsFormat2() {
+		return nil
+	}
+
+	if !msg.IsFormat1() {
+		return fmt.Errorf(errInvalidMessageHeader, ErrInvalid, 1, msg.EHD2Format1, EHD2Format1)
+	}
+
+	if err := msg.validateObjectCode("SEOJ", msg.SEOJ()); err != nil {
+		return err
+	}
+
+	if err := msg.validateObjectCode("DEOJ", msg.DEOJ()); err != nil {
+		return err
+	}
+
+	if !msg.esv.IsValid() {
+		return fmt.Errorf(errInvalidESV, ErrInvalid, msg.esv.String())
+	}
+
+	return msg.validateProperties()
+}
+
+// validateObjectCode returns an error when the specified object code has an unknown class or instance code.
+// The instance code 0x00 is allowed only for the DEOJ to specify all instances.
+func (msg *Message) validateObjectCode(name string, code ObjectCode) error {
+	if !code.IsValidClass() {
+		return fmt.Errorf(errInvalidObjectCode, ErrInvalid, name, code.String(), code.ClassGroupCode(), code.ClassCode())
+	}
+	instanceCode := code.InstanceCode()
+	if InstanceCodeMax < instanceCode || (instanceCode == InstanceCodeAll && name != "DEOJ") {
+		return fmt.Errorf(errInvalidInstanceCode, ErrInvalid, name, code.String(), instanceCode)
+	}
+	return nil
+}
+
+// validateProperties returns an error when the properties violate the OPC bounds or the EDT rule of the ESV.
+func (msg *Message) validateProperties() error {
+	props := msg.Properties()
+
+	if len(props) == 0 {
+		return fmt.Errorf(errNoProperty, ErrNoProperty, msg.esv.String())
+	}
+
+	if OPCMax < len(props) || int(msg.opc) != len(props) {
+		return fmt.Errorf(errOPCMismatch, ErrOPCMismatch, msg.opc, len(props))
+	}
+
+	rule := edtRuleOf(msg.esv)
+	for n, prop := range props {
+		switch size := prop.Size(); {
+		case PDCMax < size:
+			return fmt.Errorf(errEDTTooLarge, ErrInvalid, n, byte(prop.Code()), size, PDCMax)
+		case rule == edtRequired && size == 0:
+			return fmt.Errorf(errEDTRequired, ErrInvalid, msg.esv.String(), n, byte(prop.Code()))
+		case rule == edtProhibited && 0 < size:
+			return fmt.Errorf(errEDTProhibited, ErrInvalid, msg.esv.String(), n, byte(prop.Code()), size)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocol
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMessageValidate(t *testing.T) {
+	newTestMessage := func(seoj ObjectCode, deoj ObjectCode, esv ESV, props ...Property) *Message {
+		msg := NewMessage()
+		msg.SetSEOJ(seoj)
+		msg.SetDEOJ(deoj)
+		msg.SetESV(esv)
+		msg.AddProperties(props)
+		return msg
+	}
+
+	newTestProperty := func(data ...byte) Property {
+		prop := NewPropertyWithCode(0x80)
+		prop.SetData(data)
+		return prop
+	}
+
+	const (
+		testSEOJ = 0x0EF001
+		testDEOJ = 0x029101
+	)
+
+	tests := []struct {
+		name string
+		msg  *Message
+		err  error
+	}{
+		{"read request", newTestMessage(testSEOJ, testDEOJ, ESVReadRequest, newTestProperty()), nil},
+		{"write request", newTestMessage(testSEOJ, testDEOJ, ESVWriteRequestResponseRequired, newTestProperty(0x30)), nil},
+		{"all instances", newTestMessage(testSEOJ, 0x029100, ESVReadRequest, newTestProperty()), nil},
+		{"error response", newTestMessage(testSEOJ, testDEOJ, ESVReadRequestError, newTestProperty()), nil},
+		{"format 2", NewFormat2Message([]byte{0x01}), nil},
+		{"read request with EDT", newTestMessage(testSEOJ, testDEOJ, ESVReadRequest, newTestProperty(0x30)), ErrInvalid},
+		{"write request without EDT", newTestMessage(testSEOJ, testDEOJ, ESVWriteRequestResponseRequired, newTestProperty()), ErrInvalid},
+		{"write response with EDT", newTestMessage(testSEOJ, testDEOJ, ESVWriteResponse, newTestProperty(0x30)), ErrInvalid},
+		{"unknown ESV", newTestMessage(testSEOJ, testDEOJ, 0x00, newTestProperty()), ErrInvalid},
+		{"no property", newTestMessage(testSEOJ, testDEOJ, ESVReadRequest), ErrNoProperty},
+		{"unknown DEOJ", newTestMessage(testSEOJ, 0x000000, ESVReadRequest, newTestProperty()), ErrInvalid},
+		{"unknown DEOJ class group", newTestMessage(testSEOJ, 0x100101, ESVReadRequest, newTestProperty()), ErrInvalid},
+		{"SEOJ for all instances", newTestMessage(0x0EF000, testDEOJ, ESVReadRequest, newTestProperty()), ErrInvalid},
+		{"DEOJ instance", newTestMessage(testSEOJ, 0x029180, ESVReadRequest, newTestProperty()), ErrInvalid},
+		{"too large EDT", newTestMessage(testSEOJ, testDEOJ, ESVNotification, newTestProperty(make([]byte, PDCMax+1)...)), ErrInvalid},
+		{"too large message", NewFormat2Message(make([]byte, MaxMessageSize)), ErrInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.msg.Validate()
+			if test.err == nil {
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			if !errors.Is(err, test.err) {
+				t.Errorf("%v != %v", err, test.err)
+			}
+		})
+	}
+
+	msg := newTestMessage(testSEOJ, testDEOJ, ESVReadRequest, newTestProperty())
+	msg.opc = 2
+	if err := msg.Validate(); !errors.Is(err, ErrOPCMismatch) {
+		t.Errorf("%v != %v", err, ErrOPCMismatch)
+	}
+}
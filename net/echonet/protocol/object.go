@@ -17,6 +17,20 @@ const (
 	ObjectCodeUnknown = ObjectCodeMin
 )
 
+const (
+	ClassGroupCodeSensor         = 0x00
+	ClassGroupCodeAirConditioner = 0x01
+	ClassGroupCodeHousing        = 0x02
+	ClassGroupCodeCooking        = 0x03
+	ClassGroupCodeHealth         = 0x04
+	ClassGroupCodeManagement     = 0x05
+	ClassGroupCodeAudioVisual    = 0x06
+	ClassGroupCodeProfile        = 0x0E
+	ClassGroupCodeUserDefinition = 0x0F
+	InstanceCodeAll              = 0x00
+	InstanceCodeMax              = 0x7F
+)
+
 // ObjectCode is a type for object code.
 type ObjectCode uint
 
@@ -39,3 +53,31 @@ func (code ObjectCode) Bytes() []byte {
 	encoding.IntegerToByte(uint(code), codes)
 	return codes
 }
+
+// ClassGroupCode returns the class group code of the object code.
+func (code ObjectCode) ClassGroupCode() byte {
+	return byte((code >> 16) & 0xFF)
+}
+
+// ClassCode returns the class code of the object code.
+func (code ObjectCode) ClassCode() byte {
+	return byte((code >> 8) & 0xFF)
+}
+
+// InstanceCode returns the instance code of the object code.
+func (code ObjectCode) InstanceCode() byte {
+	return byte(code & 0xFF)
+}
+
+// IsValidClass returns true when the class group code is specified in ECHONET Lite and the class code is not unknown, otherwise false.
+func (code ObjectCode) IsValidClass() bool {
+	switch code.ClassGroupCode() {
+	case ClassGroupCodeSensor:
+		return code.ClassCode() != 0x00
+	case ClassGroupCodeAirConditioner, ClassGroupCodeHousing, ClassGroupCodeCooking,
+		ClassGroupCodeHealth, ClassGroupCodeManagement, ClassGroupCodeAudioVisual,
+		ClassGroupCodeProfile, ClassGroupCodeUserDefinition:
+		return true
+	}
+	return false
+}
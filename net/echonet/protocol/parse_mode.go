@@ -0,0 +1,28 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocol
+
+// ParseMode represents a mode of the message parsers.
+type ParseMode int
+
+const (
+	// ParseModeStrict rejects the truncated or inconsistent frames such as an OPC mismatch, trailing bytes
+	// and a request without any property.
+	ParseModeStrict ParseMode = iota
+	// ParseModeLenient accepts the malformed frames as far as possible for interoperability debugging.
+	// The truncated properties are left empty, and the trailing bytes are ignored.
+	ParseModeLenient
+)
+
+// String returns the string representation of the mode.
+func (mode ParseMode) String() string {
+	switch mode {
+	case ParseModeStrict:
+		return "strict"
+	case ParseModeLenient:
+		return "lenient"
+	}
+	return ""
+}
@@ -0,0 +1,218 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+// RateLimitAction represents an action for the request messages which exceed the rate limits.
+type RateLimitAction int
+
+const (
+	// RateLimitActionDrop drops the excess requests without any response.
+	RateLimitActionDrop RateLimitAction = iota
+	// RateLimitActionSNA answers the excess requests with the SNA responses.
+	RateLimitActionSNA
+)
+
+const (
+	// rateLimiterMaxSources is the maximum number of the source addresses to track the buckets and drops.
+	// The least recently seen source is removed when a new source exceeds the number.
+	rateLimiterMaxSources = 1024
+)
+
+// String returns the string representation of the action.
+func (action RateLimitAction) String() string {
+	switch action {
+	case RateLimitActionDrop:
+		return "drop"
+	case RateLimitActionSNA:
+		return "sna"
+	}
+	return ""
+}
+
+// RateLimit represents a token bucket rate limit.
+// The bucket is refilled at the specified rate per second up to the burst size, and each request consumes a token.
+// The rate limit is disabled when the rate is zero.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// IsEnabled returns true when the rate limit is enabled, otherwise false.
+func (limit RateLimit) IsEnabled() bool {
+	return 0 < limit.Rate
+}
+
+// tokenBucket represents a token bucket.
+type tokenBucket struct {
+	limit    RateLimit
+	tokens   float64
+	lastTime time.Time
+}
+
+// newTokenBucket returns a new full token bucket with the specified limit.
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		limit:    limit,
+		tokens:   float64(max(limit.Burst, 1)),
+		lastTime: now,
+	}
+}
+
+// refill adds the tokens for the elapsed time.
+func (bucket *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(bucket.lastTime).Seconds()
+	if 0 < elapsed {
+		bucket.tokens = min(bucket.tokens+(elapsed*bucket.limit.Rate), float64(max(bucket.limit.Burst, 1)))
+	}
+	bucket.lastTime = now
+}
+
+// hasToken returns true when the bucket has a token, otherwise false.
+func (bucket *tokenBucket) hasToken(now time.Time) bool {
+	bucket.refill(now)
+	return 1 <= bucket.tokens
+}
+
+// take consumes a token.
+func (bucket *tokenBucket) take() {
+	bucket.tokens--
+}
+
+// rateLimiterSource represents the token bucket and the drop counter of a source address.
+type rateLimiterSource struct {
+	addr   string
+	bucket *tokenBucket
+	drops  uint64
+}
+
+// rateLimiter represents a rate limiter for the request messages with the per-source and global token buckets.
+// The sources are kept in the least recently seen order to remove the bucket and drop counter of the oldest source together.
+type rateLimiter struct {
+	sync.Mutex
+	sourceLimit  RateLimit
+	globalLimit  RateLimit
+	sources      map[string]*list.Element
+	sourceList   *list.List
+	globalBucket *tokenBucket
+}
+
+// newRateLimiter returns a new rate limiter with the specified limits.
+func newRateLimiter(sourceLimit RateLimit, globalLimit RateLimit) *rateLimiter {
+	now := time.Now()
+	return &rateLimiter{
+		Mutex:        sync.Mutex{},
+		sourceLimit:  sourceLimit,
+		globalLimit:  globalLimit,
+		sources:      map[string]*list.Element{},
+		sourceList:   list.New(),
+		globalBucket: newTokenBucket(globalLimit, now),
+	}
+}
+
+// SetLimits updates the per-source and global limits, and resets the token buckets.
+func (limiter *rateLimiter) SetLimits(sourceLimit RateLimit, globalLimit RateLimit) {
+	limiter.Lock()
+	defer limiter.Unlock()
+	if limiter.sourceLimit == sourceLimit && limiter.globalLimit == globalLimit {
+		return
+	}
+	limiter.sourceLimit = sourceLimit
+	limiter.globalLimit = globalLimit
+	for elem := limiter.sourceList.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*rateLimiterSource).bucket = nil
+	}
+	limiter.globalBucket = newTokenBucket(globalLimit, time.Now())
+}
+
+// source returns the tracked source of the specified address, and adds a new source removing the least recently seen source if needed.
+func (limiter *rateLimiter) source(addr string) *rateLimiterSource {
+	if elem, ok := limiter.sources[addr]; ok {
+		limiter.sourceList.MoveToFront(elem)
+		return elem.Value.(*rateLimiterSource)
+	}
+	for rateLimiterMaxSources <= limiter.sourceList.Len() {
+		oldest := limiter.sourceList.Back()
+		limiter.sourceList.Remove(oldest)
+		delete(limiter.sources, oldest.Value.(*rateLimiterSource).addr)
+	}
+	source := &rateLimiterSource{
+		addr:   addr,
+		bucket: nil,
+		drops:  0,
+	}
+	limiter.sources[addr] = limiter.sourceList.PushFront(source)
+	return source
+}
+
+// Allow returns true when the request from the specified source is allowed, otherwise false.
+// The token is consumed only when both the per-source and global buckets have a token.
+// The number of the dropped requests from the source is returned together when the request is not allowed.
+func (limiter *rateLimiter) Allow(src string) (bool, uint64) {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	now := time.Now()
+
+	var source *rateLimiterSource
+	if limiter.sourceLimit.IsEnabled() {
+		source = limiter.source(src)
+		if source.bucket == nil {
+			source.bucket = newTokenBucket(limiter.sourceLimit, now)
+		}
+		if !source.bucket.hasToken(now) {
+			source.drops++
+			return false, source.drops
+		}
+	}
+
+	if limiter.globalLimit.IsEnabled() {
+		if !limiter.globalBucket.hasToken(now) {
+			if source == nil {
+				source = limiter.source(src)
+			}
+			source.drops++
+			return false, source.drops
+		}
+		limiter.globalBucket.take()
+	}
+
+	if source != nil {
+		source.bucket.take()
+	}
+
+	return true, 0
+}
+
+// Drops returns the number of the dropped requests for each tracked source address.
+func (limiter *rateLimiter) Drops() map[string]uint64 {
+	limiter.Lock()
+	defer limiter.Unlock()
+	drops := map[string]uint64{}
+	for addr, elem := range limiter.sources {
+		if source := elem.Value.(*rateLimiterSource); 0 < source.drops {
+			drops[addr] = source.drops
+		}
+	}
+	return drops
+}
+
+// isRateLimitedMessage returns true when the specified message is a request message to be rate limited, otherwise false.
+// The responses and notifications are not rate limited not to lose the responses for the own requests.
+// The arbitrary message format (Format 2) messages are always rate limited because they have no service code.
+func isRateLimitedMessage(msg *protocol.Message) bool {
+	if msg.IsFormat2() {
+		return true
+	}
+	esv := msg.ESV()
+	return esv.IsReadRequest() || esv.IsWriteRequest() || esv.IsNotificationRequest() || esv == protocol.ESVNotificationResponseRequired
+}
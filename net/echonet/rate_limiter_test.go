@@ -0,0 +1,131 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestRateLimiter(t *testing.T) {
+	const testRate = 0.001
+
+	t.Run("source", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{Rate: testRate, Burst: 2}, RateLimit{})
+		for n, expected := range []bool{true, true, false, false} {
+			if allowed, _ := limiter.Allow("192.168.1.10"); allowed != expected {
+				t.Errorf("[%d] %t", n, !expected)
+			}
+		}
+		if allowed, _ := limiter.Allow("192.168.1.20"); !allowed {
+			t.Errorf("%s is limited", "192.168.1.20")
+		}
+		drops := limiter.Drops()
+		if drops["192.168.1.10"] != 2 || drops["192.168.1.20"] != 0 {
+			t.Errorf("%v", drops)
+		}
+	})
+
+	t.Run("global", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{Rate: testRate, Burst: 2}, RateLimit{Rate: testRate, Burst: 3})
+		for n, src := range []string{"192.168.1.10", "192.168.1.20", "192.168.1.10", "192.168.1.30", "192.168.1.40"} {
+			expected := n < 3
+			if allowed, _ := limiter.Allow(src); allowed != expected {
+				t.Errorf("[%d] %s %t", n, src, !expected)
+			}
+		}
+		drops := limiter.Drops()
+		if drops["192.168.1.30"] != 1 || drops["192.168.1.40"] != 1 {
+			t.Errorf("%v", drops)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{}, RateLimit{})
+		for range 100 {
+			if allowed, _ := limiter.Allow("192.168.1.10"); !allowed {
+				t.Error("request is limited")
+			}
+		}
+		if drops := limiter.Drops(); len(drops) != 0 {
+			t.Errorf("%v", drops)
+		}
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{Rate: testRate, Burst: 1}, RateLimit{})
+		limiter.Allow("192.168.1.10")
+		if _, drops := limiter.Allow("192.168.1.10"); drops != 1 {
+			t.Errorf("%d != %d", drops, 1)
+		}
+		for n := range rateLimiterMaxSources {
+			limiter.Allow(fmt.Sprintf("10.0.%d.%d", n/256, n%256))
+		}
+		if n := len(limiter.sources); n != rateLimiterMaxSources {
+			t.Errorf("%d != %d", n, rateLimiterMaxSources)
+		}
+		// The oldest source is removed together with the drop counter, and starts with a new bucket.
+		if _, ok := limiter.Drops()["192.168.1.10"]; ok {
+			t.Errorf("%v", limiter.Drops())
+		}
+		if allowed, _ := limiter.Allow("192.168.1.10"); !allowed {
+			t.Errorf("%s is limited", "192.168.1.10")
+		}
+	})
+}
+
+func TestLocalNodeRateLimit(t *testing.T) {
+	dev, err := NewDevice(WithDeviceCode(testLightDeviceCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	nodeConf := newTestDefaultConfig()
+	nodeConf.(*config).SetSourceRateLimit(RateLimit{Rate: 0.001, Burst: 2})
+	nodeConf.(*config).SetRateLimitAction(RateLimitActionSNA)
+
+	node := NewLocalNode(WithLocalNodeConfig(nodeConf), WithLocalNodeDevices(dev))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	expectedESVs := []protocol.ESV{protocol.ESVReadResponse, protocol.ESVReadResponse, protocol.ESVReadRequestError}
+	for n, expectedESV := range expectedESVs {
+		reqMsg := NewMessage(
+			WithMessageDEOJ(testLightDeviceCode),
+			WithMessageESV(protocol.ESVReadRequest),
+			WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus))),
+		)
+		resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resMsg.ESV() != expectedESV {
+			t.Errorf("[%d] %02X != %02X", n, resMsg.ESV(), expectedESV)
+		}
+	}
+
+	drops := node.RateLimitDrops()
+	if len(drops) != 1 {
+		t.Fatalf("%v", drops)
+	}
+	for src, count := range drops {
+		if count != 1 {
+			t.Errorf("%s : %d != %d", src, count, 1)
+		}
+	}
+}
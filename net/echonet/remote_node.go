@@ -39,7 +39,7 @@ func newRemoteNode() *remoteNode {
 // newRemoteNodeWithRequestMessage returns a new node with the specified request message.
 func newRemoteNodeWithRequestMessage(msg *protocol.Message) *remoteNode {
 	node := newRemoteNode()
-	node.SetAddress(msg.From.IP.String())
+	node.SetAddress(msg.SourceAddress())
 	node.SetPort(msg.From.Port)
 	return node
 }
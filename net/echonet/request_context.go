@@ -0,0 +1,176 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+// RequestContext represents a context of a received request message.
+// RequestContext is passed to the object handlers and the property handlers.
+type RequestContext interface {
+	context.Context
+	// SourceNode returns the source node of the request message.
+	SourceNode() Node
+	// SourceAddress returns the source address of the request message.
+	SourceAddress() string
+	// SourcePort returns the source port of the request message.
+	SourcePort() int
+	// SEOJ returns the source object code of the request message.
+	SEOJ() ObjectCode
+	// DEOJ returns the destination object code of the request message.
+	DEOJ() ObjectCode
+	// TID returns the transaction ID of the request message.
+	TID() uint
+	// ESV returns the service code of the request message.
+	ESV() protocol.ESV
+	// Message returns the whole request message.
+	Message() *protocol.Message
+	// PacketType returns the packet type of the request message.
+	PacketType() int
+	// IsMulticast returns true when the request message was received by multicast, otherwise false.
+	IsMulticast() bool
+	// IsUDPUnicast returns true when the request message was received by UDP unicast, otherwise false.
+	IsUDPUnicast() bool
+	// IsTCPUnicast returns true when the request message was received by TCP unicast, otherwise false.
+	IsTCPUnicast() bool
+	// Interface returns the interface which received the request message.
+	Interface() *net.Interface
+	// Defer defers the response of the request message, and returns the responder to send the response later.
+	// Defer should be called in the object request handlers, and returns the same responder when it is called again.
+	Defer() Responder
+}
+
+type requestContextKey struct{}
+
+type requestContext struct {
+	context.Context
+	sync.Mutex
+	node      *localNode
+	msg       *protocol.Message
+	responder *responder
+}
+
+// newRequestContext returns a new request context with the specified parent context and request message.
+func newRequestContext(ctx context.Context, node *localNode, msg *protocol.Message) *requestContext {
+	return &requestContext{
+		Context:   ctx,
+		Mutex:     sync.Mutex{},
+		node:      node,
+		msg:       msg,
+		responder: nil,
+	}
+}
+
+// RequestContextFrom returns the request context in the specified context.
+// The context passed to the property handlers can be converted into the request context by the function.
+func RequestContextFrom(ctx context.Context) (RequestContext, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	reqCtx, ok := ctx.Value(requestContextKey{}).(RequestContext)
+	return reqCtx, ok
+}
+
+// Value returns the value associated with the specified key.
+func (ctx *requestContext) Value(key any) any {
+	if _, ok := key.(requestContextKey); ok {
+		return ctx
+	}
+	return ctx.Context.Value(key)
+}
+
+// SourceNode returns the source node of the request message.
+func (ctx *requestContext) SourceNode() Node {
+	return newRemoteNodeWithRequestMessage(ctx.msg)
+}
+
+// SourceAddress returns the source address of the request message.
+func (ctx *requestContext) SourceAddress() string {
+	return ctx.msg.SourceAddress()
+}
+
+// SourcePort returns the source port of the request message.
+func (ctx *requestContext) SourcePort() int {
+	return ctx.msg.SourcePort()
+}
+
+// SEOJ returns the source object code of the request message.
+func (ctx *requestContext) SEOJ() ObjectCode {
+	return ctx.msg.SEOJ()
+}
+
+// DEOJ returns the destination object code of the request message.
+func (ctx *requestContext) DEOJ() ObjectCode {
+	return ctx.msg.DEOJ()
+}
+
+// TID returns the transaction ID of the request message.
+func (ctx *requestContext) TID() uint {
+	return ctx.msg.TID()
+}
+
+// ESV returns the service code of the request message.
+func (ctx *requestContext) ESV() protocol.ESV {
+	return ctx.msg.ESV()
+}
+
+// Message returns the whole request message.
+func (ctx *requestContext) Message() *protocol.Message {
+	return ctx.msg
+}
+
+// PacketType returns the packet type of the request message.
+func (ctx *requestContext) PacketType() int {
+	return ctx.msg.PacketType()
+}
+
+// IsMulticast returns true when the request message was received by multicast, otherwise false.
+func (ctx *requestContext) IsMulticast() bool {
+	return ctx.msg.IsMulticastPacket()
+}
+
+// IsUDPUnicast returns true when the request message was received by UDP unicast, otherwise false.
+func (ctx *requestContext) IsUDPUnicast() bool {
+	return ctx.msg.IsUDPUnicastPacket()
+}
+
+// IsTCPUnicast returns true when the request message was received by TCP unicast, otherwise false.
+func (ctx *requestContext) IsTCPUnicast() bool {
+	return ctx.msg.IsTCPUnicastPacket()
+}
+
+// Interface returns the interface which received the request message.
+func (ctx *requestContext) Interface() *net.Interface {
+	return ctx.msg.Interface
+}
+
+// Defer defers the response of the request message, and returns the responder to send the response later.
+func (ctx *requestContext) Defer() Responder {
+	ctx.Lock()
+	defer ctx.Unlock()
+	if ctx.responder == nil {
+		ctx.responder = newResponder(ctx.node, ctx, ctx.node.MaxResponseDeferral())
+	}
+	return ctx.responder
+}
+
+// deferredResponder returns the responder when the response of the request message is deferred, otherwise nil.
+func (ctx *requestContext) deferredResponder() *responder {
+	ctx.Lock()
+	defer ctx.Unlock()
+	return ctx.responder
+}
+
+// cancelDeferral cancels the deferred response not to send any response.
+func (ctx *requestContext) cancelDeferral() {
+	if res := ctx.deferredResponder(); res != nil {
+		res.cancel()
+	}
+}
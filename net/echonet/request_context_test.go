@@ -0,0 +1,107 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestRequestContext(t *testing.T) {
+	var mutex sync.Mutex
+	reqCtxs := []RequestContext{}
+	propCtxs := []RequestContext{}
+
+	dev, err := NewDevice(
+		WithDeviceCode(testLightDeviceCode),
+		WithDeviceRequestHandler(func(ctx RequestContext, obj Object, esv protocol.ESV, prop protocol.Property) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reqCtxs = append(reqCtxs, ctx)
+			return nil
+		}),
+		WithDevicePropertyWriteHandler(DeviceOperatingStatus, func(ctx context.Context, prop Property, data []byte) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reqCtx, ok := RequestContextFrom(ctx)
+			if !ok {
+				t.Error("no request context")
+				return nil
+			}
+			propCtxs = append(propCtxs, reqCtx)
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeDevices(dev))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	reqMsg := NewMessage(
+		WithMessageDEOJ(testLightDeviceCode),
+		WithMessageESV(protocol.ESVWriteRequestResponseRequired),
+		WithMessageProperties(
+			NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOff})),
+			NewProperty(WithPropertyCode(DeviceInstallationLocation), WithPropertyData([]byte{0x00})),
+		),
+	)
+	resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(reqCtxs) != 2 {
+		t.Fatalf("%d != %d", len(reqCtxs), 2)
+	}
+	if len(propCtxs) != 1 {
+		t.Fatalf("%d != %d", len(propCtxs), 1)
+	}
+
+	for _, ctx := range append(reqCtxs, propCtxs...) {
+		if ctx.TID() != resMsg.TID() {
+			t.Errorf("%d != %d", ctx.TID(), resMsg.TID())
+		}
+		if ctx.SEOJ() != resMsg.DEOJ() {
+			t.Errorf("%s != %s", ctx.SEOJ(), resMsg.DEOJ())
+		}
+		if ctx.DEOJ() != testLightDeviceCode {
+			t.Errorf("%s != %s", ctx.DEOJ(), ObjectCode(testLightDeviceCode))
+		}
+		if ctx.ESV() != protocol.ESVWriteRequestResponseRequired {
+			t.Errorf("%02X != %02X", ctx.ESV(), protocol.ESVWriteRequestResponseRequired)
+		}
+		if n := len(ctx.Message().Properties()); n != 2 {
+			t.Errorf("%d != %d", n, 2)
+		}
+		if ctx.IsMulticast() {
+			t.Errorf("%s is received by multicast", ctx.Message())
+		}
+		if ctx.SourceNode().Port() != ctx.SourcePort() {
+			t.Errorf("%d != %d", ctx.SourceNode().Port(), ctx.SourcePort())
+		}
+		if ctx.SourceNode().Address() != ctx.SourceAddress() {
+			t.Errorf("%s != %s", ctx.SourceNode().Address(), ctx.SourceAddress())
+		}
+	}
+}
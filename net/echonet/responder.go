@@ -0,0 +1,171 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cybergarage/go-logger/log"
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+const (
+	// DefaultMaxResponseDeferral is the default maximum time to defer the responses of the request messages.
+	DefaultMaxResponseDeferral = time.Millisecond * 3000
+)
+
+const (
+	errResponderSent     = "%w: response of the request (%s) is already sent"
+	errResponderTimeout  = "%w: response of the request (%s) is not completed in %s"
+	errResponderCanceled = "%w: response of the request (%s) is canceled by the request handler error"
+)
+
+// Responder represents a deferred response of a request message.
+// The node sends no response when the request handler defers the response, and the responder sends the response later.
+// The node sends a SNA response automatically when the responder is not completed until the deadline.
+// The response of a TCP request is returned over the same TCP connection, so that the connection waits for the responder.
+// The responder is canceled without any response when the request handler returns an error after deferring the response.
+type Responder interface {
+	// Complete calls the property write handlers, and sends the standard response of the request message.
+	Complete() error
+	// Abort sends the SNA response of the request message.
+	Abort() error
+	// Deadline returns the time when the responder is aborted automatically.
+	Deadline() time.Time
+}
+
+type responder struct {
+	sync.Mutex
+	node     *localNode
+	ctx      *requestContext
+	deadline time.Time
+	timer    *time.Timer
+	resCh    chan *protocol.Message
+	sent     bool
+	timedOut bool
+	canceled bool
+}
+
+// newResponder returns a new responder for the specified request context, and starts the timer to abort the responder.
+func newResponder(node *localNode, ctx *requestContext, d time.Duration) *responder {
+	res := &responder{
+		Mutex:    sync.Mutex{},
+		node:     node,
+		ctx:      ctx,
+		deadline: time.Now().Add(d),
+		timer:    nil,
+		resCh:    nil,
+		sent:     false,
+		timedOut: false,
+		canceled: false,
+	}
+	if ctx.msg.IsTCPUnicastPacket() {
+		res.resCh = make(chan *protocol.Message, 1)
+	}
+	res.timer = time.AfterFunc(d, res.expire)
+	return res
+}
+
+// Deadline returns the time when the responder is aborted automatically.
+func (res *responder) Deadline() time.Time {
+	return res.deadline
+}
+
+// Complete calls the property write handlers, and sends the standard response of the request message.
+func (res *responder) Complete() error {
+	if err := res.finish(); err != nil {
+		return err
+	}
+	reqMsg := res.ctx.Message()
+	resMsg, err := res.node.respondRequestMessage(res.ctx, reqMsg)
+	// The waiting TCP connection is released even when no response message is returned.
+	if sendErr := res.send(resMsg); err == nil {
+		err = sendErr
+	}
+	return err
+}
+
+// Abort sends the SNA response of the request message.
+func (res *responder) Abort() error {
+	if err := res.finish(); err != nil {
+		return err
+	}
+	return res.send(protocol.NewImpossibleMessageWithMessage(res.ctx.Message()))
+}
+
+// expire sends the SNA response when the responder is not completed until the deadline.
+func (res *responder) expire() {
+	res.Lock()
+	if res.sent || res.canceled {
+		res.Unlock()
+		return
+	}
+	res.timedOut = true
+	res.Unlock()
+
+	reqMsg := res.ctx.Message()
+	log.Warnf("%v", fmt.Errorf(errResponderTimeout, ErrTimeout, reqMsg, res.node.MaxResponseDeferral()))
+	if err := res.send(protocol.NewImpossibleMessageWithMessage(reqMsg)); err != nil {
+		log.Errorf("%v", err)
+	}
+}
+
+// finish marks the responder as sent, and returns an error when the response is already sent.
+func (res *responder) finish() error {
+	res.Lock()
+	defer res.Unlock()
+	reqMsg := res.ctx.Message()
+	if res.timedOut {
+		return fmt.Errorf(errResponderTimeout, ErrTimeout, reqMsg, res.node.MaxResponseDeferral())
+	}
+	if res.canceled {
+		return fmt.Errorf(errResponderCanceled, ErrInvalid, reqMsg)
+	}
+	if res.sent {
+		return fmt.Errorf(errResponderSent, ErrInvalid, reqMsg)
+	}
+	res.sent = true
+	res.timer.Stop()
+	return nil
+}
+
+// cancel stops the timer without sending any response when the response is not sent yet.
+func (res *responder) cancel() {
+	res.Lock()
+	defer res.Unlock()
+	if res.sent || res.timedOut {
+		return
+	}
+	res.canceled = true
+	res.timer.Stop()
+}
+
+// isSynchronous returns true when the response is returned to the waiting TCP connection, otherwise false.
+func (res *responder) isSynchronous() bool {
+	return res.resCh != nil
+}
+
+// wait waits for the response message of the TCP request, and returns it.
+func (res *responder) wait() *protocol.Message {
+	return <-res.resCh
+}
+
+// send sends the specified response message to the source node of the request message.
+// The response message of the TCP request is returned to the waiting connection instead.
+func (res *responder) send(resMsg *protocol.Message) error {
+	if res.isSynchronous() {
+		res.resCh <- resMsg
+		return nil
+	}
+	if resMsg == nil {
+		return nil
+	}
+	reqMsg := res.ctx.Message()
+	_, err := res.node.server.SendMessage(reqMsg.SourceAddress(), reqMsg.SourcePort(), resMsg)
+	res.node.metrics.countSentResponse(resMsg)
+	return err
+}
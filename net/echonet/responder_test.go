@@ -0,0 +1,183 @@
+// Copyright (C) 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package echonet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestResponder(t *testing.T) {
+	t.Run("UDP", func(t *testing.T) {
+		testResponderWithConfig(t, newTestDefaultConfig())
+	})
+	t.Run("TCP", func(t *testing.T) {
+		// The deferred response is returned over the same TCP connection.
+		conf := newTestDefaultConfig()
+		conf.TransportConfig().SetTCPEnabled(true)
+		testResponderWithConfig(t, conf)
+	})
+}
+
+func testResponderWithConfig(t *testing.T, conf Config) {
+	t.Helper()
+
+	testResponderDeferral := time.Millisecond * 500
+
+	type deferredRequest struct {
+		Responder
+		obj  Object
+		prop protocol.Property
+	}
+	responders := make(chan deferredRequest, 1)
+
+	dev, err := NewDevice(
+		WithDeviceCode(testLightDeviceCode),
+		WithDeviceRequestHandler(func(ctx RequestContext, obj Object, esv protocol.ESV, prop protocol.Property) error {
+			if !esv.IsWriteRequest() {
+				return nil
+			}
+			responders <- deferredRequest{Responder: ctx.Defer(), obj: obj, prop: prop}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf.(*config).SetMaxResponseDeferral(testResponderDeferral)
+
+	ctrl := NewController(WithControllerConfig(conf))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeDevices(dev))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	postWriteMessage := func(t *testing.T, status byte) Message {
+		t.Helper()
+		reqMsg := NewMessage(
+			WithMessageDEOJ(testLightDeviceCode),
+			WithMessageESV(protocol.ESVWriteRequestResponseRequired),
+			WithMessageProperties(
+				NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{status})),
+			),
+		)
+		resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resMsg
+	}
+
+	t.Run("Complete", func(t *testing.T) {
+		go func() {
+			res := <-responders
+			time.Sleep(testResponderDeferral / 2)
+			if err := res.obj.SetPropertyData(res.prop.Code(), res.prop.Data()); err != nil {
+				t.Error(err)
+			}
+			if err := res.Complete(); err != nil {
+				t.Error(err)
+			}
+			if err := res.Complete(); !errors.Is(err, ErrInvalid) {
+				t.Errorf("response is sent again (%v)", err)
+			}
+		}()
+		resMsg := postWriteMessage(t, DeviceOperatingStatusOff)
+		if resMsg.ESV() != protocol.ESVWriteResponse {
+			t.Fatalf("%s", resMsg)
+		}
+		status, err := dev.LookupPropertyByte(DeviceOperatingStatus)
+		if err != nil || status != DeviceOperatingStatusOff {
+			t.Errorf("%X != %X", status, DeviceOperatingStatusOff)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		resMsg := postWriteMessage(t, DeviceOperatingStatusOn)
+		if resMsg.ESV() != protocol.ESVWriteRequestResponseRequiredError {
+			t.Fatalf("%s", resMsg)
+		}
+		res := <-responders
+		if err := res.Complete(); !errors.Is(err, ErrTimeout) {
+			t.Errorf("response is sent after the deadline (%v)", err)
+		}
+		status, err := dev.LookupPropertyByte(DeviceOperatingStatus)
+		if err != nil || status != DeviceOperatingStatusOff {
+			t.Errorf("%X != %X", status, DeviceOperatingStatusOff)
+		}
+	})
+
+	// The requests are not sent again by UDP when the responses are returned over TCP.
+
+	if conf.TransportConfig().TCPEnabled() && ctrl.Metrics().Retries != 0 {
+		t.Errorf("%d != %d", ctrl.Metrics().Retries, 0)
+	}
+}
+
+func TestResponderCanceledByHandlerError(t *testing.T) {
+	errHandler := errors.New("handler error")
+	responders := make(chan Responder, 1)
+
+	dev, err := NewDevice(
+		WithDeviceCode(testLightDeviceCode),
+		WithDeviceRequestHandler(func(ctx RequestContext, obj Object, esv protocol.ESV, prop protocol.Property) error {
+			if !esv.IsWriteRequest() {
+				return nil
+			}
+			responders <- ctx.Defer()
+			return errHandler
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vnet := NewVirtualNetwork()
+
+	conf := NewDefaultConfig()
+	conf.TransportConfig().SetRequestTimeout(time.Millisecond * 300)
+	conf.(*config).SetMaxResponseDeferral(time.Millisecond * 100)
+
+	ctrl := NewController(WithControllerConfig(conf), WithControllerTransport(vnet.NewTransport()))
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeDevices(dev), WithLocalNodeTransport(vnet.NewTransport()))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	// No SNA response is sent after the deadline because the responder is canceled by the handler error.
+
+	reqMsg := NewMessage(
+		WithMessageDEOJ(testLightDeviceCode),
+		WithMessageESV(protocol.ESVWriteRequestResponseRequired),
+		WithMessageProperties(
+			NewProperty(WithPropertyCode(DeviceOperatingStatus), WithPropertyData([]byte{DeviceOperatingStatusOff})),
+		),
+	)
+	if resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg); !errors.Is(err, ErrTimeout) {
+		t.Errorf("%v : %v", resMsg, err)
+	}
+
+	res := <-responders
+	if err := res.Complete(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("response is sent after the handler error (%v)", err)
+	}
+}
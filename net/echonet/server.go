@@ -5,25 +5,77 @@
 package echonet
 
 import (
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 	"github.com/cybergarage/uecho-go/net/echonet/transport"
 )
 
+// MessageObserver is an interface to observe the message frames which are sent and received by the node.
+type MessageObserver = transport.MessageObserver
+
+// ObservedMessage represents a message frame which is sent or received by the node.
+type ObservedMessage = transport.ObservedMessage
+
+// MessageDirection represents a direction of the observed message.
+type MessageDirection = transport.MessageDirection
+
+const (
+	MessageReceived = transport.MessageReceived
+	MessageSent     = transport.MessageSent
+)
+
+// Transport is an interface to send and receive the messages of a node.
+type Transport = transport.Transport
+
+// VirtualNetwork represents an in-memory virtual LAN to run many nodes and controllers in one process without any socket.
+type VirtualNetwork = transport.VirtualNetwork
+
+// NewVirtualNetwork returns a new empty virtual network. Use VirtualNetwork::NewTransport() to create a transport for each node and controller.
+func NewVirtualNetwork() *VirtualNetwork {
+	return transport.NewVirtualNetwork()
+}
+
+// interfaceEventNotifier is implemented by the transports which watch the interface changes.
+type interfaceEventNotifier interface {
+	SetInterfaceEventHandler(h transport.InterfaceEventHandler)
+}
+
+// addressAnnouncer is implemented by the transports which announce the messages from the specified bound address.
+type addressAnnouncer interface {
+	AnnounceMessageFrom(addr string, msg *protocol.Message) error
+}
+
 // server is an instance for Echonet node.
 type server struct {
-	*transport.MessageManager
+	Transport
 }
 
 // newServer returns a new server.
 func newServer() *server {
 	server := &server{
-		MessageManager: transport.NewMessageManager(),
+		Transport: transport.NewMessageManager(),
 	}
 	return server
 }
 
+// setInterfaceEventHandler sets the specified handler when the transport watches the interface changes.
+func (server *server) setInterfaceEventHandler(h transport.InterfaceEventHandler) {
+	if notifier, ok := server.Transport.(interfaceEventNotifier); ok {
+		notifier.SetInterfaceEventHandler(h)
+	}
+}
+
+// announceMessageFrom announces the message from the specified bound address, or from all bound addresses
+// when the transport cannot select the address.
+func (server *server) announceMessageFrom(addr string, msg *protocol.Message) error {
+	if announcer, ok := server.Transport.(addressAnnouncer); ok {
+		return announcer.AnnounceMessageFrom(addr, msg)
+	}
+	return server.Transport.AnnounceMessage(msg)
+}
+
 // Start starts the server.
 func (server *server) Start() error {
-	err := server.MessageManager.Start()
+	err := server.Transport.Start()
 	if err != nil {
 		return err
 	}
@@ -33,7 +85,7 @@ func (server *server) Start() error {
 
 // Stop stops the server.
 func (server *server) Stop() error {
-	err := server.MessageManager.Stop()
+	err := server.Transport.Stop()
 	if err != nil {
 		return err
 	}
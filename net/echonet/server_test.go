@@ -5,7 +5,13 @@
 package echonet
 
 import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
 	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 func TestNewServer(t *testing.T) {
@@ -23,3 +29,79 @@ func TestNewServer(t *testing.T) {
 		return
 	}
 }
+
+type testMessageObserver struct {
+	sync.Mutex
+	msgs []ObservedMessage
+}
+
+func (obs *testMessageObserver) MessageObserved(msg *ObservedMessage) {
+	obs.Lock()
+	defer obs.Unlock()
+	observed := *msg
+	observed.Bytes = bytes.Clone(msg.Bytes)
+	obs.msgs = append(obs.msgs, observed)
+}
+
+func (obs *testMessageObserver) Messages() []ObservedMessage {
+	obs.Lock()
+	defer obs.Unlock()
+	return slices.Clone(obs.msgs)
+}
+
+func TestMessageObserver(t *testing.T) {
+	dev, err := NewDevice(WithDeviceCode(testLightDeviceCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newTestDefaultConfig()
+
+	ctrl := NewController(WithControllerConfig(conf))
+	obs := &testMessageObserver{}
+	ctrl.AddMessageObserver(obs)
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctrl.Stop()
+
+	node := NewLocalNode(WithLocalNodeConfig(conf), WithLocalNodeDevices(dev))
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Stop()
+
+	reqMsg := NewMessage(
+		WithMessageDEOJ(testLightDeviceCode),
+		WithMessageESV(protocol.ESVReadRequest),
+		WithMessageProperties(NewProperty(WithPropertyCode(DeviceOperatingStatus))),
+	)
+	resMsg, err := ctrl.PostMessage(context.Background(), node, reqMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hasMessage := func(dir MessageDirection, msg Message) bool {
+		for _, observed := range obs.Messages() {
+			if observed.Direction == dir && bytes.Equal(observed.Bytes, msg.ToProtocol().Bytes()) {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasMessage(MessageSent, reqMsg) {
+		t.Errorf("%s is not observed", reqMsg)
+	}
+	if !hasMessage(MessageReceived, resMsg) {
+		t.Errorf("%s is not observed", resMsg)
+	}
+
+	ctrl.RemoveMessageObserver(obs)
+	n := len(obs.Messages())
+	if _, err := ctrl.PostMessage(context.Background(), node, reqMsg); err != nil {
+		t.Fatal(err)
+	}
+	if len(obs.Messages()) != n {
+		t.Errorf("%d != %d", len(obs.Messages()), n)
+	}
+}
@@ -28,7 +28,7 @@ func newStandardObject(clsName string, grpCode byte, clsCode byte) Object {
 	return obj
 }
 
-func newStandardProperty(code PropertyCode, name string, dataType string, dataSize int, getRule string, setRule string, annoRule string) Property {
+func newStandardProperty(code PropertyCode, name string, dataType string, dataSize int, getRule string, setRule string, annoRule string, enums ...[]byte) Property {
 	strAttrToPropertyAttr := func(strAttr string) PropertyAttribute {
 		switch strAttr {
 		case "required":
@@ -41,6 +41,9 @@ func newStandardProperty(code PropertyCode, name string, dataType string, dataSi
 	prop := NewProperty(
 		WithPropertyCode(code),
 		WithPropertyName(name),
+		WithPropertyDataType(dataType),
+		WithPropertyCapacity(dataSize),
+		WithPropertyEnums(enums...),
 		WithPropertyReadAttribute(strAttrToPropertyAttr(getRule)),
 		WithPropertyWriteAttribute(strAttrToPropertyAttr(setRule)),
 		WithPropertyAnnoAttribute(strAttrToPropertyAttr(annoRule)),
@@ -106,6 +109,7 @@ foreach my $device_json_file(@device_json_files){
     my $data_type = %{$data}{'type'};
     my $data_size = %{$data}{'size'};
     my $data_ref = %{$data}{'$ref'};
+    my $enums = %{$data}{'enum'};
     if (0< length($data_ref)) {
       my @data_refs = split(/\//, $data_ref);
       my $data_ref_len = @data_refs;
@@ -113,19 +117,18 @@ foreach my $device_json_file(@device_json_files){
       my $prop_def = %{$def_json_root}{$data_ref_id};
       $data_type = %{$prop_def}{'type'};  
       $data_size = %{$prop_def}{'size'};
-      my $enums = %{$prop_def}{'enum'};
-      if (0 < @data_refs) {
-        foreach $enum(@{$enums}) {
-          my $edt = %{$enum}{'edt'};
-          my $name = %{$enum}{'name'};
-          my $descs = %{$enum}{'descriptions'};
-          my $desc = %{$descs}{'en'};
-          if (0< length($edt)) {
-          }
-        }
+      $enums = %{$prop_def}{'enum'};
+    }
+    my $enum_args = "";
+    foreach $enum(@{$enums}) {
+      my $edt = %{$enum}{'edt'};
+      if (0< length($edt)) {
+        $edt =~ s/^0x//i;
+        my @edt_bytes = map { "0x" . uc($_) } ($edt =~ /(..)/g);
+        $enum_args .= sprintf(", []byte{%s}", join(", ", @edt_bytes));
       }
     }
-    printf("obj.AddProperty(newStandardProperty(%s, \"%s\", \"%s\", %d, \"%s\", \"%s\", \"%s\"))\n",
+    printf("obj.AddProperty(newStandardProperty(%s, \"%s\", \"%s\", %d, \"%s\", \"%s\", \"%s\"%s))\n",
       $epc,
       $name,
       $data_type,
@@ -133,6 +136,7 @@ foreach my $device_json_file(@device_json_files){
       $get_rule,
       $set_rule,
       $anno_rule,
+      $enum_args,
       );
    }
   printf("db.addObject(obj)\n\n", $grp_code, $cls_code);
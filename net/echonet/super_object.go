@@ -15,11 +15,12 @@ const (
 )
 
 const (
-	ObjectOperatingStatus  = 0x80
-	ObjectManufacturerCode = 0x8A
-	ObjectAnnoPropertyMap  = 0x9D
-	ObjectSetPropertyMap   = 0x9E
-	ObjectGetPropertyMap   = 0x9F
+	ObjectOperatingStatus      = 0x80
+	ObjectIdentificationNumber = 0x83
+	ObjectManufacturerCode     = 0x8A
+	ObjectAnnoPropertyMap      = 0x9D
+	ObjectSetPropertyMap       = 0x9E
+	ObjectGetPropertyMap       = 0x9F
 )
 
 const (
@@ -50,6 +51,8 @@ type SuperObject interface {
 	SetManufacturerCode(code uint) error
 	// ManufacturerCode return the manufacture codes of the object.
 	ManufacturerCode() (uint, error)
+	// SetIdentificationNumber sets a identification number with the specified manufacture code and node ID to the object.
+	SetIdentificationNumber(code uint, nodeID []byte) error
 }
 
 type superObject struct {
@@ -85,6 +88,7 @@ func (obj *superObject) addStandardProperties() {
 }
 
 // addStandardPropertiesWithCode sets mandatory properties with the specified the object code.
+// The required properties are initialized with the default data of the standard.
 func (obj *superObject) addStandardPropertiesWithCode(objCode ObjectCode) {
 	stdObj, ok := SharedStandardDatabase().LookupObject(objCode)
 	if !ok {
@@ -92,7 +96,11 @@ func (obj *superObject) addStandardPropertiesWithCode(objCode ObjectCode) {
 	}
 	obj.SetClassName(stdObj.ClassName())
 	for _, stdProp := range stdObj.Properties() {
-		obj.AddProperty(stdProp.Copy())
+		prop := stdProp.Copy()
+		if data := newStandardPropertyDefaultData(obj.Code(), prop); data != nil {
+			prop.SetData(data)
+		}
+		obj.AddProperty(prop)
 	}
 }
 
@@ -199,3 +207,12 @@ func (obj *superObject) SetManufacturerCode(code uint) error {
 func (obj *superObject) ManufacturerCode() (uint, error) {
 	return obj.LookupPropertyInteger(ObjectManufacturerCode)
 }
+
+// SetIdentificationNumber sets a identification number with the specified manufacture code and node ID to the object.
+func (obj *superObject) SetIdentificationNumber(code uint, nodeID []byte) error {
+	idBytes, err := newIdentificationNumber(code, nodeID, obj.Code())
+	if err != nil {
+		return err
+	}
+	return obj.SetPropertyData(ObjectIdentificationNumber, idBytes)
+}
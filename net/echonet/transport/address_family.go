@@ -0,0 +1,69 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"strings"
+)
+
+// AddressFamily represents the IP address families which the servers are bound to.
+type AddressFamily int
+
+const (
+	// AddressFamilyIPv4 binds the servers to the IPv4 addresses.
+	AddressFamilyIPv4 AddressFamily = 1 << iota
+	// AddressFamilyIPv6 binds the servers to the IPv6 link-local and global addresses.
+	AddressFamilyIPv6
+	// AddressFamilyDualStack binds the servers to both the IPv4 and IPv6 addresses.
+	AddressFamilyDualStack = AddressFamilyIPv4 | AddressFamilyIPv6
+)
+
+// AddressFamilyOf returns the address family of the specified address.
+func AddressFamilyOf(addr string) AddressFamily {
+	if IsIPv6Address(addr) {
+		return AddressFamilyIPv6
+	}
+	return AddressFamilyIPv4
+}
+
+// Contains returns true whether the specified address belongs to the address family, otherwise false.
+func (family AddressFamily) Contains(addr string) bool {
+	if len(addr) == 0 {
+		return false
+	}
+	return (family & AddressFamilyOf(addr)) != 0
+}
+
+// FilterAddresses returns the addresses which belong to the address family.
+func (family AddressFamily) FilterAddresses(addrs []string) []string {
+	familyAddrs := []string{}
+	for _, addr := range addrs {
+		if family.Contains(addr) {
+			familyAddrs = append(familyAddrs, addr)
+		}
+	}
+	return familyAddrs
+}
+
+// String returns the string representation of the address family.
+func (family AddressFamily) String() string {
+	names := []string{}
+	if (family & AddressFamilyIPv4) != 0 {
+		names = append(names, "ipv4")
+	}
+	if (family & AddressFamilyIPv6) != 0 {
+		names = append(names, "ipv6")
+	}
+	return strings.Join(names, "+")
+}
+
+// SplitAddressZone splits the specified address into the IP address and the IPv6 scoped addressing zone.
+func SplitAddressZone(addr string) (string, string) {
+	host, zone, ok := strings.Cut(addr, "%")
+	if !ok {
+		return addr, ""
+	}
+	return host, zone
+}
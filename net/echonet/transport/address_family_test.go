@@ -0,0 +1,89 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddressFamily(t *testing.T) {
+	addrs := []string{
+		"192.168.0.1",
+		"2001:db8::1",
+		"fe80::1%eth0",
+	}
+
+	tests := []struct {
+		family   AddressFamily
+		name     string
+		expected []string
+	}{
+		{AddressFamilyIPv4, "ipv4", []string{"192.168.0.1"}},
+		{AddressFamilyIPv6, "ipv6", []string{"2001:db8::1", "fe80::1%eth0"}},
+		{AddressFamilyDualStack, "ipv4+ipv6", addrs},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.family.String() != test.name {
+				t.Errorf("%s != %s", test.family.String(), test.name)
+			}
+			filtered := test.family.FilterAddresses(addrs)
+			if !slices.Equal(filtered, test.expected) {
+				t.Errorf("%v != %v", filtered, test.expected)
+			}
+			if test.family.Contains("") {
+				t.Errorf("empty address is contained")
+			}
+		})
+	}
+}
+
+func TestSplitAddressZone(t *testing.T) {
+	tests := []struct {
+		addr string
+		host string
+		zone string
+	}{
+		{"192.168.0.1", "192.168.0.1", ""},
+		{"2001:db8::1", "2001:db8::1", ""},
+		{"fe80::1%eth0", "fe80::1", "eth0"},
+	}
+
+	for _, test := range tests {
+		host, zone := SplitAddressZone(test.addr)
+		if host != test.host || zone != test.zone {
+			t.Errorf("%s : (%s, %s) != (%s, %s)", test.addr, host, zone, test.host, test.zone)
+		}
+	}
+}
+
+func TestMulticastInterfaceAddresses(t *testing.T) {
+	tests := []struct {
+		addrs    []string
+		expected []string
+	}{
+		{
+			[]string{"192.168.0.1", "192.168.1.1"},
+			[]string{"192.168.0.1", "192.168.1.1"},
+		},
+		{
+			[]string{"2001:db8::1", "fe80::1%eth0", "192.168.0.1"},
+			[]string{"192.168.0.1", "fe80::1%eth0"},
+		},
+		{
+			[]string{"2001:db8::1", "2001:db8::2"},
+			[]string{"2001:db8::1"},
+		},
+	}
+
+	for _, test := range tests {
+		maddrs := multicastInterfaceAddresses(test.addrs)
+		if !slices.Equal(maddrs, test.expected) {
+			t.Errorf("%v != %v", maddrs, test.expected)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"bufio"
+	"io"
+	"sync"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+const (
+	// maxPooledBufferSize is the maximum capacity of the message buffers which are returned to the pool.
+	maxPooledBufferSize = (MaxPacketSize * 4)
+)
+
+// messageBuffer represents an encoded message buffer for the outgoing messages.
+type messageBuffer struct {
+	bytes []byte
+}
+
+var messageBufferPool = sync.Pool{
+	New: func() any {
+		return &messageBuffer{
+			bytes: make([]byte, 0, MaxPacketSize),
+		}
+	},
+}
+
+// newMessageBuffer returns a pooled buffer which has the encoded bytes of the specified message.
+func newMessageBuffer(msg *protocol.Message) *messageBuffer {
+	buf, _ := messageBufferPool.Get().(*messageBuffer)
+	buf.bytes = msg.AppendBytes(buf.bytes[:0])
+	return buf
+}
+
+// Bytes returns the encoded message bytes.
+func (buf *messageBuffer) Bytes() []byte {
+	return buf.bytes
+}
+
+// Release returns the buffer to the pool. The buffer must not be used after Release.
+func (buf *messageBuffer) Release() {
+	if maxPooledBufferSize < cap(buf.bytes) {
+		return
+	}
+	messageBufferPool.Put(buf)
+}
+
+var bufferedReaderPool = sync.Pool{
+	New: func() any {
+		return bufio.NewReaderSize(nil, MaxPacketSize)
+	},
+}
+
+// newBufferedReader returns a pooled buffered reader for the specified reader.
+func newBufferedReader(r io.Reader) *bufio.Reader {
+	reader, _ := bufferedReaderPool.Get().(*bufio.Reader)
+	reader.Reset(r)
+	return reader
+}
+
+// releaseBufferedReader returns the specified buffered reader to the pool.
+func releaseBufferedReader(reader *bufio.Reader) {
+	reader.Reset(nil)
+	bufferedReaderPool.Put(reader)
+}
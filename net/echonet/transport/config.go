@@ -7,6 +7,7 @@ package transport
 // Config represents a cofiguration for transport.
 type Config struct {
 	*UnicastConfig
+	*MulticastConfig
 	*ExtensionConfig
 }
 
@@ -14,6 +15,7 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	conf := &Config{
 		UnicastConfig:   NewDefaultUnicastConfig(),
+		MulticastConfig: NewDefaultMulticastConfig(),
 		ExtensionConfig: NewDefaultExtensionConfig(),
 	}
 	return conf
@@ -22,6 +24,7 @@ func NewDefaultConfig() *Config {
 // SetConfig sets all configuration flags.
 func (conf *Config) SetConfig(newConfig *Config) {
 	conf.UnicastConfig.SetConfig(newConfig.UnicastConfig)
+	conf.MulticastConfig.SetConfig(newConfig.MulticastConfig)
 	conf.ExtensionConfig.SetConfig(newConfig.ExtensionConfig)
 }
 
@@ -30,6 +33,9 @@ func (conf *Config) Equals(other *Config) bool {
 	if !conf.UnicastConfig.Equals(other.UnicastConfig) {
 		return false
 	}
+	if !conf.MulticastConfig.Equals(other.MulticastConfig) {
+		return false
+	}
 	if !conf.ExtensionConfig.Equals(other.ExtensionConfig) {
 		return false
 	}
@@ -28,8 +28,12 @@ const (
 )
 
 const (
-	DefaultConnectimeTimeOut = (time.Millisecond * 5000)
-	DefaultRequestTimeout    = (time.Millisecond * 5000)
-	DefaultBindRetryCount    = 5
-	DefaultBindRetryWaitTime = (time.Millisecond * 500)
+	DefaultConnectimeTimeOut      = (time.Millisecond * 5000)
+	DefaultRequestTimeout         = (time.Millisecond * 5000)
+	DefaultBindRetryCount         = 5
+	DefaultBindRetryWaitTime      = (time.Millisecond * 500)
+	DefaultTCPIdleTimeout         = (time.Second * 30)
+	DefaultTCPKeepAlive           = (time.Second * 15)
+	DefaultInterfaceWatchInterval = (time.Second * 5)
+	DefaultMulticastTTL           = 1
 )
@@ -18,4 +18,13 @@ var (
 	errAvailableAddressNotFound = fmt.Errorf("%w: no available address", ErrInvalid)
 	errAvailableInterfaceFound  = fmt.Errorf("%w: no available interface", ErrInvalid)
 	errUnicastServerNotRunning  = fmt.Errorf("%w: unicast server is not running", ErrInvalid)
+	errInvalidInterfaceRule     = fmt.Errorf("%w: interface rule", ErrInvalid)
+	errAddressFamilyNotBound    = fmt.Errorf("%w: no server is bound to the address family", ErrInvalid)
+	errAddressNotBound          = fmt.Errorf("%w: no server is bound to the address", ErrInvalid)
+	errVirtualAddressInUse      = fmt.Errorf("%w: virtual address is already in use", ErrInvalid)
+	errVirtualHostUnreachable   = fmt.Errorf("%w: virtual host is unreachable", ErrInvalid)
+	errResponseMessageNotFound  = fmt.Errorf("%w: no response message", ErrInvalid)
+	errInvalidMulticastAddress  = fmt.Errorf("%w: multicast address", ErrInvalid)
+	errInvalidMulticastPort     = fmt.Errorf("%w: multicast port", ErrInvalid)
+	errInvalidMulticastTTL      = fmt.Errorf("%w: multicast TTL", ErrInvalid)
 )
@@ -6,17 +6,28 @@ package transport
 
 import (
 	"reflect"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 // ExtensionConfig represents a cofiguration for extended specifications.
 type ExtensionConfig struct {
 	autoPortBindingEnabled bool
+	parseMode              protocol.ParseMode
+	addressFamily          AddressFamily
+	interfaceFilter        *InterfaceFilter
+	interfaceWatchInterval time.Duration
 }
 
 // NewDefaultExtensionConfig returns a default configuration.
 func NewDefaultExtensionConfig() *ExtensionConfig {
 	conf := &ExtensionConfig{
 		autoPortBindingEnabled: false,
+		parseMode:              protocol.ParseModeStrict,
+		addressFamily:          AddressFamilyIPv4,
+		interfaceFilter:        NewInterfaceFilter(),
+		interfaceWatchInterval: DefaultInterfaceWatchInterval,
 	}
 	return conf
 }
@@ -24,6 +35,10 @@ func NewDefaultExtensionConfig() *ExtensionConfig {
 // SetConfig sets all flags.
 func (conf *ExtensionConfig) SetConfig(newConfig *ExtensionConfig) {
 	conf.autoPortBindingEnabled = newConfig.autoPortBindingEnabled
+	conf.parseMode = newConfig.parseMode
+	conf.addressFamily = newConfig.addressFamily
+	conf.interfaceFilter.SetFilter(newConfig.interfaceFilter)
+	conf.interfaceWatchInterval = newConfig.interfaceWatchInterval
 }
 
 // SetAutoPortBindingEnabled sets a flag for TCP functions.
@@ -36,6 +51,63 @@ func (conf *ExtensionConfig) AutoPortBindingEnabled() bool {
 	return conf.autoPortBindingEnabled
 }
 
+// SetParseMode sets the specified mode to parse the received messages.
+func (conf *ExtensionConfig) SetParseMode(mode protocol.ParseMode) {
+	conf.parseMode = mode
+}
+
+// ParseMode returns the mode to parse the received messages. The mode is ParseModeStrict by default.
+func (conf *ExtensionConfig) ParseMode() protocol.ParseMode {
+	return conf.parseMode
+}
+
+// SetAddressFamily sets the specified address families to bind the servers.
+func (conf *ExtensionConfig) SetAddressFamily(family AddressFamily) {
+	conf.addressFamily = family
+}
+
+// AddressFamily returns the address families to bind the servers. The family is AddressFamilyIPv4 by default.
+func (conf *ExtensionConfig) AddressFamily() AddressFamily {
+	return conf.addressFamily
+}
+
+// SetInterfaceIncludes sets the specified rules to include the interfaces and addresses to bind the servers.
+// Each rule is an interface name, a glob pattern of the interface names, a CIDR or an explicit IP address.
+func (conf *ExtensionConfig) SetInterfaceIncludes(rules ...string) {
+	conf.interfaceFilter.SetIncludes(rules...)
+}
+
+// InterfaceIncludes returns the rules to include the interfaces and addresses to bind the servers.
+func (conf *ExtensionConfig) InterfaceIncludes() []string {
+	return conf.interfaceFilter.Includes()
+}
+
+// SetInterfaceExcludes sets the specified rules to exclude the interfaces and addresses to bind the servers.
+// Each rule is an interface name, a glob pattern of the interface names, a CIDR or an explicit IP address.
+func (conf *ExtensionConfig) SetInterfaceExcludes(rules ...string) {
+	conf.interfaceFilter.SetExcludes(rules...)
+}
+
+// InterfaceExcludes returns the rules to exclude the interfaces and addresses to bind the servers.
+func (conf *ExtensionConfig) InterfaceExcludes() []string {
+	return conf.interfaceFilter.Excludes()
+}
+
+// InterfaceFilter returns the filter to select the interfaces and addresses to bind the servers.
+func (conf *ExtensionConfig) InterfaceFilter() *InterfaceFilter {
+	return conf.interfaceFilter
+}
+
+// SetInterfaceWatchInterval sets the specified interval to poll the interface changes. The interfaces are not watched when the interval is zero.
+func (conf *ExtensionConfig) SetInterfaceWatchInterval(d time.Duration) {
+	conf.interfaceWatchInterval = d
+}
+
+// InterfaceWatchInterval returns the interval to poll the interface changes.
+func (conf *ExtensionConfig) InterfaceWatchInterval() time.Duration {
+	return conf.interfaceWatchInterval
+}
+
 // Equals returns true whether the specified other class is same, otherwise false.
 func (conf *ExtensionConfig) Equals(otherConf *ExtensionConfig) bool {
 	return reflect.DeepEqual(conf, otherConf)
@@ -6,6 +6,8 @@ package transport
 
 import (
 	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 func TestNewDefaultExtensionConfigConfig(t *testing.T) {
@@ -43,6 +45,38 @@ func TestExtensionConfigEquals(t *testing.T) {
 	if !conf02.Equals(conf03) {
 		t.Errorf("%v != %v", conf01, conf03)
 	}
+
+	conf03.SetParseMode(protocol.ParseModeLenient)
+	if conf02.Equals(conf03) {
+		t.Errorf("%v == %v", conf02, conf03)
+	}
+	conf02.SetConfig(conf03)
+	if conf02.ParseMode() != protocol.ParseModeLenient {
+		t.Errorf("%s != %s", conf02.ParseMode(), protocol.ParseModeLenient)
+	}
+
+	conf03.SetAddressFamily(AddressFamilyDualStack)
+	if conf02.Equals(conf03) {
+		t.Errorf("%v == %v", conf02, conf03)
+	}
+	conf02.SetConfig(conf03)
+	if conf02.AddressFamily() != AddressFamilyDualStack {
+		t.Errorf("%s != %s", conf02.AddressFamily(), AddressFamilyDualStack)
+	}
+
+	conf03.SetInterfaceIncludes("eth*")
+	conf03.SetInterfaceExcludes("192.168.0.0/24")
+	if conf02.Equals(conf03) {
+		t.Errorf("%v == %v", conf02, conf03)
+	}
+	conf02.SetConfig(conf03)
+	if !conf02.Equals(conf03) {
+		t.Errorf("%v != %v", conf02, conf03)
+	}
+	conf03.SetInterfaceIncludes()
+	if conf02.Equals(conf03) {
+		t.Errorf("%v == %v", conf02, conf03)
+	}
 }
 
 func TestExtensionAutoBindingConfig(t *testing.T) {
@@ -5,6 +5,7 @@
 package transport
 
 import (
+	"fmt"
 	"net"
 	"slices"
 	"strings"
@@ -31,20 +32,20 @@ func IsIPv4Address(addr string) bool {
 
 // IsIPv6Interface returns true whether the specified address is a IPv6 address.
 func IsIPv6Interface(ifi *net.Interface) bool {
-	addrs, err := GetInterfaceAddresses(ifi)
-	if err != nil {
-		return false
-	}
-	return slices.ContainsFunc(addrs, IsIPv6Address)
+	_, err := GetInterfaceAddressesWithFamily(ifi, AddressFamilyIPv6)
+	return err == nil
 }
 
 // IsIPv4Interface returns true whether the specified address is a IPv4 address.
 func IsIPv4Interface(ifi *net.Interface) bool {
-	addrs, err := GetInterfaceAddresses(ifi)
-	if err != nil {
-		return false
-	}
-	return slices.ContainsFunc(addrs, IsIPv4Address)
+	_, err := GetInterfaceAddressesWithFamily(ifi, AddressFamilyIPv4)
+	return err == nil
+}
+
+// isLinkLocalAddress returns true whether the specified address is a IPv6 link-local address with the scoped addressing zone.
+func isLinkLocalAddress(addr string) bool {
+	_, zone := SplitAddressZone(addr)
+	return 0 < len(zone)
 }
 
 // IsLoopbackAddress returns true whether the specified address is a loopback addresses.
@@ -97,32 +98,80 @@ func IsVirtualInterface(ifi *net.Interface) bool {
 	return false
 }
 
-// GetInterfaceAddresses returns a IPv4 or IPv6 address of the specivied interface.
+// GetInterfaceAddresses returns the IPv4 addresses of the specivied interface.
 func GetInterfaceAddresses(ifi *net.Interface) ([]string, error) {
+	return GetInterfaceAddressesWithFamily(ifi, AddressFamilyIPv4)
+}
+
+// GetInterfaceAddressesWithFamily returns the addresses of the specified address family of the specivied interface.
+// The IPv6 link-local addresses have the scoped addressing zone of the interface name such as "fe80::1%eth0".
+func GetInterfaceAddressesWithFamily(ifi *net.Interface, family AddressFamily) ([]string, error) {
 	addrs, err := ifi.Addrs()
 	if err != nil {
 		return nil, err
 	}
 	ipaddrs := []string{}
 	for _, addr := range addrs {
-		addrStr := addr.String()
-		saddr := strings.Split(addrStr, "/")
-		if len(saddr) < 2 {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipnet.IP
+		if ip.IsLoopback() || ip.IsMulticast() || ip.IsUnspecified() {
 			continue
 		}
-		if IsIPv6Address(saddr[0]) {
+		if ip.To4() != nil {
+			ipaddrs = append(ipaddrs, ip.String())
 			continue
 		}
-		ipaddrs = append(ipaddrs, saddr[0])
+		if ip.IsLinkLocalUnicast() {
+			ipaddrs = append(ipaddrs, ip.String()+"%"+ifi.Name)
+			continue
+		}
+		ipaddrs = append(ipaddrs, ip.String())
 	}
+	ipaddrs = family.FilterAddresses(ipaddrs)
 	if len(ipaddrs) == 0 {
 		return nil, errAvailableAddressNotFound
 	}
 	return ipaddrs, nil
 }
 
-// GetAvailableInterfaces returns all available interfaces in the node.
+// GetInterfaceSubnet returns the subnet of the specified address of the interface.
+func GetInterfaceSubnet(ifi *net.Interface, ifaddr string) (*net.IPNet, error) {
+	host, _ := SplitAddressZone(ifaddr)
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("%w (%s)", errAvailableAddressNotFound, ifaddr)
+	}
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipnet.IP.Equal(ip) {
+			return &net.IPNet{IP: ipnet.IP.Mask(ipnet.Mask), Mask: ipnet.Mask}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w (%s %s)", errAvailableAddressNotFound, ifi.Name, ifaddr)
+}
+
+// GetAvailableInterfaces returns all available interfaces which have the IPv4 addresses in the node.
 func GetAvailableInterfaces() ([]*net.Interface, error) {
+	return GetAvailableInterfacesWithFamily(AddressFamilyIPv4)
+}
+
+// GetAvailableInterfacesWithFamily returns all available interfaces which have the addresses of the specified address family in the node.
+func GetAvailableInterfacesWithFamily(family AddressFamily) ([]*net.Interface, error) {
+	return getAvailableInterfaces(family, false)
+}
+
+// getAvailableInterfaces returns all available interfaces of the specified address family in the node including the virtual and bridge interfaces when the flag is true.
+func getAvailableInterfaces(family AddressFamily, virtualEnabled bool) ([]*net.Interface, error) {
 	useIfs := make([]*net.Interface, 0)
 	localIfs, err := net.Interfaces()
 	if err != nil {
@@ -140,13 +189,13 @@ func GetAvailableInterfaces() ([]*net.Interface, error) {
 		if (localIf.Flags & net.FlagMulticast) == 0 {
 			continue
 		}
-		if IsBridgeInterface(&localIf) {
+		if !virtualEnabled && IsBridgeInterface(&localIf) {
 			continue
 		}
-		if IsVirtualInterface(&localIf) {
+		if !virtualEnabled && IsVirtualInterface(&localIf) {
 			continue
 		}
-		_, addrErr := GetInterfaceAddresses(&localIf)
+		_, addrErr := GetInterfaceAddressesWithFamily(&localIf, family)
 		if addrErr != nil {
 			continue
 		}
@@ -162,15 +211,20 @@ func GetAvailableInterfaces() ([]*net.Interface, error) {
 	return useIfs, err
 }
 
-// GetAvailableAddresses returns all available IP addresses in the node.
+// GetAvailableAddresses returns all available IPv4 addresses in the node.
 func GetAvailableAddresses() ([]string, error) {
+	return GetAvailableAddressesWithFamily(AddressFamilyIPv4)
+}
+
+// GetAvailableAddressesWithFamily returns all available addresses of the specified address family in the node.
+func GetAvailableAddressesWithFamily(family AddressFamily) ([]string, error) {
 	addrs := make([]string, 0)
-	ifis, err := GetAvailableInterfaces()
+	ifis, err := GetAvailableInterfacesWithFamily(family)
 	if err != nil {
 		return addrs, err
 	}
 	for _, ifi := range ifis {
-		ipaddrs, err := GetInterfaceAddresses(ifi)
+		ipaddrs, err := GetInterfaceAddressesWithFamily(ifi, family)
 		if err != nil {
 			continue
 		}
@@ -178,3 +232,54 @@ func GetAvailableAddresses() ([]string, error) {
 	}
 	return addrs, nil
 }
+
+// GetSelectedInterfaces returns the available interfaces which are selected by the address family and the interface filter of the specified configuration.
+func GetSelectedInterfaces(conf *Config) ([]*net.Interface, error) {
+	selectedIfs, err := getSelectedInterfaceAddresses(conf.AddressFamily(), conf.InterfaceFilter())
+	if err != nil {
+		return nil, err
+	}
+	ifis := make([]*net.Interface, len(selectedIfs))
+	for n, selectedIf := range selectedIfs {
+		ifis[n] = selectedIf.ifi
+	}
+	return ifis, nil
+}
+
+// interfaceAddresses represents the selected addresses of an interface.
+type interfaceAddresses struct {
+	ifi   *net.Interface
+	addrs []string
+}
+
+// getSelectedInterfaceAddresses returns the available interfaces and addresses which are selected by the specified address family and filter.
+// The virtual and bridge interfaces are also available when the filter has include rules, because the include rules select the interfaces explicitly.
+func getSelectedInterfaceAddresses(family AddressFamily, filter *InterfaceFilter) ([]*interfaceAddresses, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	ifis, err := getAvailableInterfaces(family, filter.HasIncludes())
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIfs := []*interfaceAddresses{}
+	for _, ifi := range ifis {
+		ifaddrs, err := GetInterfaceAddressesWithFamily(ifi, family)
+		if err != nil {
+			continue
+		}
+		ifaddrs = filter.FilterAddresses(ifi, ifaddrs)
+		if len(ifaddrs) == 0 {
+			continue
+		}
+		selectedIfs = append(selectedIfs, &interfaceAddresses{ifi: ifi, addrs: ifaddrs})
+	}
+
+	if len(selectedIfs) == 0 {
+		return nil, fmt.Errorf("%w (%s)", errAvailableAddressNotFound, family)
+	}
+
+	return selectedIfs, nil
+}
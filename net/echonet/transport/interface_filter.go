@@ -0,0 +1,141 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"slices"
+	"strings"
+)
+
+// InterfaceFilter represents the rules to select the interfaces and addresses which the servers are bound to.
+// Each rule is an interface name, a glob pattern of the interface names such as "eth*", a CIDR such as "192.168.0.0/24", or an explicit IP address.
+// The address is selected when it matches any include rule, or the include rules are empty, and it matches no exclude rule.
+type InterfaceFilter struct {
+	includes []string
+	excludes []string
+}
+
+// NewInterfaceFilter returns a new empty filter which selects all available interfaces.
+func NewInterfaceFilter() *InterfaceFilter {
+	filter := &InterfaceFilter{
+		includes: []string{},
+		excludes: []string{},
+	}
+	return filter
+}
+
+// SetFilter sets all rules of the specified filter.
+func (filter *InterfaceFilter) SetFilter(newFilter *InterfaceFilter) {
+	filter.includes = append([]string{}, newFilter.includes...)
+	filter.excludes = append([]string{}, newFilter.excludes...)
+}
+
+// SetIncludes sets the specified rules to include the interfaces and addresses.
+func (filter *InterfaceFilter) SetIncludes(rules ...string) {
+	filter.includes = append([]string{}, rules...)
+}
+
+// Includes returns the rules to include the interfaces and addresses.
+func (filter *InterfaceFilter) Includes() []string {
+	return filter.includes
+}
+
+// SetExcludes sets the specified rules to exclude the interfaces and addresses.
+func (filter *InterfaceFilter) SetExcludes(rules ...string) {
+	filter.excludes = append([]string{}, rules...)
+}
+
+// Excludes returns the rules to exclude the interfaces and addresses.
+func (filter *InterfaceFilter) Excludes() []string {
+	return filter.excludes
+}
+
+// HasIncludes returns true whether the filter has any include rule, otherwise false.
+func (filter *InterfaceFilter) HasIncludes() bool {
+	return 0 < len(filter.includes)
+}
+
+// Validate returns an error when the filter has a malformed rule.
+func (filter *InterfaceFilter) Validate() error {
+	for _, rule := range slices.Concat(filter.includes, filter.excludes) {
+		if len(rule) == 0 {
+			return fmt.Errorf("%w (%q)", errInvalidInterfaceRule, rule)
+		}
+		if strings.Contains(rule, "/") {
+			if _, _, err := net.ParseCIDR(rule); err != nil {
+				return fmt.Errorf("%w (%s): %w", errInvalidInterfaceRule, rule, err)
+			}
+			continue
+		}
+		if _, err := path.Match(rule, ""); err != nil {
+			return fmt.Errorf("%w (%s): %w", errInvalidInterfaceRule, rule, err)
+		}
+	}
+	return nil
+}
+
+// Matches returns true whether the specified address of the interface is selected by the filter, otherwise false.
+func (filter *InterfaceFilter) Matches(ifi *net.Interface, addr string) bool {
+	if filter.HasIncludes() {
+		if !slices.ContainsFunc(filter.includes, func(rule string) bool { return matchInterfaceRule(rule, ifi, addr) }) {
+			return false
+		}
+	}
+	return !slices.ContainsFunc(filter.excludes, func(rule string) bool { return matchInterfaceRule(rule, ifi, addr) })
+}
+
+// FilterAddresses returns the addresses of the specified interface which are selected by the filter.
+func (filter *InterfaceFilter) FilterAddresses(ifi *net.Interface, addrs []string) []string {
+	selectedAddrs := []string{}
+	for _, addr := range addrs {
+		if filter.Matches(ifi, addr) {
+			selectedAddrs = append(selectedAddrs, addr)
+		}
+	}
+	return selectedAddrs
+}
+
+// matchInterfaceRule returns true whether the specified rule matches the interface or the address, otherwise false.
+func matchInterfaceRule(rule string, ifi *net.Interface, addr string) bool {
+	host, _ := SplitAddressZone(addr)
+	ip := net.ParseIP(host)
+
+	// CIDR
+
+	if strings.Contains(rule, "/") {
+		_, ipnet, err := net.ParseCIDR(rule)
+		if err != nil || ip == nil {
+			return false
+		}
+		return ipnet.Contains(ip)
+	}
+
+	// Explicit address
+
+	ruleHost, ruleZone := SplitAddressZone(rule)
+	if ruleIP := net.ParseIP(ruleHost); ruleIP != nil {
+		if ip == nil || !ruleIP.Equal(ip) {
+			return false
+		}
+		if 0 < len(ruleZone) && (ifi == nil || ifi.Name != ruleZone) {
+			return false
+		}
+		return true
+	}
+
+	// Interface name or glob pattern
+
+	if ifi == nil {
+		return false
+	}
+	ok, err := path.Match(rule, ifi.Name)
+	if err != nil {
+		return false
+	}
+	return ok
+}
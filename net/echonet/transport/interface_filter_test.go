@@ -0,0 +1,170 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestInterfaceFilter(t *testing.T) {
+	eth0 := &net.Interface{Name: "eth0"}   // nolint: exhaustruct
+	wg0 := &net.Interface{Name: "wg0"}     // nolint: exhaustruct
+	veth1 := &net.Interface{Name: "veth1"} // nolint: exhaustruct
+
+	tests := []struct {
+		name     string
+		includes []string
+		excludes []string
+		ifi      *net.Interface
+		addr     string
+		expected bool
+	}{
+		{"empty", nil, nil, eth0, "192.168.0.1", true},
+		{"name", []string{"eth0"}, nil, eth0, "192.168.0.1", true},
+		{"name unmatched", []string{"eth0"}, nil, wg0, "10.0.0.1", false},
+		{"glob", []string{"eth*"}, nil, eth0, "192.168.0.1", true},
+		{"glob unmatched", []string{"eth*"}, nil, veth1, "172.17.0.1", false},
+		{"cidr", []string{"192.168.0.0/24"}, nil, eth0, "192.168.0.1", true},
+		{"cidr unmatched", []string{"192.168.0.0/24"}, nil, eth0, "192.168.1.1", false},
+		{"cidr ipv6", []string{"fe80::/10"}, nil, eth0, "fe80::1%eth0", true},
+		{"address", []string{"192.168.0.1"}, nil, eth0, "192.168.0.1", true},
+		{"address unmatched", []string{"192.168.0.1"}, nil, eth0, "192.168.0.2", false},
+		{"address zone", []string{"fe80::1%eth0"}, nil, eth0, "fe80::1%eth0", true},
+		{"address zone unmatched", []string{"fe80::1%wg0"}, nil, eth0, "fe80::1%eth0", false},
+		{"exclude name", nil, []string{"wg*"}, wg0, "10.0.0.1", false},
+		{"exclude other name", nil, []string{"wg*"}, eth0, "192.168.0.1", true},
+		{"exclude cidr", []string{"eth0"}, []string{"192.168.0.128/25"}, eth0, "192.168.0.200", false},
+		{"include and exclude", []string{"eth0"}, []string{"192.168.0.128/25"}, eth0, "192.168.0.1", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := NewInterfaceFilter()
+			filter.SetIncludes(test.includes...)
+			filter.SetExcludes(test.excludes...)
+			if err := filter.Validate(); err != nil {
+				t.Error(err)
+				return
+			}
+			if filter.Matches(test.ifi, test.addr) != test.expected {
+				t.Errorf("%s:%s %v != %v", test.ifi.Name, test.addr, !test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestInterfaceFilterValidate(t *testing.T) {
+	badRules := []string{
+		"",
+		"192.168.0.0/33",
+		"eth[",
+	}
+
+	for _, rule := range badRules {
+		filter := NewInterfaceFilter()
+		filter.SetExcludes(rule)
+		if err := filter.Validate(); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%q : %v", rule, err)
+		}
+	}
+}
+
+func TestGetSelectedInterfaceAddresses(t *testing.T) {
+	ifis, err := GetAvailableInterfaces()
+	if err != nil {
+		t.Skip(err)
+	}
+	ifi := ifis[0]
+	ifaddrs, err := GetInterfaceAddresses(ifi)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	t.Run("name", func(t *testing.T) {
+		filter := NewInterfaceFilter()
+		filter.SetIncludes(ifi.Name)
+		selectedIfs, err := getSelectedInterfaceAddresses(AddressFamilyDualStack, filter)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(selectedIfs) != 1 || selectedIfs[0].ifi.Name != ifi.Name {
+			t.Errorf("%v", selectedIfs)
+		}
+	})
+
+	t.Run("address", func(t *testing.T) {
+		filter := NewInterfaceFilter()
+		filter.SetIncludes(ifaddrs[0])
+		selectedIfs, err := getSelectedInterfaceAddresses(AddressFamilyDualStack, filter)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(selectedIfs) != 1 || len(selectedIfs[0].addrs) != 1 || selectedIfs[0].addrs[0] != ifaddrs[0] {
+			t.Errorf("%v", selectedIfs)
+		}
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		filter := NewInterfaceFilter()
+		filter.SetExcludes("*")
+		_, err := getSelectedInterfaceAddresses(AddressFamilyDualStack, filter)
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("%v", err)
+		}
+	})
+}
+
+func TestMessageManagerInterfaceFilter(t *testing.T) {
+	addrs, err := GetAvailableAddresses()
+	if err != nil {
+		t.Skip(err)
+	}
+	ipv4Addrs := AddressFamilyIPv4.FilterAddresses(addrs)
+	if len(ipv4Addrs) == 0 {
+		t.Skip("IPv4 address is not available")
+	}
+
+	conf := newTestDefaultConfig()
+	conf.SetInterfaceIncludes(ipv4Addrs[0])
+
+	mgr := NewMessageManager()
+	mgr.SetConfig(conf)
+	err = mgr.Start()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer mgr.Stop()
+
+	boundAddrs := mgr.Addresses()
+	if len(boundAddrs) != 1 || boundAddrs[0] != ipv4Addrs[0] {
+		t.Errorf("%v != [%s]", boundAddrs, ipv4Addrs[0])
+	}
+
+	for _, server := range mgr.MulticastManager().Servers {
+		addr, err := server.Address()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if addr != ipv4Addrs[0] {
+			t.Errorf("%s != %s", addr, ipv4Addrs[0])
+		}
+	}
+
+	// Exclude all interfaces
+
+	mgr.Stop()
+	conf.SetInterfaceExcludes("*")
+	mgr.SetConfig(conf)
+	err = mgr.Start()
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("%v", err)
+	}
+}
@@ -5,6 +5,7 @@
 package transport
 
 import (
+	"net"
 	"testing"
 )
 
@@ -26,6 +27,59 @@ func TestGetAvailableAddresses(t *testing.T) {
 	if len(addrs) == 0 {
 		t.Errorf("available address is not found")
 	}
+	// The addresses are only IPv4 addresses without the address family.
+	for _, addr := range addrs {
+		if !IsIPv4Address(addr) {
+			t.Errorf("%s is not an IPv4 address", addr)
+		}
+	}
+}
+
+func TestGetAvailableAddressesWithFamily(t *testing.T) {
+	for _, family := range []AddressFamily{AddressFamilyIPv4, AddressFamilyIPv6, AddressFamilyDualStack} {
+		addrs, err := GetAvailableAddressesWithFamily(family)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if !family.Contains(addr) {
+				t.Errorf("%s : %s", family, addr)
+			}
+		}
+	}
+}
+
+func TestGetInterfaceAddressesZone(t *testing.T) {
+	ifis, err := GetAvailableInterfacesWithFamily(AddressFamilyDualStack)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, ifi := range ifis {
+		ifaddrs, err := GetInterfaceAddressesWithFamily(ifi, AddressFamilyDualStack)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		for _, ifaddr := range ifaddrs {
+			host, zone := SplitAddressZone(ifaddr)
+			ip := net.ParseIP(host)
+			if ip == nil {
+				t.Errorf("%s is not an IP address", ifaddr)
+				continue
+			}
+			if ip.IsLinkLocalUnicast() && ip.To4() == nil {
+				if zone != ifi.Name {
+					t.Errorf("%s != %s", zone, ifi.Name)
+				}
+				continue
+			}
+			if 0 < len(zone) {
+				t.Errorf("%s has a zone", ifaddr)
+			}
+		}
+	}
 }
 
 func TestIPv6Addresses(t *testing.T) {
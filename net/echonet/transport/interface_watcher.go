@@ -0,0 +1,155 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// InterfaceEventType represents a type of the interface change.
+type InterfaceEventType int
+
+const (
+	// InterfaceAddressAdded represents an address which is newly available on the interface.
+	InterfaceAddressAdded InterfaceEventType = iota
+	// InterfaceAddressRemoved represents an address which is no longer available on the interface.
+	InterfaceAddressRemoved
+)
+
+// String returns the string representation of the event type.
+func (t InterfaceEventType) String() string {
+	switch t {
+	case InterfaceAddressAdded:
+		return "added"
+	case InterfaceAddressRemoved:
+		return "removed"
+	}
+	return ""
+}
+
+// InterfaceEvent represents a change of the selected interfaces and addresses.
+type InterfaceEvent struct {
+	Type      InterfaceEventType
+	Interface string
+	Address   string
+	// Err is the error to bind the servers to the added address, or nil when the servers are rebound.
+	Err error
+}
+
+// InterfaceEventHandler is an interface to receive the interface changes after the servers are rebound.
+type InterfaceEventHandler interface {
+	InterfaceChanged(event *InterfaceEvent)
+}
+
+// interfaceAddressesFunc returns the currently selected interfaces and addresses.
+type interfaceAddressesFunc func() ([]*interfaceAddresses, error)
+
+// interfaceChangedFunc is called with the currently selected interfaces and addresses, and the changes from the last ones.
+type interfaceChangedFunc func(ifis []*interfaceAddresses, events []*InterfaceEvent)
+
+// interfaceWatcher polls the selected interfaces and addresses, and reports the changes.
+type interfaceWatcher struct {
+	interval      time.Duration
+	getInterfaces interfaceAddressesFunc
+	changed       interfaceChangedFunc
+	lastIfis      []*interfaceAddresses
+	done          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// newInterfaceWatcher returns a new watcher which reports the changes from the specified interfaces and addresses.
+func newInterfaceWatcher(interval time.Duration, getInterfaces interfaceAddressesFunc, changed interfaceChangedFunc, ifis []*interfaceAddresses) *interfaceWatcher {
+	watcher := &interfaceWatcher{
+		interval:      interval,
+		getInterfaces: getInterfaces,
+		changed:       changed,
+		lastIfis:      ifis,
+		done:          nil,
+		wg:            sync.WaitGroup{},
+	}
+	return watcher
+}
+
+// Start starts to poll the interfaces.
+func (watcher *interfaceWatcher) Start() {
+	watcher.done = make(chan struct{})
+	watcher.wg.Add(1)
+	go func() {
+		defer watcher.wg.Done()
+		ticker := time.NewTicker(watcher.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-watcher.done:
+				return
+			case <-ticker.C:
+				watcher.Poll()
+			}
+		}
+	}()
+}
+
+// Stop stops to poll the interfaces, and waits for the running poll.
+func (watcher *interfaceWatcher) Stop() {
+	if watcher.done == nil {
+		return
+	}
+	close(watcher.done)
+	watcher.wg.Wait()
+	watcher.done = nil
+}
+
+// Poll gets the current interfaces and addresses, and reports the changes from the last ones.
+func (watcher *interfaceWatcher) Poll() {
+	ifis, err := watcher.getInterfaces()
+	if err != nil {
+		// All selected addresses are unavailable such as while the Wi-Fi is reconnecting.
+		if !errors.Is(err, errAvailableAddressNotFound) {
+			return
+		}
+		ifis = []*interfaceAddresses{}
+	}
+
+	events := compareInterfaceAddresses(watcher.lastIfis, ifis)
+	watcher.lastIfis = ifis
+	if len(events) == 0 {
+		return
+	}
+
+	watcher.changed(ifis, events)
+}
+
+// compareInterfaceAddresses returns the changes from the last interfaces and addresses to the current ones.
+func compareInterfaceAddresses(lastIfis []*interfaceAddresses, ifis []*interfaceAddresses) []*InterfaceEvent {
+	events := []*InterfaceEvent{}
+	for _, ifi := range lastIfis {
+		for _, addr := range ifi.addrs {
+			if !hasInterfaceAddress(ifis, ifi.ifi.Name, addr) {
+				events = append(events, &InterfaceEvent{Type: InterfaceAddressRemoved, Interface: ifi.ifi.Name, Address: addr, Err: nil})
+			}
+		}
+	}
+	for _, ifi := range ifis {
+		for _, addr := range ifi.addrs {
+			if !hasInterfaceAddress(lastIfis, ifi.ifi.Name, addr) {
+				events = append(events, &InterfaceEvent{Type: InterfaceAddressAdded, Interface: ifi.ifi.Name, Address: addr, Err: nil})
+			}
+		}
+	}
+	return events
+}
+
+// hasInterfaceAddress returns true whether the specified address of the interface is included, otherwise false.
+func hasInterfaceAddress(ifis []*interfaceAddresses, name string, addr string) bool {
+	for _, ifi := range ifis {
+		if ifi.ifi.Name == name && slices.Contains(ifi.addrs, addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestInterfaceAddresses(name string, addrs ...string) *interfaceAddresses {
+	return &interfaceAddresses{ifi: &net.Interface{Name: name}, addrs: addrs} // nolint:exhaustruct
+}
+
+func TestCompareInterfaceAddresses(t *testing.T) {
+	lastIfis := []*interfaceAddresses{
+		newTestInterfaceAddresses("eth0", "192.168.0.2"),
+		newTestInterfaceAddresses("wlan0", "192.168.1.2"),
+	}
+	ifis := []*interfaceAddresses{
+		newTestInterfaceAddresses("eth0", "192.168.0.2"),
+		newTestInterfaceAddresses("wlan0", "192.168.1.3"),
+		newTestInterfaceAddresses("usb0", "10.0.0.2"),
+	}
+
+	events := compareInterfaceAddresses(lastIfis, ifis)
+	expectedEvents := []InterfaceEvent{
+		{Type: InterfaceAddressRemoved, Interface: "wlan0", Address: "192.168.1.2", Err: nil},
+		{Type: InterfaceAddressAdded, Interface: "wlan0", Address: "192.168.1.3", Err: nil},
+		{Type: InterfaceAddressAdded, Interface: "usb0", Address: "10.0.0.2", Err: nil},
+	}
+	if len(events) != len(expectedEvents) {
+		t.Fatalf("%d != %d", len(events), len(expectedEvents))
+	}
+	for n, event := range events {
+		if *event != expectedEvents[n] {
+			t.Errorf("%v != %v", *event, expectedEvents[n])
+		}
+	}
+
+	if events := compareInterfaceAddresses(ifis, ifis); len(events) != 0 {
+		t.Errorf("%d != %d", len(events), 0)
+	}
+}
+
+func TestInterfaceWatcherPoll(t *testing.T) {
+	eth0 := newTestInterfaceAddresses("eth0", "192.168.0.2")
+
+	var ifis []*interfaceAddresses
+	var ifisErr error
+	getInterfaces := func() ([]*interfaceAddresses, error) {
+		return ifis, ifisErr
+	}
+
+	var lastEvents []*InterfaceEvent
+	changed := func(_ []*interfaceAddresses, events []*InterfaceEvent) {
+		lastEvents = events
+	}
+
+	watcher := newInterfaceWatcher(time.Second, getInterfaces, changed, []*interfaceAddresses{eth0})
+
+	t.Run("NoChange", func(t *testing.T) {
+		ifis, ifisErr, lastEvents = []*interfaceAddresses{eth0}, nil, nil
+		watcher.Poll()
+		if lastEvents != nil {
+			t.Errorf("%v", lastEvents)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		ifis, ifisErr, lastEvents = nil, errInvalidInterfaceRule, nil
+		watcher.Poll()
+		if lastEvents != nil {
+			t.Errorf("%v", lastEvents)
+		}
+	})
+
+	t.Run("AllRemoved", func(t *testing.T) {
+		ifis, ifisErr, lastEvents = nil, errAvailableAddressNotFound, nil
+		watcher.Poll()
+		if len(lastEvents) != 1 || lastEvents[0].Type != InterfaceAddressRemoved {
+			t.Errorf("%v", lastEvents)
+		}
+	})
+
+	t.Run("Added", func(t *testing.T) {
+		ifis, ifisErr, lastEvents = []*interfaceAddresses{eth0}, nil, nil
+		watcher.Poll()
+		if len(lastEvents) != 1 || lastEvents[0].Type != InterfaceAddressAdded {
+			t.Errorf("%v", lastEvents)
+		}
+	})
+}
+
+type testInterfaceEventHandler struct {
+	sync.Mutex
+	events []InterfaceEvent
+}
+
+func (h *testInterfaceEventHandler) InterfaceChanged(event *InterfaceEvent) {
+	h.Lock()
+	defer h.Unlock()
+	h.events = append(h.events, *event)
+}
+
+func TestMessageManagerInterfacesChanged(t *testing.T) {
+	conf := newTestDefaultConfig()
+	conf.SetInterfaceWatchInterval(0)
+
+	ifis, err := getSelectedInterfaceAddresses(conf.AddressFamily(), conf.InterfaceFilter())
+	if err != nil {
+		t.Skip(err)
+	}
+
+	mgr := NewMessageManager()
+	mgr.SetConfig(conf)
+	handler := &testInterfaceEventHandler{} // nolint:exhaustruct
+	mgr.SetInterfaceEventHandler(handler)
+	err = mgr.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Stop()
+
+	addrs := mgr.Addresses()
+
+	// Unbind all servers from the removed interfaces
+
+	mgr.interfacesChanged([]*interfaceAddresses{}, compareInterfaceAddresses(ifis, nil))
+	if len(mgr.Addresses()) != 0 {
+		t.Errorf("%v is bound", mgr.Addresses())
+	}
+	if mgr.IsRunning() {
+		t.Errorf("manager is running without interfaces")
+	}
+
+	// Rebind all servers to the added interfaces
+
+	mgr.interfacesChanged(ifis, compareInterfaceAddresses(nil, ifis))
+	if len(mgr.Addresses()) != len(addrs) {
+		t.Errorf("%v != %v", mgr.Addresses(), addrs)
+	}
+	if !mgr.IsRunning() {
+		t.Errorf("manager is not running")
+	}
+	for _, server := range mgr.MulticastManager().Servers {
+		if server.UnicastServer == nil {
+			t.Errorf("unicast server is not set to the multicast server")
+		}
+	}
+
+	handler.Lock()
+	defer handler.Unlock()
+	if len(handler.events) != (len(addrs) * 2) {
+		t.Errorf("%d != %d", len(handler.events), len(addrs)*2)
+	}
+	for _, event := range handler.events {
+		if event.Err != nil {
+			t.Errorf("%s (%s) : %s", event.Type, event.Address, event.Err)
+		}
+	}
+}
+
+func TestMessageManagerAnnounceMessageFrom(t *testing.T) {
+	// Disable the loopback so that the own multicast servers do not handle the announcements after the test.
+	conf := newTestDefaultConfig()
+	conf.SetMulticastLoopbackEnabled(false)
+
+	mgr := NewMessageManager()
+	mgr.SetConfig(conf)
+	err := mgr.Start()
+	if err != nil {
+		t.Skip(err)
+	}
+	defer mgr.Stop()
+
+	obs := &testMessageObserver{} // nolint:exhaustruct
+	mgr.AddMessageObserver(obs)
+	defer mgr.RemoveMessageObserver(obs)
+
+	// The message is announced only from the server bound to the specified address, such as a newly added address.
+
+	for _, addr := range mgr.Addresses() {
+		msg, err := newTestMessage(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := mgr.AnnounceMessageFrom(addr, msg); err != nil {
+			t.Error(err)
+			continue
+		}
+		msgs := obs.messages()
+		if len(msgs) == 0 {
+			t.Errorf("%s : message is not announced", addr)
+			continue
+		}
+		host, _ := SplitAddressZone(addr)
+		if lastMsg := msgs[len(msgs)-1]; lastMsg.Direction != MessageSent || !lastMsg.From.IP.Equal(net.ParseIP(host)) {
+			t.Errorf("%s : %s != %s", lastMsg.Direction, lastMsg.From, addr)
+		}
+	}
+
+	msg, err := newTestMessage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.AnnounceMessageFrom("198.51.100.254", msg); !errors.Is(err, ErrInvalid) {
+		t.Errorf("message is announced from an unbound address (%v)", err)
+	}
+}
+
+func TestMessageManagerConcurrentStop(t *testing.T) {
+	conf := newTestDefaultConfig()
+	conf.SetInterfaceWatchInterval(time.Millisecond * 10)
+
+	mgr := NewMessageManager()
+	mgr.SetConfig(conf)
+	if err := mgr.Start(); err != nil {
+		t.Skip(err)
+	}
+
+	// The watcher is stopped only once even when the manager is stopped concurrently.
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if err := mgr.Stop(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if mgr.IsRunning() {
+		t.Errorf("manager is running")
+	}
+}
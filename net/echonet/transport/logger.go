@@ -5,6 +5,9 @@
 package transport
 
 import (
+	"encoding/hex"
+	"sync/atomic"
+
 	"github.com/cybergarage/go-logger/log"
 )
 
@@ -21,15 +24,70 @@ const (
 	logSocketDirectionRead  = 1
 )
 
-func outputSocketLog(logLevel log.Level, socketType string, socketDirection int, msgFrom string, msgTo string, msg string, msgSize int) {
+// LogMessageFormatter formats the message frames in the socket logs.
+type LogMessageFormatter func(b []byte) string
+
+var logMessageFormatter atomic.Pointer[LogMessageFormatter]
+
+// SetLogMessageFormatter sets the formatter of the message frames in the socket logs.
+// The message frames are output as the hex strings when the formatter is nil.
+func SetLogMessageFormatter(f LogMessageFormatter) {
+	if f == nil {
+		logMessageFormatter.Store(nil)
+		return
+	}
+	logMessageFormatter.Store(&f)
+}
+
+// logFrame represents a message frame which is formatted only when the log is output.
+type logFrame interface {
+	Bytes() []byte
+	String() string
+}
+
+// hexBytes represents bytes which are encoded to a hex string only when the log is output.
+type hexBytes []byte
+
+// Bytes returns the bytes.
+func (b hexBytes) Bytes() []byte {
+	return b
+}
+
+// String returns the hex string of the bytes.
+func (b hexBytes) String() string {
+	return hex.EncodeToString(b)
+}
+
+// formatLogFrame returns the string of the specified message frame with the log message formatter.
+func formatLogFrame(msg logFrame) string {
+	f := logMessageFormatter.Load()
+	if f == nil {
+		return msg.String()
+	}
+	return (*f)(msg.Bytes())
+}
+
+// isSocketLogEnabled returns true when the specified log level is output by the shared logger, otherwise false.
+func isSocketLogEnabled(logLevel log.Level) bool {
+	logger := log.GetSharedLogger()
+	if logger == nil {
+		return false
+	}
+	return logLevel <= logger.Level()
+}
+
+func outputSocketLog(logLevel log.Level, socketType string, socketDirection int, msgFrom string, msgTo string, msg logFrame, msgSize int) {
+	if !isSocketLogEnabled(logLevel) {
+		return
+	}
 	switch socketDirection {
 	case logSocketDirectionWrite:
 		{
-			log.Outputf(logLevel, logSocketWriteFormat, socketType, msgFrom, msgTo, msgSize, msg)
+			log.Outputf(logLevel, logSocketWriteFormat, socketType, msgFrom, msgTo, msgSize, formatLogFrame(msg))
 		}
 	case logSocketDirectionRead:
 		{
-			log.Outputf(logLevel, logSocketReadFormat, socketType, msgTo, msgFrom, msgSize, msg)
+			log.Outputf(logLevel, logSocketReadFormat, socketType, msgTo, msgFrom, msgSize, formatLogFrame(msg))
 		}
 	}
 }
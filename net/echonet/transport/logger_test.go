@@ -0,0 +1,30 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogMessageFormatter(t *testing.T) {
+	frame := hexBytes{0x10, 0x81}
+	if s := formatLogFrame(frame); s != "1081" {
+		t.Errorf("%s != %s", s, "1081")
+	}
+
+	SetLogMessageFormatter(func(b []byte) string {
+		return fmt.Sprintf("%d bytes", len(b))
+	})
+	defer SetLogMessageFormatter(nil)
+	if s := formatLogFrame(frame); s != "2 bytes" {
+		t.Errorf("%s != %s", s, "2 bytes")
+	}
+
+	SetLogMessageFormatter(nil)
+	if s := formatLogFrame(frame); s != "1081" {
+		t.Errorf("%s != %s", s, "1081")
+	}
+}
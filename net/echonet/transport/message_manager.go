@@ -5,25 +5,42 @@
 package transport
 
 import (
+	"sync"
+
+	"github.com/cybergarage/go-logger/log"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 // A MessageManager represents a multicast server list.
 type MessageManager struct {
+	sync.RWMutex
 	port           uint
 	messageHandler protocol.MessageHandler
 	multicastMgr   *MulticastManager
 	unicastMgr     *UnicastManager
+	observers      *messageObservers
+	metrics        *transportMetrics
+	watcher        *interfaceWatcher
+	eventHandler   InterfaceEventHandler
 }
 
 // NewMessageManager returns a new message manager.
 func NewMessageManager() *MessageManager {
 	mgr := &MessageManager{
+		RWMutex:        sync.RWMutex{},
 		port:           UDPPort,
 		messageHandler: nil,
 		multicastMgr:   NewMulticastManager(),
 		unicastMgr:     NewUnicastManager(),
+		observers:      newMessageObservers(),
+		metrics:        newTransportMetrics(),
+		watcher:        nil,
+		eventHandler:   nil,
 	}
+	mgr.multicastMgr.observers = mgr.observers
+	mgr.unicastMgr.observers = mgr.observers
+	mgr.multicastMgr.metrics = mgr.metrics
+	mgr.unicastMgr.metrics = mgr.metrics
 	return mgr
 }
 
@@ -37,11 +54,13 @@ func (mgr *MessageManager) UnicastManager() *UnicastManager {
 	return mgr.unicastMgr
 }
 
-// Addresses return the bound interface addresses.
+// Addresses return the bound interface addresses. The IPv6 link-local addresses have the scoped addressing zone.
 func (mgr *MessageManager) Addresses() []string {
+	mgr.RLock()
+	defer mgr.RUnlock()
 	ifaddrs := []string{}
-	for _, server := range mgr.MulticastManager().Servers {
-		ifaddr, err := server.Address()
+	for _, server := range mgr.UnicastManager().Servers {
+		ifaddr, err := server.UDPSocket.Address()
 		if err != nil {
 			continue
 		}
@@ -50,9 +69,20 @@ func (mgr *MessageManager) Addresses() []string {
 	return ifaddrs
 }
 
+// InterfaceAddresses returns the bound addresses for each interface name.
+func (mgr *MessageManager) InterfaceAddresses() map[string][]string {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	return mgr.UnicastManager().InterfaceAddresses()
+}
+
 // SetConfig sets all configuration flags.
 func (mgr *MessageManager) SetConfig(newConfig *Config) {
 	mgr.unicastMgr.SetConfig(newConfig)
+	mgr.multicastMgr.SetParseMode(newConfig.ParseMode())
+	mgr.multicastMgr.SetAddressFamily(newConfig.AddressFamily())
+	mgr.multicastMgr.SetInterfaceFilter(newConfig.InterfaceFilter())
+	mgr.multicastMgr.SetMulticastConfig(newConfig.MulticastConfig)
 }
 
 // Config returns all current configurations.
@@ -77,6 +107,32 @@ func (mgr *MessageManager) SetMessageHandler(h protocol.MessageHandler) {
 	mgr.messageHandler = h
 }
 
+// AddMessageObserver adds the specified observer to observe the message frames which are sent and received by all servers.
+// The observer can be added while the manager is running.
+func (mgr *MessageManager) AddMessageObserver(o MessageObserver) {
+	mgr.observers.Add(o)
+}
+
+// RemoveMessageObserver removes the specified observer.
+func (mgr *MessageManager) RemoveMessageObserver(o MessageObserver) {
+	mgr.observers.Remove(o)
+}
+
+// SetInterfaceEventHandler sets a handler to receive the interface changes after the servers are rebound.
+func (mgr *MessageManager) SetInterfaceEventHandler(h InterfaceEventHandler) {
+	mgr.eventHandler = h
+}
+
+// InterfaceEventHandler returns the handler to receive the interface changes.
+func (mgr *MessageManager) InterfaceEventHandler() InterfaceEventHandler {
+	return mgr.eventHandler
+}
+
+// Metrics returns a snapshot of the traffic counters of all servers.
+func (mgr *MessageManager) Metrics() *Metrics {
+	return mgr.metrics.Snapshot()
+}
+
 // MessageHandler returns the listener of the manager.
 func (mgr *MessageManager) MessageHandler() protocol.MessageHandler {
 	return mgr.messageHandler
@@ -84,25 +140,47 @@ func (mgr *MessageManager) MessageHandler() protocol.MessageHandler {
 
 // SendMessage send a message to the destination address.
 func (mgr *MessageManager) SendMessage(addr string, port int, msg *protocol.Message) (int, error) {
+	mgr.RLock()
+	defer mgr.RUnlock()
 	return mgr.unicastMgr.SendMessage(addr, port, msg)
 }
 
 // AnnounceMessage sends a message to the multicast address.
 func (mgr *MessageManager) AnnounceMessage(msg *protocol.Message) error {
+	mgr.RLock()
+	defer mgr.RUnlock()
 	return mgr.unicastMgr.AnnounceMessage(msg)
 }
 
+// AnnounceMessageFrom sends a message to the multicast address from the server bound to the specified local address.
+func (mgr *MessageManager) AnnounceMessageFrom(addr string, msg *protocol.Message) error {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	return mgr.unicastMgr.AnnounceMessageFrom(addr, msg)
+}
+
 // PostMessage posts a message to the destination address and gets the response message.
 func (mgr *MessageManager) PostMessage(addr string, port int, msg *protocol.Message) (*protocol.Message, error) {
+	mgr.RLock()
+	defer mgr.RUnlock()
 	return mgr.unicastMgr.PostMessage(addr, port, msg)
 }
 
-// Start starts all transport managers.
+// Start starts all transport managers, and starts to watch the interface changes when the watch interval is not zero.
 func (mgr *MessageManager) Start() error {
 	err := mgr.Stop()
 	if err != nil {
 		return err
 	}
+
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	err = mgr.Config().ValidateMulticastConfig()
+	if err != nil {
+		return err
+	}
+
 	err = mgr.unicastMgr.Start()
 	if err != nil {
 		return err
@@ -110,24 +188,54 @@ func (mgr *MessageManager) Start() error {
 
 	err = mgr.multicastMgr.Start()
 	if err != nil {
-		mgr.Stop()
+		mgr.stopManagers()
 		return err
 	}
 
 	// Set appropriate unicast servers to all multicast servers to response the multicast messages
 	err = mgr.multicastMgr.setUnicastManager(mgr.unicastMgr)
 	if err != nil {
-		mgr.Stop()
+		mgr.stopManagers()
 		return err
 	}
 
 	mgr.SetPort(mgr.unicastMgr.Port())
 
+	if interval := mgr.Config().InterfaceWatchInterval(); 0 < interval {
+		getInterfaces := func() ([]*interfaceAddresses, error) {
+			return getSelectedInterfaceAddresses(mgr.Config().AddressFamily(), mgr.Config().InterfaceFilter())
+		}
+		ifis, err := getInterfaces()
+		if err != nil {
+			mgr.stopManagers()
+			return err
+		}
+		mgr.watcher = newInterfaceWatcher(interval, getInterfaces, mgr.interfacesChanged, ifis)
+		mgr.watcher.Start()
+	}
+
 	return nil
 }
 
 // Stop stops all transport managers.
 func (mgr *MessageManager) Stop() error {
+	// The watcher is stopped without the lock because the running poll rebinds the servers with the lock.
+	mgr.Lock()
+	watcher := mgr.watcher
+	mgr.watcher = nil
+	mgr.Unlock()
+	if watcher != nil {
+		watcher.Stop()
+	}
+
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	return mgr.stopManagers()
+}
+
+// stopManagers stops all transport managers without the lock.
+func (mgr *MessageManager) stopManagers() error {
 	var lastErr error
 	err := mgr.multicastMgr.Stop()
 	if err != nil {
@@ -142,8 +250,41 @@ func (mgr *MessageManager) Stop() error {
 	return lastErr
 }
 
+// interfacesChanged rebinds the servers to the specified interfaces and addresses, and notifies the changes to the handler.
+func (mgr *MessageManager) interfacesChanged(ifis []*interfaceAddresses, events []*InterfaceEvent) {
+	mgr.Lock()
+	errs := mgr.unicastMgr.updateInterfaces(ifis)
+	for addr, err := range mgr.multicastMgr.updateInterfaces(ifis) {
+		if _, ok := errs[addr]; !ok {
+			errs[addr] = err
+		}
+	}
+	if 0 < len(mgr.unicastMgr.Servers) {
+		if err := mgr.multicastMgr.setUnicastManager(mgr.unicastMgr); err != nil {
+			log.Error(err)
+		}
+	}
+	mgr.Unlock()
+
+	for _, event := range events {
+		if event.Type == InterfaceAddressAdded {
+			event.Err = errs[event.Address]
+		}
+		log.Infof("interface %s (%s) : %s", event.Type, event.Interface, event.Address)
+		if event.Err != nil {
+			log.Error(event.Err)
+		}
+		if mgr.eventHandler != nil {
+			mgr.eventHandler.InterfaceChanged(event)
+		}
+	}
+}
+
 // IsRunning returns true whether the local managers are running, otherwise false.
 func (mgr *MessageManager) IsRunning() bool {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
 	if !mgr.unicastMgr.IsRunning() {
 		return false
 	}
@@ -7,6 +7,8 @@ package transport
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,7 +22,7 @@ type testMessageManager struct {
 
 	FromPort                int
 	FromPacketType          int
-	lastNotificationMessage *protocol.Message
+	lastNotificationMessage atomic.Pointer[protocol.Message]
 }
 
 func newTestMessage(tid uint) (*protocol.Message, error) {
@@ -53,7 +55,7 @@ func newTestMessageManager() *testMessageManager {
 		MessageManager:          NewMessageManager(),
 		FromPort:                0,
 		FromPacketType:          protocol.UnknownPacket,
-		lastNotificationMessage: nil,
+		lastNotificationMessage: atomic.Pointer[protocol.Message]{},
 	}
 	return mgr
 }
@@ -66,7 +68,7 @@ func (mgr *testMessageManager) ProtocolMessageReceived(msg *protocol.Message) (*
 		copyMsg, err := protocol.NewMessageWithMessage(msg)
 		if err == nil {
 			// log.Trace("lastNotificationMessage (O) : %s", copyMsg.String())
-			mgr.lastNotificationMessage = copyMsg
+			mgr.lastNotificationMessage.Store(copyMsg)
 		} else {
 			log.Errorf("ProtocolMessageReceived (X) : %s", msg.String())
 		}
@@ -92,7 +94,7 @@ func testUnicastMessagingWithRunningManagers(t *testing.T, mgrs []*testMessageMa
 		dstMgr := dstMgrs[n]
 		dstMgr.FromPort = srcMgr.Port()
 		dstMgr.FromPacketType = protocol.UnicastPacket
-		dstMgr.lastNotificationMessage = nil
+		dstMgr.lastNotificationMessage.Store(nil)
 
 		t.Run(fmt.Sprintf("Unicast:%d->%d", srcMgr.Port(), dstMgr.Port()), func(t *testing.T) {
 			msg, err := newTestMessage(uint(n))
@@ -117,7 +119,7 @@ func testUnicastMessagingWithRunningManagers(t *testing.T, mgrs []*testMessageMa
 
 			time.Sleep(time.Second)
 
-			dstLastMsg := dstMgr.lastNotificationMessage
+			dstLastMsg := dstMgr.lastNotificationMessage.Load()
 			if dstLastMsg == nil {
 				t.Errorf("%s != (nil)", msg)
 				return
@@ -200,3 +202,103 @@ func TestUnicastMessaging(t *testing.T) {
 		testUnicastMessagingWithConfig(t, conf, false)
 	})
 }
+
+func hasTestIPv6Address() bool {
+	addrs, err := GetAvailableAddressesWithFamily(AddressFamilyIPv6)
+	if err != nil {
+		return false
+	}
+	return 0 < len(addrs)
+}
+
+func TestIPv6Messaging(t *testing.T) {
+	if !hasTestIPv6Address() {
+		t.Skip("IPv6 address is not available")
+	}
+
+	conf := newTestDefaultConfig()
+	conf.SetAddressFamily(AddressFamilyIPv6)
+
+	mgrs := []*testMessageManager{
+		newTestMessageManager(),
+		newTestMessageManager(),
+	}
+
+	for n, mgr := range mgrs {
+		mgr.SetConfig(conf)
+		mgr.SetPort(UDPPort + n)
+		mgr.SetMessageHandler(mgr)
+		err := mgr.Start()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer mgr.Stop()
+	}
+
+	srcMgr := mgrs[0]
+	dstMgr := mgrs[1]
+
+	for _, mgr := range mgrs {
+		for _, addr := range mgr.Addresses() {
+			if !IsIPv6Address(addr) {
+				t.Errorf("%s is not an IPv6 address", addr)
+			}
+		}
+	}
+
+	checkSourceAddress := func(t *testing.T, msg *protocol.Message) {
+		t.Helper()
+		if msg == nil {
+			t.Errorf("message is not received")
+			return
+		}
+		srcAddr := msg.SourceAddress()
+		if !IsIPv6Address(srcAddr) {
+			t.Errorf("%s is not an IPv6 address", srcAddr)
+			return
+		}
+		host, zone := SplitAddressZone(srcAddr)
+		if net.ParseIP(host).IsLinkLocalUnicast() && len(zone) == 0 {
+			t.Errorf("%s has no zone", srcAddr)
+		}
+	}
+
+	// Send unicast messages to all bound addresses, and check the source addresses
+
+	for n, dstAddr := range dstMgr.Addresses() {
+		t.Run(fmt.Sprintf("Unicast:%s", dstAddr), func(t *testing.T) {
+			msg, err := newTestMessage(uint(n))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			dstMgr.lastNotificationMessage.Store(nil)
+			_, err = srcMgr.SendMessage(dstAddr, dstMgr.Port(), msg)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			time.Sleep(time.Millisecond * 500)
+			checkSourceAddress(t, dstMgr.lastNotificationMessage.Load())
+		})
+	}
+
+	// Announce a message to the IPv6 multicast address
+
+	t.Run("Multicast", func(t *testing.T) {
+		msg, err := newTestMessage(0xFF)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		dstMgr.lastNotificationMessage.Store(nil)
+		err = srcMgr.AnnounceMessage(msg)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		time.Sleep(time.Millisecond * 500)
+		checkSourceAddress(t, dstMgr.lastNotificationMessage.Load())
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// SocketType represents a type of the sockets which the metrics are counted for.
+type SocketType int
+
+const (
+	// SocketMulticast represents the UDP multicast sockets.
+	SocketMulticast SocketType = iota
+	// SocketUDP represents the UDP unicast sockets.
+	SocketUDP
+	// SocketTCP represents the TCP unicast sockets.
+	SocketTCP
+)
+
+// SocketTypes returns all socket types.
+func SocketTypes() []SocketType {
+	return []SocketType{SocketMulticast, SocketUDP, SocketTCP}
+}
+
+// String returns the string representation of the socket type.
+func (t SocketType) String() string {
+	switch t {
+	case SocketMulticast:
+		return "multicast"
+	case SocketUDP:
+		return "udp"
+	case SocketTCP:
+		return "tcp"
+	}
+	return ""
+}
+
+// SocketMetrics represents the traffic counters of a socket type.
+type SocketMetrics struct {
+	PacketsIn   uint64
+	PacketsOut  uint64
+	BytesIn     uint64
+	BytesOut    uint64
+	ParseErrors uint64
+}
+
+// Metrics represents a snapshot of the traffic counters of a transport.
+type Metrics struct {
+	Sockets map[SocketType]SocketMetrics
+}
+
+// socketCounters represents the goroutine-safe traffic counters of a socket type.
+type socketCounters struct {
+	packetsIn   atomic.Uint64
+	packetsOut  atomic.Uint64
+	bytesIn     atomic.Uint64
+	bytesOut    atomic.Uint64
+	parseErrors atomic.Uint64
+}
+
+// transportMetrics represents the traffic counters which are shared by the servers and sockets of a transport.
+// All methods are nil-safe, so that the sockets without the metrics never count.
+type transportMetrics struct {
+	sockets [SocketTCP + 1]socketCounters
+}
+
+// newTransportMetrics returns new zero counters.
+func newTransportMetrics() *transportMetrics {
+	return &transportMetrics{} // nolint:exhaustruct
+}
+
+// countReceived counts a received message frame of the specified size.
+func (m *transportMetrics) countReceived(t SocketType, n int) {
+	if m == nil {
+		return
+	}
+	m.sockets[t].packetsIn.Add(1)
+	m.sockets[t].bytesIn.Add(uint64(n))
+}
+
+// countSent counts a sent message frame of the specified size.
+func (m *transportMetrics) countSent(t SocketType, n int) {
+	if m == nil {
+		return
+	}
+	m.sockets[t].packetsOut.Add(1)
+	m.sockets[t].bytesOut.Add(uint64(n))
+}
+
+// countParseError counts a received message frame which could not be parsed.
+func (m *transportMetrics) countParseError(t SocketType) {
+	if m == nil {
+		return
+	}
+	m.sockets[t].parseErrors.Add(1)
+}
+
+// Snapshot returns the current counters.
+func (m *transportMetrics) Snapshot() *Metrics {
+	metrics := &Metrics{
+		Sockets: map[SocketType]SocketMetrics{},
+	}
+	if m == nil {
+		return metrics
+	}
+	for _, t := range SocketTypes() {
+		c := &m.sockets[t]
+		metrics.Sockets[t] = SocketMetrics{
+			PacketsIn:   c.packetsIn.Load(),
+			PacketsOut:  c.packetsOut.Load(),
+			BytesIn:     c.bytesIn.Load(),
+			BytesOut:    c.bytesOut.Load(),
+			ParseErrors: c.parseErrors.Load(),
+		}
+	}
+	return metrics
+}
+
+// udpSocketTypeOf returns the socket type of the UDP message frame which is sent to or received at the specified address.
+func udpSocketTypeOf(addr net.Addr) SocketType {
+	if udpAddr, ok := addr.(*net.UDPAddr); ok && udpAddr.IP.IsMulticast() {
+		return SocketMulticast
+	}
+	return SocketUDP
+}
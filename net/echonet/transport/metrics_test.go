@@ -0,0 +1,97 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func TestTransportMetricsNil(t *testing.T) {
+	var m *transportMetrics
+	m.countReceived(SocketUDP, 1)
+	m.countSent(SocketUDP, 1)
+	m.countParseError(SocketUDP)
+	metrics := m.Snapshot()
+	if len(metrics.Sockets) != 0 {
+		t.Errorf("%v", metrics.Sockets)
+	}
+}
+
+func TestUDPSocketTypeOf(t *testing.T) {
+	tests := []struct {
+		addr       net.Addr
+		socketType SocketType
+	}{
+		{&net.UDPAddr{IP: net.ParseIP(MulticastIPv4Address), Port: Port, Zone: ""}, SocketMulticast},
+		{&net.UDPAddr{IP: net.ParseIP(MulticastIPv6Address), Port: Port, Zone: ""}, SocketMulticast},
+		{&net.UDPAddr{IP: net.ParseIP("192.168.0.1"), Port: Port, Zone: ""}, SocketUDP},
+	}
+	for _, test := range tests {
+		if socketType := udpSocketTypeOf(test.addr); socketType != test.socketType {
+			t.Errorf("%s : %s != %s", test.addr, socketType, test.socketType)
+		}
+	}
+}
+
+func TestVirtualTransportMetrics(t *testing.T) {
+	vnet := NewVirtualNetwork()
+	transports, handlers := newTestVirtualTransports(t, vnet, 2)
+	src := transports[0]
+	dst := transports[1]
+
+	// The notification message is not answered by the test handlers.
+	msg, err := newTestMessage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.SetESV(protocol.ESVNotification)
+	msgSize := uint64(msg.Size())
+
+	_, err = src.SendMessage(dst.Address(), dst.Port(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgs := handlers[1].waitMessages(1); len(msgs) != 1 {
+		t.Fatalf("%d != %d", len(msgs), 1)
+	}
+
+	err = src.AnnounceMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isReceived := handlers[1].waitMessage(func(msg *protocol.Message) bool {
+		return msg.IsPacketType(protocol.MulticastPacket)
+	})
+	if !isReceived {
+		t.Fatalf("multicast message is not received")
+	}
+
+	srcMetrics := src.Metrics()
+	if m := srcMetrics.Sockets[SocketUDP]; m.PacketsOut != 1 || m.BytesOut != msgSize {
+		t.Errorf("%+v", m)
+	}
+	if m := srcMetrics.Sockets[SocketMulticast]; m.PacketsOut != 1 || m.BytesOut != msgSize {
+		t.Errorf("%+v", m)
+	}
+
+	dstMetrics := dst.Metrics()
+	if m := dstMetrics.Sockets[SocketUDP]; m.PacketsIn != 1 || m.BytesIn != msgSize {
+		t.Errorf("%+v", m)
+	}
+	if m := dstMetrics.Sockets[SocketMulticast]; m.PacketsIn != 1 || m.BytesIn != msgSize {
+		t.Errorf("%+v", m)
+	}
+
+	_, err = dst.receiveMessage(src.udpAddr(), []byte{0x00}, protocol.UDPUnicastPacket)
+	if err == nil {
+		t.Errorf("invalid message is parsed")
+	}
+	if m := dst.Metrics().Sockets[SocketUDP]; m.ParseErrors != 1 {
+		t.Errorf("%+v", m)
+	}
+}
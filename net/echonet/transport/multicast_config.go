@@ -0,0 +1,127 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+)
+
+// MulticastConfig represents a cofiguration for multicast group.
+type MulticastConfig struct {
+	ipv4Address     string
+	ipv6Address     string
+	port            int
+	ttl             int
+	loopbackEnabled bool
+}
+
+// NewDefaultMulticastConfig returns a default configuration.
+func NewDefaultMulticastConfig() *MulticastConfig {
+	conf := &MulticastConfig{
+		ipv4Address:     MulticastIPv4Address,
+		ipv6Address:     MulticastIPv6Address,
+		port:            Port,
+		ttl:             DefaultMulticastTTL,
+		loopbackEnabled: true,
+	}
+	return conf
+}
+
+// SetConfig sets all flags.
+func (conf *MulticastConfig) SetConfig(newConfig *MulticastConfig) {
+	conf.ipv4Address = newConfig.ipv4Address
+	conf.ipv6Address = newConfig.ipv6Address
+	conf.port = newConfig.port
+	conf.ttl = newConfig.ttl
+	conf.loopbackEnabled = newConfig.loopbackEnabled
+}
+
+// SetMulticastIPv4Address sets the specified IPv4 multicast group address.
+func (conf *MulticastConfig) SetMulticastIPv4Address(addr string) {
+	conf.ipv4Address = addr
+}
+
+// MulticastIPv4Address returns the IPv4 multicast group address. The address is MulticastIPv4Address by default.
+func (conf *MulticastConfig) MulticastIPv4Address() string {
+	return conf.ipv4Address
+}
+
+// SetMulticastIPv6Address sets the specified IPv6 multicast group address.
+func (conf *MulticastConfig) SetMulticastIPv6Address(addr string) {
+	conf.ipv6Address = addr
+}
+
+// MulticastIPv6Address returns the IPv6 multicast group address. The address is MulticastIPv6Address by default.
+func (conf *MulticastConfig) MulticastIPv6Address() string {
+	return conf.ipv6Address
+}
+
+// MulticastAddress returns the multicast group address of the address family of the specified interface address.
+func (conf *MulticastConfig) MulticastAddress(ifaddr string) string {
+	if IsIPv6Address(ifaddr) {
+		return conf.ipv6Address
+	}
+	return conf.ipv4Address
+}
+
+// SetMulticastPort sets the specified port of the multicast group.
+func (conf *MulticastConfig) SetMulticastPort(port int) {
+	conf.port = port
+}
+
+// MulticastPort returns the port of the multicast group. The port is Port by default.
+func (conf *MulticastConfig) MulticastPort() int {
+	return conf.port
+}
+
+// SetMulticastTTL sets the specified TTL (IPv6 hop limit) of the announced multicast messages.
+func (conf *MulticastConfig) SetMulticastTTL(ttl int) {
+	conf.ttl = ttl
+}
+
+// MulticastTTL returns the TTL (IPv6 hop limit) of the announced multicast messages. The TTL is DefaultMulticastTTL by default.
+func (conf *MulticastConfig) MulticastTTL() int {
+	return conf.ttl
+}
+
+// SetMulticastLoopbackEnabled sets a flag to deliver the announced multicast messages to the nodes on the same host.
+func (conf *MulticastConfig) SetMulticastLoopbackEnabled(flag bool) {
+	conf.loopbackEnabled = flag
+}
+
+// MulticastLoopbackEnabled returns true whether the announced multicast messages are delivered to the nodes on the same host, otherwise false.
+func (conf *MulticastConfig) MulticastLoopbackEnabled() bool {
+	return conf.loopbackEnabled
+}
+
+// ValidateMulticastConfig returns an error when the multicast group addresses, port or TTL are invalid.
+func (conf *MulticastConfig) ValidateMulticastConfig() error {
+	for _, addr := range []string{conf.ipv4Address, conf.ipv6Address} {
+		ip := net.ParseIP(addr)
+		if ip == nil || !ip.IsMulticast() {
+			return fmt.Errorf("%w (%s)", errInvalidMulticastAddress, addr)
+		}
+	}
+	if ip := net.ParseIP(conf.ipv4Address); ip.To4() == nil {
+		return fmt.Errorf("%w (%s)", errInvalidMulticastAddress, conf.ipv4Address)
+	}
+	if ip := net.ParseIP(conf.ipv6Address); ip.To4() != nil {
+		return fmt.Errorf("%w (%s)", errInvalidMulticastAddress, conf.ipv6Address)
+	}
+	if conf.port <= 0 || 65535 < conf.port {
+		return fmt.Errorf("%w (%d)", errInvalidMulticastPort, conf.port)
+	}
+	if conf.ttl < 0 || 255 < conf.ttl {
+		return fmt.Errorf("%w (%d)", errInvalidMulticastTTL, conf.ttl)
+	}
+	return nil
+}
+
+// Equals returns true whether the specified other class is same, otherwise false.
+func (conf *MulticastConfig) Equals(otherConf *MulticastConfig) bool {
+	return reflect.DeepEqual(conf, otherConf)
+}
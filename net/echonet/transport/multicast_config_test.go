@@ -0,0 +1,131 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+const (
+	testMulticastIPv4Address = "239.255.36.10"
+	testMulticastPort        = 13610
+)
+
+func TestMulticastConfigEquals(t *testing.T) {
+	conf01 := NewDefaultMulticastConfig()
+	conf02 := NewDefaultMulticastConfig()
+
+	if !conf01.Equals(conf02) {
+		t.Errorf("%v != %v", conf01, conf02)
+	}
+
+	conf01.SetMulticastIPv4Address(testMulticastIPv4Address)
+	conf01.SetMulticastPort(testMulticastPort)
+	conf01.SetMulticastTTL(4)
+	conf01.SetMulticastLoopbackEnabled(false)
+	if conf01.Equals(conf02) {
+		t.Errorf("%v == %v", conf01, conf02)
+	}
+
+	conf02.SetConfig(conf01)
+	if !conf01.Equals(conf02) {
+		t.Errorf("%v != %v", conf01, conf02)
+	}
+
+	if addr := conf02.MulticastAddress("192.168.0.1"); addr != testMulticastIPv4Address {
+		t.Errorf("%s != %s", addr, testMulticastIPv4Address)
+	}
+	if addr := conf02.MulticastAddress("fe80::1"); addr != MulticastIPv6Address {
+		t.Errorf("%s != %s", addr, MulticastIPv6Address)
+	}
+}
+
+func TestMulticastConfigValidate(t *testing.T) {
+	tests := []struct {
+		ipv4Addr string
+		ipv6Addr string
+		port     int
+		ttl      int
+		err      error
+	}{
+		{MulticastIPv4Address, MulticastIPv6Address, Port, DefaultMulticastTTL, nil},
+		{testMulticastIPv4Address, "ff05::3610", testMulticastPort, 255, nil},
+		{"192.168.0.1", MulticastIPv6Address, Port, DefaultMulticastTTL, errInvalidMulticastAddress},
+		{MulticastIPv6Address, MulticastIPv6Address, Port, DefaultMulticastTTL, errInvalidMulticastAddress},
+		{MulticastIPv4Address, MulticastIPv4Address, Port, DefaultMulticastTTL, errInvalidMulticastAddress},
+		{MulticastIPv4Address, MulticastIPv6Address, 0, DefaultMulticastTTL, errInvalidMulticastPort},
+		{MulticastIPv4Address, MulticastIPv6Address, Port, 256, errInvalidMulticastTTL},
+	}
+	for _, test := range tests {
+		conf := NewDefaultMulticastConfig()
+		conf.SetMulticastIPv4Address(test.ipv4Addr)
+		conf.SetMulticastIPv6Address(test.ipv6Addr)
+		conf.SetMulticastPort(test.port)
+		conf.SetMulticastTTL(test.ttl)
+		err := conf.ValidateMulticastConfig()
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%v : %v", conf, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%v : %v != %v", conf, err, test.err)
+		}
+	}
+}
+
+func TestMulticastSocketWithMulticastConfig(t *testing.T) {
+	ifis, err := getSelectedInterfaceAddresses(AddressFamilyIPv4, NewInterfaceFilter())
+	if err != nil || len(ifis) == 0 {
+		t.Skipf("no available IPv4 interface (%v)", err)
+	}
+	ifi := ifis[0].ifi
+	ifaddr := ifis[0].addrs[0]
+
+	conf := NewDefaultMulticastConfig()
+	conf.SetMulticastIPv4Address(testMulticastIPv4Address)
+	conf.SetMulticastPort(testMulticastPort)
+	conf.SetMulticastTTL(2)
+
+	msock := NewMulticastSocket()
+	msock.SetMulticastConfig(conf)
+	if err := msock.Bind(ifi, ifaddr); err != nil {
+		t.Fatal(err)
+	}
+	defer msock.Close()
+
+	if addr := msock.multicastAddr; addr.IP.String() != testMulticastIPv4Address || addr.Port != testMulticastPort {
+		t.Errorf("%s != %s:%d", addr, testMulticastIPv4Address, testMulticastPort)
+	}
+
+	usock := NewUnicastUDPSocket()
+	usock.SetMulticastConfig(conf)
+	if err := usock.Bind(ifi, ifaddr, testMulticastPort+1); err != nil {
+		t.Fatal(err)
+	}
+	defer usock.Close()
+
+	msg, err := newTestMessage(uint(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := usock.AnnounceMessage(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	msock.Conn.SetReadDeadline(time.Now().Add(time.Second))
+	recvMsg, err := msock.ReadMessage()
+	if err != nil {
+		t.Skipf("multicast message is not received (%v)", err)
+	}
+	if recvMsg.TID() != msg.TID() || !recvMsg.IsESV(protocol.ESVWriteReadRequest) {
+		t.Errorf("%s != %s", recvMsg, msg)
+	}
+}
@@ -12,15 +12,27 @@ import (
 
 // A MulticastManager represents a multicast server manager.
 type MulticastManager struct {
-	Servers []*MulticastServer
-	Handler MulticastHandler
+	Servers       []*MulticastServer
+	Handler       MulticastHandler
+	parseMode     protocol.ParseMode
+	addressFamily AddressFamily
+	filter        *InterfaceFilter
+	multicastConf *MulticastConfig
+	observers     *messageObservers
+	metrics       *transportMetrics
 }
 
 // NewMulticastManager returns a new MulticastManager.
 func NewMulticastManager() *MulticastManager {
 	mgr := &MulticastManager{
-		Servers: make([]*MulticastServer, 0),
-		Handler: nil,
+		Servers:       make([]*MulticastServer, 0),
+		Handler:       nil,
+		parseMode:     protocol.ParseModeStrict,
+		addressFamily: AddressFamilyIPv4,
+		filter:        NewInterfaceFilter(),
+		multicastConf: NewDefaultMulticastConfig(),
+		observers:     nil,
+		metrics:       nil,
 	}
 	return mgr
 }
@@ -30,6 +42,46 @@ func (mgr *MulticastManager) SetHandler(l MulticastHandler) {
 	mgr.Handler = l
 }
 
+// SetParseMode sets the specified mode to parse the received messages of the servers.
+func (mgr *MulticastManager) SetParseMode(mode protocol.ParseMode) {
+	mgr.parseMode = mode
+}
+
+// ParseMode returns the mode to parse the received messages of the servers.
+func (mgr *MulticastManager) ParseMode() protocol.ParseMode {
+	return mgr.parseMode
+}
+
+// SetAddressFamily sets the specified address families to bind the servers.
+func (mgr *MulticastManager) SetAddressFamily(family AddressFamily) {
+	mgr.addressFamily = family
+}
+
+// AddressFamily returns the address families to bind the servers.
+func (mgr *MulticastManager) AddressFamily() AddressFamily {
+	return mgr.addressFamily
+}
+
+// SetInterfaceFilter sets the specified filter to select the interfaces and addresses to bind the servers.
+func (mgr *MulticastManager) SetInterfaceFilter(filter *InterfaceFilter) {
+	mgr.filter.SetFilter(filter)
+}
+
+// InterfaceFilter returns the filter to select the interfaces and addresses to bind the servers.
+func (mgr *MulticastManager) InterfaceFilter() *InterfaceFilter {
+	return mgr.filter
+}
+
+// SetMulticastConfig sets the specified multicast group and options to bind the servers.
+func (mgr *MulticastManager) SetMulticastConfig(conf *MulticastConfig) {
+	mgr.multicastConf.SetConfig(conf)
+}
+
+// MulticastConfig returns the multicast group and options to bind the servers.
+func (mgr *MulticastManager) MulticastConfig() *MulticastConfig {
+	return mgr.multicastConf
+}
+
 // AnnounceMessage announces the message to the bound multicast address.
 func (mgr *MulticastManager) AnnounceMessage(msg *protocol.Message) error {
 	var lastErr error
@@ -46,6 +98,10 @@ func (mgr *MulticastManager) AnnounceMessage(msg *protocol.Message) error {
 func (mgr *MulticastManager) StartWithInterface(ifi *net.Interface, ifaddr string) (*MulticastServer, error) {
 	server := NewMulticastServer()
 	server.Handler = mgr.Handler
+	server.SetParseMode(mgr.parseMode)
+	server.SetMulticastConfig(mgr.multicastConf)
+	server.setObservers(mgr.observers)
+	server.setMetrics(mgr.metrics)
 	if err := server.Start(ifi, ifaddr); err != nil {
 		return nil, err
 	}
@@ -60,18 +116,14 @@ func (mgr *MulticastManager) Start() error {
 		return err
 	}
 
-	ifis, err := GetAvailableInterfaces()
+	ifis, err := getSelectedInterfaceAddresses(mgr.addressFamily, mgr.filter)
 	if err != nil {
 		return err
 	}
 
 	for _, ifi := range ifis {
-		ifaddrs, err := GetInterfaceAddresses(ifi)
-		if err != nil {
-			continue
-		}
-		for _, ifaddr := range ifaddrs {
-			_, err := mgr.StartWithInterface(ifi, ifaddr)
+		for _, ifaddr := range multicastInterfaceAddresses(ifi.addrs) {
+			_, err := mgr.StartWithInterface(ifi.ifi, ifaddr)
 			if err != nil {
 				mgr.Stop()
 				return err
@@ -104,14 +156,81 @@ func (mgr *MulticastManager) IsRunning() bool {
 }
 
 // setUnicastManager sets appropriate unicast servers to all multicast servers to response the multicast messages.
+// The multicast servers which have no unicast server of the same address family never respond, and the last error is returned.
 func (mgr *MulticastManager) setUnicastManager(unicastMgr *UnicastManager) error {
+	var lastErr error
 	for _, multicastServer := range mgr.Servers {
-		unicastServer, err := unicastMgr.getAppropriateServerForInterface(multicastServer.Socket.interfac)
+		unicastServer, err := unicastMgr.getAppropriateServerForInterface(multicastServer.Socket.interfac, multicastServer.Socket.address)
 		if err != nil {
-			mgr.Stop()
-			return err
+			lastErr = err
 		}
 		multicastServer.SetUnicastServer(unicastServer)
 	}
-	return nil
+	return lastErr
+}
+
+// updateInterfaces stops the servers which are bound to the unavailable addresses, and starts the servers on the new addresses.
+// It returns the errors to start the servers for each address.
+func (mgr *MulticastManager) updateInterfaces(ifis []*interfaceAddresses) map[string]error {
+	servers := make([]*MulticastServer, 0)
+	for _, server := range mgr.Servers {
+		ifi := server.Socket.interfac
+		if ifi != nil && hasInterfaceAddress(ifis, ifi.Name, server.Socket.address) {
+			servers = append(servers, server)
+			continue
+		}
+		server.Stop()
+	}
+	mgr.Servers = servers
+
+	errs := map[string]error{}
+	for _, ifi := range ifis {
+		for _, ifaddr := range multicastInterfaceAddresses(ifi.addrs) {
+			if mgr.hasServerForInterface(ifi.ifi.Name, ifaddr) {
+				continue
+			}
+			if _, err := mgr.StartWithInterface(ifi.ifi, ifaddr); err != nil {
+				errs[ifaddr] = err
+			}
+		}
+	}
+	return errs
+}
+
+// hasServerForInterface returns true whether a server is bound to the specified address of the interface, otherwise false.
+// The IPv6 multicast group is joined once for each interface, so that any IPv6 server of the interface is regarded as bound.
+func (mgr *MulticastManager) hasServerForInterface(name string, ifaddr string) bool {
+	for _, server := range mgr.Servers {
+		ifi := server.Socket.interfac
+		if ifi == nil || ifi.Name != name {
+			continue
+		}
+		if server.Socket.address == ifaddr {
+			return true
+		}
+		if IsIPv6Address(ifaddr) && IsIPv6Address(server.Socket.address) {
+			return true
+		}
+	}
+	return false
+}
+
+// multicastInterfaceAddresses returns the interface addresses to bind the multicast servers.
+// The IPv6 multicast address is link-local scope, so only one IPv6 address is selected for each interface, preferring the link-local address.
+func multicastInterfaceAddresses(ifaddrs []string) []string {
+	maddrs := []string{}
+	var ipv6Addr string
+	for _, ifaddr := range ifaddrs {
+		if !IsIPv6Address(ifaddr) {
+			maddrs = append(maddrs, ifaddr)
+			continue
+		}
+		if len(ipv6Addr) == 0 || isLinkLocalAddress(ifaddr) && !isLinkLocalAddress(ipv6Addr) {
+			ipv6Addr = ifaddr
+		}
+	}
+	if 0 < len(ipv6Addr) {
+		maddrs = append(maddrs, ipv6Addr)
+	}
+	return maddrs
 }
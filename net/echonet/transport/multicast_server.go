@@ -6,6 +6,7 @@ package transport
 
 import (
 	"net"
+	"sync"
 
 	"github.com/cybergarage/go-logger/log"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
@@ -19,6 +20,7 @@ type MulticastServer struct {
 	Channel       chan any
 	Handler       MulticastHandler
 	UnicastServer *UnicastServer
+	unicastMutex  sync.RWMutex
 }
 
 // NewMulticastServer returns a new MulticastServer.
@@ -29,6 +31,7 @@ func NewMulticastServer() *MulticastServer {
 		Channel:         nil,
 		Handler:         nil,
 		UnicastServer:   nil,
+		unicastMutex:    sync.RWMutex{},
 	}
 	return server
 }
@@ -40,9 +43,18 @@ func (server *MulticastServer) SetHandler(l MulticastHandler) {
 
 // SetUnicastServer set a unicast server to response received messages.
 func (server *MulticastServer) SetUnicastServer(s *UnicastServer) {
+	server.unicastMutex.Lock()
+	defer server.unicastMutex.Unlock()
 	server.UnicastServer = s
 }
 
+// unicastServer returns the unicast server to response received messages.
+func (server *MulticastServer) unicastServer() *UnicastServer {
+	server.unicastMutex.RLock()
+	defer server.unicastMutex.RUnlock()
+	return server.UnicastServer
+}
+
 // Start starts this server.
 func (server *MulticastServer) Start(ifi *net.Interface, ifaddr string) error {
 	if err := server.MulticastSocket.Bind(ifi, ifaddr); err != nil {
@@ -62,18 +74,19 @@ func (server *MulticastServer) Stop() error {
 }
 
 func handleMulticastRequestMessage(server *MulticastServer, reqMsg *protocol.Message) {
-	server.MulticastSocket.outputReadLog(log.LevelTrace, logSocketTypeUDPMulticast, reqMsg.From.String(), reqMsg.String(), reqMsg.Size())
+	server.MulticastSocket.outputReadLog(log.LevelTrace, logSocketTypeUDPMulticast, reqMsg.From, reqMsg, reqMsg.Size())
 
 	if server.Handler == nil {
 		return
 	}
 
 	resMsg, err := server.Handler.ProtocolMessageReceived(reqMsg)
-	if server.UnicastServer == nil || err != nil || resMsg == nil {
+	unicastServer := server.unicastServer()
+	if unicastServer == nil || err != nil || resMsg == nil {
 		return
 	}
 
-	server.UnicastServer.UDPSocket.ResponseMessageForRequestMessage(reqMsg, resMsg)
+	unicastServer.UDPSocket.ResponseMessageForRequestMessage(reqMsg, resMsg)
 }
 
 func handleMulticastConnection(server *MulticastServer, cancel chan any) {
@@ -85,7 +98,11 @@ func handleMulticastConnection(server *MulticastServer, cancel chan any) {
 		default:
 			reqMsg, err := server.MulticastSocket.ReadMessage()
 			if err != nil {
-				return
+				// The invalid frame is already logged and counted, and the next frame is read unless the socket is closed.
+				if isSocketClosedError(err) {
+					return
+				}
+				continue
 			}
 			reqMsg.SetPacketType(protocol.MulticastPacket)
 
@@ -7,6 +7,7 @@ package transport
 import (
 	"fmt"
 	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,14 +17,14 @@ import (
 type testMulticastServer struct {
 	*MulticastServer
 
-	lastMessage *protocol.Message
+	lastMessage atomic.Pointer[protocol.Message]
 }
 
 // NewMessageManager returns a new message manager.
 func newTestMulticastServer() *testMulticastServer {
 	server := &testMulticastServer{
 		MulticastServer: NewMulticastServer(),
-		lastMessage:     nil,
+		lastMessage:     atomic.Pointer[protocol.Message]{},
 	}
 	server.SetHandler(server)
 	return server
@@ -33,7 +34,7 @@ func (server *testMulticastServer) ProtocolMessageReceived(msg *protocol.Message
 	if isTestMessage(msg) {
 		copyMsg, err := protocol.NewMessageWithMessage(msg)
 		if err == nil {
-			server.lastMessage = copyMsg
+			server.lastMessage.Store(copyMsg)
 		}
 	}
 
@@ -67,8 +68,16 @@ func testMulticastServerWithInterface(t *testing.T, ifi *net.Interface, ifaddr s
 	sock := NewUnicastUDPSocket()
 	toAddr := MulticastIPv4Address
 	if IsIPv6Address(ifaddr) {
-		toAddr = MulticastIPv6Address
+		toAddr = MulticastIPv6Address + "%" + ifi.Name
 	}
+
+	// The truncated frame is dropped, and the server must keep reading the next frames.
+
+	msgBytes := msg.Bytes()
+	if _, err := sock.SendBytes(toAddr, Port, msgBytes[:len(msgBytes)-1]); err != nil {
+		t.Error(err)
+	}
+
 	nSent, err := sock.SendMessage(toAddr, Port, msg)
 	if err != nil {
 		t.Error(err)
@@ -83,10 +92,10 @@ func testMulticastServerWithInterface(t *testing.T, ifi *net.Interface, ifaddr s
 
 	time.Sleep(time.Second)
 
-	if !msg.Equals(server.lastMessage) {
+	if !msg.Equals(server.lastMessage.Load()) {
 		ifi, _ := server.MulticastServer.Socket.Interface()
 		t.Errorf("%v", ifi)
-		t.Errorf("%s != %s", msg.String(), server.lastMessage.String())
+		t.Errorf("%s != %s", msg.String(), server.lastMessage.Load().String())
 	}
 
 	// Stop server
@@ -98,14 +107,14 @@ func testMulticastServerWithInterface(t *testing.T, ifi *net.Interface, ifaddr s
 }
 
 func TestMulticastServerWithInterface(t *testing.T) {
-	ifis, err := GetAvailableInterfaces()
+	ifis, err := GetAvailableInterfacesWithFamily(AddressFamilyDualStack)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
 	for _, ifi := range ifis {
-		ifaddrs, err := GetInterfaceAddresses(ifi)
+		ifaddrs, err := GetInterfaceAddressesWithFamily(ifi, AddressFamilyDualStack)
 		if err != nil {
 			t.Error(err)
 			continue
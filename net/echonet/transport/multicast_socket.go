@@ -31,11 +31,10 @@ func (sock *MulticastSocket) Bind(ifi *net.Interface, ifaddr string) error {
 		return err
 	}
 
+	port := sock.multicastConf.MulticastPort()
 	switch {
-	case IsIPv4Address(ifaddr):
-		err = sock.Listen(ifi, MulticastIPv4Address, Port)
-	case IsIPv6Address(ifaddr):
-		err = sock.Listen(ifi, MulticastIPv6Address, Port)
+	case IsIPv4Address(ifaddr), IsIPv6Address(ifaddr):
+		err = sock.Listen(ifi, sock.multicastConf.MulticastAddress(ifaddr), port)
 	default:
 		return errAvailableAddressNotFound
 	}
@@ -44,10 +43,11 @@ func (sock *MulticastSocket) Bind(ifi *net.Interface, ifaddr string) error {
 		return fmt.Errorf("%w (%s)", err, ifi.Name)
 	}
 
-	sock.SetBoundStatus(ifi, ifaddr, Port)
-	sock.Conn.SetReadBuffer(sock.ReadBufferSize())
+	sock.SetBoundStatus(ifi, ifaddr, port)
+	conn := sock.conn()
+	conn.SetReadBuffer(sock.ReadBufferSize())
 
-	rawConn, err := sock.Conn.SyscallConn()
+	rawConn, err := conn.SyscallConn()
 	if err != nil {
 		sock.Close()
 		return err
@@ -58,7 +58,7 @@ func (sock *MulticastSocket) Bind(ifi *net.Interface, ifaddr string) error {
 		return err
 	}
 
-	err = sock.SetMulticastLoop(rawConn, ifaddr, true)
+	err = sock.SetMulticastLoop(rawConn, ifaddr, sock.multicastConf.MulticastLoopbackEnabled())
 	if err != nil {
 		return err
 	}
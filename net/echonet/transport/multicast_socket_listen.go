@@ -14,15 +14,25 @@ import (
 
 // Listen listens the Ethonet multicast address with the specified interface.
 func (sock *MulticastSocket) Listen(ifi *net.Interface, ipaddr string, port int) error {
-	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(ipaddr, strconv.Itoa(port)))
+	network := "udp4"
+	if IsIPv6Address(ipaddr) {
+		network = "udp6"
+	}
+
+	addr, err := net.ResolveUDPAddr(network, net.JoinHostPort(ipaddr, strconv.Itoa(port)))
 	if err != nil {
 		return err
 	}
 
-	sock.Conn, err = net.ListenMulticastUDP("udp", ifi, addr)
+	conn, err := net.ListenMulticastUDP(network, ifi, addr)
 	if err != nil {
 		return fmt.Errorf("%w (%s %s %d)", err, ifi.Name, ipaddr, port)
 	}
+	sock.setConn(conn)
+	if network == "udp6" {
+		addr.Zone = ifi.Name
+	}
+	sock.multicastAddr = addr
 
 	return nil
 }
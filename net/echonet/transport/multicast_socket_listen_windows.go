@@ -13,12 +13,18 @@ import (
 
 // Listen listens the Ethonet multicast address with the specified interface.
 func (sock *MulticastSocket) Listen(ifi *net.Interface, ipaddr string, port int) error {
-	ipv4Addr := &net.UDPAddr{IP: net.ParseIP(ipaddr), Port: port}
-	conn, err := net.ListenUDP("udp4", ipv4Addr)
+	network := "udp4"
+	groupAddr := &net.UDPAddr{IP: net.ParseIP(ipaddr), Port: port}
+	if IsIPv6Address(ipaddr) {
+		network = "udp6"
+		groupAddr.Zone = ifi.Name
+	}
+	conn, err := net.ListenUDP(network, groupAddr)
 	if err != nil {
 		return fmt.Errorf("%w (%s %s %d)", err, ifi.Name, ipaddr, port)
 	}
-	sock.Conn = conn
+	sock.setConn(conn)
+	sock.multicastAddr = groupAddr
 
 	return nil
 }
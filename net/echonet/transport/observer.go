@@ -0,0 +1,134 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+// MessageDirection represents a direction of the observed message.
+type MessageDirection int
+
+const (
+	// MessageReceived represents a message received by the transport.
+	MessageReceived MessageDirection = iota
+	// MessageSent represents a message sent by the transport.
+	MessageSent
+)
+
+const (
+	// NetworkUDP represents the UDP network of the observed message.
+	NetworkUDP = "udp"
+	// NetworkTCP represents the TCP network of the observed message.
+	NetworkTCP = "tcp"
+)
+
+// String returns the string representation of the direction.
+func (dir MessageDirection) String() string {
+	switch dir {
+	case MessageReceived:
+		return "received"
+	case MessageSent:
+		return "sent"
+	}
+	return ""
+}
+
+// ObservedMessage represents a message frame which is sent or received by the transport.
+type ObservedMessage struct {
+	Time      time.Time
+	Direction MessageDirection
+	Network   string
+	From      *protocol.Address
+	To        *protocol.Address
+	// Bytes is the message frame, and is valid only while the observer is called.
+	Bytes []byte
+}
+
+// MessageObserver is an interface to observe the message frames which are sent and received by the transport.
+// The observer is called synchronously in the reading or writing goroutine of the sockets.
+type MessageObserver interface {
+	MessageObserved(msg *ObservedMessage)
+}
+
+// messageObservers represents a goroutine-safe observer list which is shared by the servers and sockets of a message manager.
+type messageObservers struct {
+	sync.RWMutex
+	observers []MessageObserver
+}
+
+// newMessageObservers returns a new empty observer list.
+func newMessageObservers() *messageObservers {
+	return &messageObservers{
+		RWMutex:   sync.RWMutex{},
+		observers: []MessageObserver{},
+	}
+}
+
+// Add adds the specified observer.
+func (obs *messageObservers) Add(o MessageObserver) {
+	obs.Lock()
+	defer obs.Unlock()
+	obs.observers = append(slices.Clone(obs.observers), o)
+}
+
+// Remove removes the specified observer.
+func (obs *messageObservers) Remove(o MessageObserver) {
+	obs.Lock()
+	defer obs.Unlock()
+	obs.observers = slices.DeleteFunc(slices.Clone(obs.observers), func(other MessageObserver) bool {
+		return other == o
+	})
+}
+
+// list returns the current observers. The returned list is never modified.
+func (obs *messageObservers) list() []MessageObserver {
+	if obs == nil {
+		return nil
+	}
+	obs.RLock()
+	defer obs.RUnlock()
+	return obs.observers
+}
+
+// isObserved returns true when any observer is added, otherwise false.
+func (obs *messageObservers) isObserved() bool {
+	return 0 < len(obs.list())
+}
+
+// notify calls the observers with the specified message frame when any observer is added.
+func (obs *messageObservers) notify(dir MessageDirection, network string, from net.Addr, to net.Addr, b []byte) {
+	observers := obs.list()
+	if len(observers) == 0 {
+		return
+	}
+	msg := &ObservedMessage{
+		Time:      time.Now(),
+		Direction: dir,
+		Network:   network,
+		From:      newObservedAddress(from),
+		To:        newObservedAddress(to),
+		Bytes:     b,
+	}
+	for _, o := range observers {
+		o.MessageObserved(msg)
+	}
+}
+
+// newObservedAddress returns a new address of the specified network address.
+func newObservedAddress(addr net.Addr) *protocol.Address {
+	switch addr := addr.(type) {
+	case *net.UDPAddr:
+		return &protocol.Address{IP: addr.IP, Port: addr.Port, Zone: addr.Zone}
+	case *net.TCPAddr:
+		return &protocol.Address{IP: addr.IP, Port: addr.Port, Zone: addr.Zone}
+	}
+	return protocol.NewAddress()
+}
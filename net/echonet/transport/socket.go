@@ -10,21 +10,31 @@ import (
 	"net"
 	"strconv"
 	"syscall"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 // A Socket represents a socket.
 type Socket struct {
-	interfac *net.Interface
-	port     int
-	address  string
+	interfac  *net.Interface
+	subnet    *net.IPNet
+	port      int
+	address   string
+	parseMode protocol.ParseMode
+	observers *messageObservers
+	metrics   *transportMetrics
 }
 
 // NewSocket returns a new UDPSocket.
 func NewSocket() *Socket {
 	sock := &Socket{
-		interfac: nil,
-		port:     0,
-		address:  "",
+		interfac:  nil,
+		subnet:    nil,
+		port:      0,
+		address:   "",
+		parseMode: protocol.ParseModeStrict,
+		observers: nil,
+		metrics:   nil,
 	}
 	sock.Close()
 	return sock
@@ -33,15 +43,45 @@ func NewSocket() *Socket {
 // Close initialize this socket.
 func (sock *Socket) Close() {
 	sock.interfac = nil
+	sock.subnet = nil
 	sock.address = ""
 	sock.port = 0
 }
 
+// SetParseMode sets the specified mode to parse the received messages.
+func (sock *Socket) SetParseMode(mode protocol.ParseMode) {
+	sock.parseMode = mode
+}
+
+// ParseMode returns the mode to parse the received messages.
+func (sock *Socket) ParseMode() protocol.ParseMode {
+	return sock.parseMode
+}
+
+// setObservers sets the specified observers to notify the sent and received messages.
+func (sock *Socket) setObservers(obs *messageObservers) {
+	sock.observers = obs
+}
+
+// setMetrics sets the specified metrics to count the sent and received messages.
+func (sock *Socket) setMetrics(m *transportMetrics) {
+	sock.metrics = m
+}
+
 // SetBoundStatus sets the bound interface, port, and address.
 func (sock *Socket) SetBoundStatus(i *net.Interface, addr string, port int) {
 	sock.interfac = i
+	sock.subnet = nil
 	sock.address = addr
 	sock.port = port
+	if i != nil {
+		sock.subnet, _ = GetInterfaceSubnet(i, addr)
+	}
+}
+
+// Subnet returns the subnet of the bound address, or nil when the subnet is unknown.
+func (sock *Socket) Subnet() *net.IPNet {
+	return sock.subnet
 }
 
 // IsBound returns true whether the socket is bound, otherwise false.
@@ -108,3 +148,20 @@ func (sock *Socket) SetMulticastLoop(rawConn syscall.RawConn, addr string, flag
 
 	return nil
 }
+
+// SetMulticastTTL sets a value to IP_MULTICAST_TTL or IPV6_MULTICAST_HOPS.
+func (sock *Socket) SetMulticastTTL(rawConn syscall.RawConn, addr string, ttl int) error {
+	var sockErr error
+	err := rawConn.Control(func(fd uintptr) {
+		if IsIPv6Address(addr) {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MULTICAST_HOPS, ttl)
+		} else {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MULTICAST_TTL, ttl)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
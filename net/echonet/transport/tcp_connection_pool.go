@@ -0,0 +1,191 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxTCPIdleConnectionsPerPeer is the maximum number of the idle connections which are kept for each peer.
+	maxTCPIdleConnectionsPerPeer = 4
+	// tcpConnectionAliveCheckTime is the time to check whether the idle connection is closed by the peer before reusing it.
+	tcpConnectionAliveCheckTime = (time.Microsecond * 100)
+)
+
+// tcpConnection represents a persistent TCP connection with the buffered reader to read the successive message frames.
+type tcpConnection struct {
+	conn     *net.TCPConn
+	reader   *bufio.Reader
+	lastUsed time.Time
+}
+
+// newTCPConnection returns a new persistent connection for the specified connection.
+func newTCPConnection(conn *net.TCPConn) *tcpConnection {
+	return &tcpConnection{
+		conn:     conn,
+		reader:   newBufferedReader(conn),
+		lastUsed: time.Now(),
+	}
+}
+
+// Close closes the connection and releases the buffered reader.
+func (c *tcpConnection) Close() error {
+	err := c.conn.Close()
+	if c.reader != nil {
+		releaseBufferedReader(c.reader)
+		c.reader = nil
+	}
+	return err
+}
+
+// isAlive returns true whether the idle connection is not closed by the peer, otherwise false.
+func (c *tcpConnection) isAlive() bool {
+	err := c.conn.SetReadDeadline(time.Now().Add(tcpConnectionAliveCheckTime))
+	if err != nil {
+		return false
+	}
+	defer c.conn.SetReadDeadline(time.Time{})
+	_, err = c.reader.Peek(1)
+	if err == nil {
+		return true
+	}
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// tcpConnectionPool represents the idle persistent TCP connections for each peer.
+type tcpConnectionPool struct {
+	sync.Mutex
+	idleTimeout time.Duration
+	conns       map[string][]*tcpConnection
+}
+
+// newTCPConnectionPool returns a new empty connection pool.
+func newTCPConnectionPool() *tcpConnectionPool {
+	pool := &tcpConnectionPool{
+		Mutex:       sync.Mutex{},
+		idleTimeout: DefaultTCPIdleTimeout,
+		conns:       map[string][]*tcpConnection{},
+	}
+	return pool
+}
+
+// SetIdleTimeout sets the specified timeout to close the idle connections. The connections are not pooled when the timeout is zero.
+func (pool *tcpConnectionPool) SetIdleTimeout(d time.Duration) {
+	pool.Lock()
+	defer pool.Unlock()
+	pool.idleTimeout = d
+}
+
+// IdleTimeout returns the timeout to close the idle connections.
+func (pool *tcpConnectionPool) IdleTimeout() time.Duration {
+	pool.Lock()
+	defer pool.Unlock()
+	return pool.idleTimeout
+}
+
+// Get returns the most recently used idle connection to the specified peer, or nil when the pool has no idle connection.
+func (pool *tcpConnectionPool) Get(peer string) *tcpConnection {
+	pool.Lock()
+	defer pool.Unlock()
+
+	pool.closeExpiredConnections(time.Now())
+
+	conns := pool.conns[peer]
+	if len(conns) == 0 {
+		return nil
+	}
+	conn := conns[len(conns)-1]
+	conns = conns[:len(conns)-1]
+	if len(conns) == 0 {
+		delete(pool.conns, peer)
+	} else {
+		pool.conns[peer] = conns
+	}
+	return conn
+}
+
+// Put returns the specified connection to the pool as an idle connection to the peer.
+// The connection is closed when the pool is disabled or has enough idle connections to the peer.
+func (pool *tcpConnectionPool) Put(peer string, conn *tcpConnection) {
+	pool.Lock()
+	defer pool.Unlock()
+
+	now := time.Now()
+	pool.closeExpiredConnections(now)
+
+	conns := pool.conns[peer]
+	if pool.idleTimeout <= 0 || maxTCPIdleConnectionsPerPeer <= len(conns) {
+		conn.Close()
+		return
+	}
+	conn.lastUsed = now
+	pool.conns[peer] = append(conns, conn)
+}
+
+// Len returns the number of the idle connections in the pool.
+func (pool *tcpConnectionPool) Len() int {
+	pool.Lock()
+	defer pool.Unlock()
+	n := 0
+	for _, conns := range pool.conns {
+		n += len(conns)
+	}
+	return n
+}
+
+// Close closes all idle connections in the pool.
+func (pool *tcpConnectionPool) Close() error {
+	pool.Lock()
+	defer pool.Unlock()
+
+	var errs []error
+	for peer, conns := range pool.conns {
+		for _, conn := range conns {
+			if err := conn.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		delete(pool.conns, peer)
+	}
+	return errors.Join(errs...)
+}
+
+// closeExpiredConnections closes the idle connections which are not used within the idle timeout.
+func (pool *tcpConnectionPool) closeExpiredConnections(now time.Time) {
+	for peer, conns := range pool.conns {
+		activeConns := conns[:0]
+		for _, conn := range conns {
+			if pool.idleTimeout <= 0 || pool.idleTimeout <= now.Sub(conn.lastUsed) {
+				conn.Close()
+				continue
+			}
+			activeConns = append(activeConns, conn)
+		}
+		if len(activeConns) == 0 {
+			delete(pool.conns, peer)
+			continue
+		}
+		pool.conns[peer] = activeConns
+	}
+}
+
+// isClosedConnectionError returns true whether the specified error means the connection is closed by the peer, otherwise false.
+func isClosedConnectionError(err error) bool {
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return true
+	}
+	return false
+}
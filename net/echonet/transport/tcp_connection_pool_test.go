@@ -0,0 +1,233 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+func newTestTCPConnectionPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	clientConn, err := net.DialTCP("tcp", nil, l.Addr().(*net.TCPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConn, err := l.AcceptTCP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientConn, serverConn
+}
+
+func TestTCPConnectionPool(t *testing.T) {
+	const peer = "127.0.0.1:3610"
+
+	t.Run("PutGet", func(t *testing.T) {
+		pool := newTCPConnectionPool()
+		defer pool.Close()
+		if pool.Get(peer) != nil {
+			t.Errorf("empty pool returns a connection")
+		}
+		clientConn, serverConn := newTestTCPConnectionPair(t)
+		defer serverConn.Close()
+		conn := newTCPConnection(clientConn)
+		pool.Put(peer, conn)
+		if pool.Len() != 1 {
+			t.Errorf("%d != %d", pool.Len(), 1)
+		}
+		if got := pool.Get(peer); got != conn {
+			t.Errorf("pooled connection is not returned")
+		}
+		if pool.Len() != 0 {
+			t.Errorf("%d != %d", pool.Len(), 0)
+		}
+		conn.Close()
+	})
+
+	t.Run("MaxIdleConnections", func(t *testing.T) {
+		pool := newTCPConnectionPool()
+		defer pool.Close()
+		for n := 0; n < (maxTCPIdleConnectionsPerPeer + 2); n++ {
+			clientConn, serverConn := newTestTCPConnectionPair(t)
+			defer serverConn.Close()
+			pool.Put(peer, newTCPConnection(clientConn))
+		}
+		if pool.Len() != maxTCPIdleConnectionsPerPeer {
+			t.Errorf("%d != %d", pool.Len(), maxTCPIdleConnectionsPerPeer)
+		}
+	})
+
+	t.Run("IdleTimeout", func(t *testing.T) {
+		pool := newTCPConnectionPool()
+		defer pool.Close()
+		pool.SetIdleTimeout(time.Millisecond * 10)
+		clientConn, serverConn := newTestTCPConnectionPair(t)
+		defer serverConn.Close()
+		pool.Put(peer, newTCPConnection(clientConn))
+		time.Sleep(time.Millisecond * 50)
+		if pool.Get(peer) != nil {
+			t.Errorf("expired connection is returned")
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		pool := newTCPConnectionPool()
+		defer pool.Close()
+		pool.SetIdleTimeout(0)
+		clientConn, serverConn := newTestTCPConnectionPair(t)
+		defer serverConn.Close()
+		pool.Put(peer, newTCPConnection(clientConn))
+		if pool.Len() != 0 {
+			t.Errorf("%d != %d", pool.Len(), 0)
+		}
+	})
+}
+
+func TestTCPConnectionIsAlive(t *testing.T) {
+	clientConn, serverConn := newTestTCPConnectionPair(t)
+	conn := newTCPConnection(clientConn)
+	defer conn.Close()
+	if !conn.isAlive() {
+		t.Errorf("open connection is not alive")
+	}
+	serverConn.Close()
+	time.Sleep(time.Millisecond * 10)
+	if conn.isAlive() {
+		t.Errorf("closed connection is alive")
+	}
+}
+
+type testTCPResponder struct{}
+
+func (res *testTCPResponder) ProtocolMessageReceived(msg *protocol.Message) (*protocol.Message, error) {
+	return protocol.NewResponseMessageWithMessage(msg), nil
+}
+
+func TestPersistentTCPConnection(t *testing.T) {
+	ifis, err := GetAvailableInterfaces()
+	if err != nil || len(ifis) == 0 {
+		t.Skip("available interface is not found")
+	}
+	ifaddrs, err := GetInterfaceAddresses(ifis[0])
+	if err != nil || len(ifaddrs) == 0 {
+		t.Skip("available address is not found")
+	}
+	ifaddr := ifaddrs[0]
+
+	server := NewUnicastServer()
+	server.SetTCPEnabled(true)
+	server.SetHandler(&testTCPResponder{})
+	err = server.Start(ifis[0], ifaddr, testUnicastTCPSocketPort)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer server.Stop()
+
+	client := NewUnicastServer()
+	client.SetTCPEnabled(true)
+	err = client.Start(ifis[0], ifaddr, testUnicastTCPSocketPort+1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer client.Stop()
+	sock := client.TCPSocket
+
+	for n := 1; n <= 5; n++ {
+		reqMsg, err := newTestMessage(uint(n))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resMsg, err := sock.PostMessage(ifaddr, testUnicastTCPSocketPort, reqMsg, DefaultConnectimeTimeOut)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if resMsg.TID() != reqMsg.TID() {
+			t.Errorf("%d != %d", resMsg.TID(), reqMsg.TID())
+		}
+		if sock.pool.Len() != 1 {
+			t.Errorf("%d != %d", sock.pool.Len(), 1)
+		}
+	}
+}
+
+func TestTCPSendMessageConnection(t *testing.T) {
+	ifis, err := GetAvailableInterfaces()
+	if err != nil || len(ifis) == 0 {
+		t.Skip("available interface is not found")
+	}
+	ifaddrs, err := GetInterfaceAddresses(ifis[0])
+	if err != nil || len(ifaddrs) == 0 {
+		t.Skip("available address is not found")
+	}
+	ifaddr := ifaddrs[0]
+
+	server := NewUnicastServer()
+	server.SetTCPEnabled(true)
+	server.SetHandler(&testTCPResponder{})
+	err = server.Start(ifis[0], ifaddr, testUnicastTCPSocketPort)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer server.Stop()
+
+	client := NewUnicastServer()
+	client.SetTCPEnabled(true)
+	err = client.Start(ifis[0], ifaddr, testUnicastTCPSocketPort+1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer client.Stop()
+	sock := client.TCPSocket
+
+	// The connection of the sent message is not kept, because the responses to the message are not read.
+
+	for n := 1; n <= 3; n++ {
+		msg, err := newTestMessage(uint(n))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = sock.SendMessage(ifaddr, testUnicastTCPSocketPort, msg, DefaultConnectimeTimeOut)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if sock.pool.Len() != 0 {
+			t.Errorf("%d != %d", sock.pool.Len(), 0)
+		}
+	}
+
+	reqMsg, err := newTestMessage(10)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	resMsg, err := sock.PostMessage(ifaddr, testUnicastTCPSocketPort, reqMsg, DefaultConnectimeTimeOut)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if resMsg.TID() != reqMsg.TID() {
+		t.Errorf("%d != %d", resMsg.TID(), reqMsg.TID())
+	}
+	if sock.pool.Len() != 1 {
+		t.Errorf("%d != %d", sock.pool.Len(), 1)
+	}
+}
@@ -8,9 +8,13 @@ package transport
 
 import (
 	"bufio"
-	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"net"
+	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/cybergarage/go-logger/log"
@@ -20,20 +24,50 @@ import (
 // A TCPSocket represents a socket for TCP.
 type TCPSocket struct {
 	*Socket
-	Listener *net.TCPListener
-	readBuf  []byte
+
+	Listener  *net.TCPListener
+	readBuf   []byte
+	keepAlive time.Duration
+	pool      *tcpConnectionPool
+	connMutex sync.Mutex
+	conns     map[*net.TCPConn]struct{}
 }
 
 // NewTCPSocket returns a new TCPSocket.
 func NewTCPSocket() *TCPSocket {
 	sock := &TCPSocket{
-		Socket:   NewSocket(),
-		Listener: nil,
-		readBuf:  make([]byte, MaxPacketSize),
+		Socket:    NewSocket(),
+		Listener:  nil,
+		readBuf:   make([]byte, MaxPacketSize),
+		keepAlive: DefaultTCPKeepAlive,
+		pool:      newTCPConnectionPool(),
+		connMutex: sync.Mutex{},
+		conns:     map[*net.TCPConn]struct{}{},
 	}
 	return sock
 }
 
+// SetIdleTimeout sets a timeout to close the idle persistent connections of the server and client.
+// The connections are closed after each message when the timeout is zero.
+func (sock *TCPSocket) SetIdleTimeout(d time.Duration) {
+	sock.pool.SetIdleTimeout(d)
+}
+
+// IdleTimeout returns the timeout to close the idle persistent connections.
+func (sock *TCPSocket) IdleTimeout() time.Duration {
+	return sock.pool.IdleTimeout()
+}
+
+// SetKeepAlive sets a keep-alive period of the connections. The keep-alive is disabled when the period is zero.
+func (sock *TCPSocket) SetKeepAlive(d time.Duration) {
+	sock.keepAlive = d
+}
+
+// KeepAlive returns the keep-alive period of the connections.
+func (sock *TCPSocket) KeepAlive() time.Duration {
+	return sock.keepAlive
+}
+
 // Bind binds to Echonet multicast address.
 func (sock *TCPSocket) Bind(ifi *net.Interface, ifaddr string, port int) error {
 	err := sock.Close()
@@ -55,7 +89,6 @@ func (sock *TCPSocket) Bind(ifi *net.Interface, ifaddr string, port int) error {
 	if err != nil {
 		return err
 	}
-
 	err = sock.SetReuseAddr(rawConn, true)
 	if err != nil {
 		return err
@@ -66,8 +99,11 @@ func (sock *TCPSocket) Bind(ifi *net.Interface, ifaddr string, port int) error {
 	return nil
 }
 
-// Close closes the current opened socket.
+// Close closes the current opened socket, the accepted connections and the idle persistent connections.
 func (sock *TCPSocket) Close() error {
+	sock.closeConnections()
+	sock.pool.Close()
+
 	if sock.Listener == nil {
 		return nil
 	}
@@ -83,69 +119,198 @@ func (sock *TCPSocket) Close() error {
 	return nil
 }
 
-func (sock *TCPSocket) outputReadLog(logLevel log.Level, msgFrom string, msg string, msgSize int) {
+// AcceptConnection waits for and returns the next connection to the listener.
+func (sock *TCPSocket) AcceptConnection() (*net.TCPConn, error) {
+	if sock.Listener == nil {
+		return nil, errSocketClosed
+	}
+	conn, err := sock.Listener.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	sock.setConnectionKeepAlive(conn)
+	sock.connMutex.Lock()
+	sock.conns[conn] = struct{}{}
+	sock.connMutex.Unlock()
+	return conn, nil
+}
+
+// CloseConnection closes the specified accepted connection.
+func (sock *TCPSocket) CloseConnection(conn *net.TCPConn) error {
+	sock.connMutex.Lock()
+	delete(sock.conns, conn)
+	sock.connMutex.Unlock()
+	return conn.Close()
+}
+
+// closeConnections closes all accepted connections.
+func (sock *TCPSocket) closeConnections() {
+	sock.connMutex.Lock()
+	defer sock.connMutex.Unlock()
+	for conn := range sock.conns {
+		conn.Close()
+		delete(sock.conns, conn)
+	}
+}
+
+// setConnectionKeepAlive sets the keep-alive period to the specified connection.
+func (sock *TCPSocket) setConnectionKeepAlive(conn *net.TCPConn) {
+	if sock.keepAlive <= 0 {
+		conn.SetKeepAlive(false)
+		return
+	}
+	conn.SetKeepAlive(true)
+	conn.SetKeepAlivePeriod(sock.keepAlive)
+}
+
+func (sock *TCPSocket) outputReadLog(logLevel log.Level, msgFrom fmt.Stringer, msg logFrame, msgSize int) {
+	if !isSocketLogEnabled(logLevel) {
+		return
+	}
 	msgTo, _ := sock.IPAddr()
-	outputSocketLog(logLevel, logSocketTypeTCPUnicast, logSocketDirectionRead, msgFrom, msgTo, msg, msgSize)
+	outputSocketLog(logLevel, logSocketTypeTCPUnicast, logSocketDirectionRead, msgFrom.String(), msgTo, msg, msgSize)
 }
 
-// ReadMessage reads a message from the current opened socket.
+// ReadMessage reads a message from the specified connection.
 func (sock *TCPSocket) ReadMessage(conn net.Conn) (*protocol.Message, error) {
+	reader := newBufferedReader(conn)
+	defer releaseBufferedReader(reader)
+	return sock.ReadStreamMessage(conn, reader)
+}
+
+// ReadStreamMessage reads the next message frame from the specified buffered reader of the connection.
+// The reader should be kept for the connection to read the successive message frames in the stream.
+func (sock *TCPSocket) ReadStreamMessage(conn net.Conn, reader *bufio.Reader) (*protocol.Message, error) {
 	remoteAddr := conn.RemoteAddr()
 
-	reader := bufio.NewReader(conn)
-	msg, err := protocol.NewMessageWithReader(reader)
+	msg := protocol.NewMessage()
+	msg.SetParseMode(sock.parseMode)
+	err := msg.ParseReader(reader)
 	if err != nil {
-		sock.outputReadLog(log.LevelError, remoteAddr.String(), "", 0)
+		// The closed and idle timed out streams are not errors of the persistent connections.
+		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil, err
+		}
+		if errors.Is(err, protocol.ErrInvalid) {
+			sock.metrics.countParseError(SocketTCP)
+		}
+		sock.outputReadLog(log.LevelError, remoteAddr, hexBytes(nil), 0)
 		log.Error(err)
 		return nil, err
 	}
 
 	err = msg.From.ParseString(remoteAddr.String())
 	if err != nil {
-		sock.outputReadLog(log.LevelError, remoteAddr.String(), msg.String(), msg.Size())
+		sock.outputReadLog(log.LevelError, remoteAddr, msg, msg.Size())
 		log.Error(err)
 		return nil, err
 	}
 
-	sock.outputReadLog(log.LevelTrace, remoteAddr.String(), msg.String(), msg.Size())
+	sock.outputReadLog(log.LevelTrace, remoteAddr, msg, msg.Size())
+	sock.metrics.countReceived(SocketTCP, msg.Size())
+
+	if sock.observers.isObserved() {
+		sock.observers.notify(MessageReceived, NetworkTCP, remoteAddr, conn.LocalAddr(), msg.Bytes())
+	}
 
 	return msg, nil
 }
 
-func (sock *TCPSocket) outputWriteLog(logLevel log.Level, msgFrom string, msgTo string, msg string, msgSize int) {
-	outputSocketLog(logLevel, logSocketTypeTCPUnicast, logSocketDirectionWrite, msgFrom, msgTo, msg, msgSize)
+func (sock *TCPSocket) outputWriteLog(logLevel log.Level, msgFrom string, msgTo string, b []byte, msgSize int) {
+	outputSocketLog(logLevel, logSocketTypeTCPUnicast, logSocketDirectionWrite, msgFrom, msgTo, hexBytes(b), msgSize)
 }
 
 // SendMessage sends a message to the destination address.
+// The message is sent over a new connection which is closed after the message, because the persistent connections are kept only for the posted requests.
+// The responses to the message, which are not read, could be paired with the wrong requests when the connection is reused.
 func (sock *TCPSocket) SendMessage(addr string, port int, msg *protocol.Message, timeout time.Duration) (int, error) {
-	conn, nWrote, err := sock.dialAndWriteBytes(addr, port, msg.Bytes(), timeout)
+	buf := newMessageBuffer(msg)
+	defer buf.Release()
+
+	conn, nWrote, err := sock.dialAndWriteBytes(addr, port, buf.Bytes(), timeout)
 	if conn != nil {
 		conn.Close()
 	}
 	return nWrote, err
 }
 
-// PostMessage sends a message to the destination address.
+// PostMessage sends a message to the destination address and reads the response message of the same TID.
+// The message is sent over a persistent connection to the destination, and the connection is kept for the next messages.
 func (sock *TCPSocket) PostMessage(addr string, port int, reqMsg *protocol.Message, timeout time.Duration) (*protocol.Message, error) {
-	conn, _, err := sock.dialAndWriteBytes(addr, port, reqMsg.Bytes(), timeout)
-	if err != nil {
-		return nil, err
+	buf := newMessageBuffer(reqMsg)
+	defer buf.Release()
+
+	// The Format 2 message has no length field, so that the message is sent over a new connection which is closed for writing after the message.
+
+	if reqMsg.IsFormat2() {
+		conn, _, err := sock.dialAndWriteBytes(addr, port, buf.Bytes(), timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		conn.CloseWrite()
+		err = conn.SetReadDeadline(time.Now().Add(timeout))
+		if err != nil {
+			return nil, err
+		}
+		return sock.ReadMessage(conn)
 	}
 
-	defer conn.Close()
+	peer := net.JoinHostPort(addr, strconv.Itoa(port))
+	for {
+		conn, reused, err := sock.getPeerConnection(addr, port, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		resMsg, err := sock.postBytesToConnection(conn, reqMsg.TID(), buf.Bytes(), timeout)
+		if err == nil {
+			sock.pool.Put(peer, conn)
+			return resMsg, nil
+		}
+		conn.Close()
+
+		// The idle connection might be closed by the peer, so that the message is posted again over a new connection.
+		if reused && isClosedConnectionError(err) {
+			continue
+		}
 
-	err = conn.SetReadDeadline(time.Now().Add(timeout))
+		return nil, err
+	}
+}
+
+// postBytesToConnection writes the specified request bytes to the connection, and reads the response message of the specified TID.
+// The other message frames in the stream, such as the late responses of the previous requests, are skipped.
+func (sock *TCPSocket) postBytesToConnection(conn *tcpConnection, tid uint, b []byte, timeout time.Duration) (*protocol.Message, error) {
+	err := conn.conn.SetWriteDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	_, err = sock.writeBytesToConnection(conn.conn, b)
 	if err != nil {
-		conn.Close()
 		return nil, err
 	}
 
-	return sock.ReadMessage(conn)
+	err = conn.conn.SetReadDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.conn.SetReadDeadline(time.Time{})
+
+	for {
+		resMsg, err := sock.ReadStreamMessage(conn.conn, conn.reader)
+		if err != nil {
+			return nil, err
+		}
+		if resMsg.TID() == tid {
+			return resMsg, nil
+		}
+	}
 }
 
 // ResponseMessageForRequestMessage sends a specified response message to the request node.
 func (sock *TCPSocket) ResponseMessageForRequestMessage(reqMsg *protocol.Message, resMsg *protocol.Message, timeout time.Duration) error {
-	dstAddr := reqMsg.From.IP.String()
+	dstAddr := reqMsg.SourceAddress()
 	dstPort := reqMsg.From.Port
 	_, err := sock.SendMessage(dstAddr, dstPort, resMsg, timeout)
 	return err
@@ -153,7 +318,18 @@ func (sock *TCPSocket) ResponseMessageForRequestMessage(reqMsg *protocol.Message
 
 // ResponseMessageToConnection sends a response message to the specified connection.
 func (sock *TCPSocket) ResponseMessageToConnection(conn *net.TCPConn, resMsg *protocol.Message) error {
-	_, err := sock.writeBytesToConnection(conn, resMsg.Bytes())
+	return sock.responseMessageToConnection(conn, resMsg, DefaultConnectimeTimeOut)
+}
+
+// responseMessageToConnection sends a response message to the specified connection, and gives up when the peer does not read it within the specified timeout.
+func (sock *TCPSocket) responseMessageToConnection(conn *net.TCPConn, resMsg *protocol.Message, timeout time.Duration) error {
+	buf := newMessageBuffer(resMsg)
+	defer buf.Release()
+	err := conn.SetWriteDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+	_, err = sock.writeBytesToConnection(conn, buf.Bytes())
 	return err
 }
 
@@ -165,34 +341,75 @@ func (sock *TCPSocket) writeBytesToConnection(conn *net.TCPConn, b []byte) (int,
 	var nWrote int
 	nWrote, err := conn.Write(b)
 	if err != nil {
-		sock.outputWriteLog(log.LevelError, localAddr.String(), toAddr.String(), hex.EncodeToString(b), 0)
+		sock.outputWriteLog(log.LevelError, localAddr.String(), toAddr.String(), b, 0)
 		log.Error(err)
 		return nWrote, err
 	}
 
-	sock.outputWriteLog(log.LevelTrace, localAddr.String(), toAddr.String(), hex.EncodeToString(b), nWrote)
+	sock.outputWriteLog(log.LevelTrace, localAddr.String(), toAddr.String(), b, nWrote)
+	sock.metrics.countSent(SocketTCP, nWrote)
+	sock.observers.notify(MessageSent, NetworkTCP, localAddr, toAddr, b)
 
 	return nWrote, nil
 }
 
-// dialAndWriteBytes sends the specified bytes to the specified destination.
-func (sock *TCPSocket) dialAndWriteBytes(addr string, port int, b []byte, timeout time.Duration) (*net.TCPConn, int, error) {
-	toAddr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+// getPeerConnection returns an idle persistent connection to the specified destination, or a new connection when the pool has no idle connection.
+func (sock *TCPSocket) getPeerConnection(addr string, port int, timeout time.Duration) (*tcpConnection, bool, error) {
+	peer := net.JoinHostPort(addr, strconv.Itoa(port))
+	for {
+		conn := sock.pool.Get(peer)
+		if conn == nil {
+			break
+		}
+		if conn.isAlive() {
+			return conn, true, nil
+		}
+		conn.Close()
+	}
+	conn, err := sock.dial(addr, port, timeout)
 	if err != nil {
-		sock.outputWriteLog(log.LevelError, "", toAddr.String(), hex.EncodeToString(b), 0)
-		log.Error(err)
-		return nil, 0, err
+		return nil, false, err
 	}
+	return newTCPConnection(conn), false, nil
+}
+
+// dial connects to the specified destination.
+func (sock *TCPSocket) dial(addr string, port int, timeout time.Duration) (*net.TCPConn, error) {
+	toAddr := net.JoinHostPort(addr, strconv.Itoa(port))
 
 	fromAddr, err := sock.IPAddr()
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	conn, err := net.DialTCP("tcp", nil, toAddr)
+	keepAlive := sock.keepAlive
+	if keepAlive <= 0 {
+		keepAlive = -1
+	}
+	dialer := net.Dialer{ // nolint:exhaustruct
+		Timeout:   timeout,
+		KeepAlive: keepAlive,
+	}
+	conn, err := dialer.Dial("tcp", toAddr)
 	if err != nil {
-		sock.outputWriteLog(log.LevelError, fromAddr, toAddr.String(), hex.EncodeToString(b), 0)
+		sock.outputWriteLog(log.LevelError, fromAddr, toAddr, nil, 0)
 		log.Error(err)
+		return nil, err
+	}
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("%w (%s)", errSocketClosed, toAddr)
+	}
+
+	return tcpConn, nil
+}
+
+// dialAndWriteBytes sends the specified bytes to the specified destination over a new connection.
+func (sock *TCPSocket) dialAndWriteBytes(addr string, port int, b []byte, timeout time.Duration) (*net.TCPConn, int, error) {
+	conn, err := sock.dial(addr, port, timeout)
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -205,7 +422,8 @@ func (sock *TCPSocket) dialAndWriteBytes(addr string, port int, b []byte, timeou
 	nWrote, err := sock.writeBytesToConnection(conn, b)
 	if err != nil {
 		conn.Close()
+		return nil, nWrote, err
 	}
 
-	return conn, nWrote, err
+	return conn, nWrote, nil
 }
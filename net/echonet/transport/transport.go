@@ -0,0 +1,48 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+// Transport is an interface to send and receive the messages of a node.
+// MessageManager implements the transport over the real sockets, and VirtualTransport implements it over an in-memory virtual network.
+type Transport interface {
+	// SetConfig sets all configuration flags.
+	SetConfig(newConfig *Config)
+	// Config returns all current configurations.
+	Config() *Config
+	// SetPort sets a listen port.
+	SetPort(port int)
+	// Port returns the listen port.
+	Port() int
+	// Addresses return the bound local addresses.
+	Addresses() []string
+	// InterfaceAddresses returns the bound local addresses for each interface name.
+	InterfaceAddresses() map[string][]string
+	// SetMessageHandler sets a handler to receive the messages.
+	SetMessageHandler(h protocol.MessageHandler)
+	// MessageHandler returns the handler to receive the messages.
+	MessageHandler() protocol.MessageHandler
+	// AddMessageObserver adds the specified observer to observe the sent and received message frames.
+	AddMessageObserver(o MessageObserver)
+	// RemoveMessageObserver removes the specified observer.
+	RemoveMessageObserver(o MessageObserver)
+	// Metrics returns a snapshot of the traffic counters.
+	Metrics() *Metrics
+	// SendMessage sends a unicast message to the destination address.
+	SendMessage(addr string, port int, msg *protocol.Message) (int, error)
+	// AnnounceMessage sends a multicast message.
+	AnnounceMessage(msg *protocol.Message) error
+	// PostMessage posts a unicast message to the destination address and gets the response message.
+	PostMessage(addr string, port int, msg *protocol.Message) (*protocol.Message, error)
+	// Start starts the transport.
+	Start() error
+	// Stop stops the transport.
+	Stop() error
+	// IsRunning returns true whether the transport is running, otherwise false.
+	IsRunning() bool
+}
@@ -5,9 +5,11 @@
 package transport
 
 import (
-	"encoding/hex"
+	"errors"
+	"fmt"
 	"net"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/cybergarage/go-logger/log"
@@ -19,8 +21,11 @@ type UDPSocket struct {
 	*Socket
 
 	Conn           *net.UDPConn
+	connMutex      sync.RWMutex
 	readBufferSize int
 	readBuffer     []byte
+	multicastAddr  *net.UDPAddr
+	multicastConf  *MulticastConfig
 }
 
 // NewUDPSocket returns a new UDPSocket.
@@ -28,8 +33,11 @@ func NewUDPSocket() *UDPSocket {
 	sock := &UDPSocket{
 		Socket:         NewSocket(),
 		Conn:           nil,
+		connMutex:      sync.RWMutex{},
 		readBufferSize: MaxPacketSize,
 		readBuffer:     make([]byte, 0),
+		multicastAddr:  nil,
+		multicastConf:  NewDefaultMulticastConfig(),
 	}
 	sock.SetReadBufferSize(MaxPacketSize)
 	return sock
@@ -46,26 +54,58 @@ func (sock *UDPSocket) ReadBufferSize() int {
 	return sock.readBufferSize
 }
 
+// SetMulticastConfig sets the specified multicast group and options to bind and announce.
+func (sock *UDPSocket) SetMulticastConfig(conf *MulticastConfig) {
+	sock.multicastConf.SetConfig(conf)
+}
+
+// MulticastConfig returns the multicast group and options to bind and announce.
+func (sock *UDPSocket) MulticastConfig() *MulticastConfig {
+	return sock.multicastConf
+}
+
+// setConn sets the specified connection as the current opened socket.
+func (sock *UDPSocket) setConn(conn *net.UDPConn) {
+	sock.connMutex.Lock()
+	defer sock.connMutex.Unlock()
+	sock.Conn = conn
+}
+
+// conn returns the current opened socket, or nil when the socket is closed.
+func (sock *UDPSocket) conn() *net.UDPConn {
+	sock.connMutex.RLock()
+	defer sock.connMutex.RUnlock()
+	return sock.Conn
+}
+
 // Close closes the current opened socket.
 func (sock *UDPSocket) Close() error {
+	sock.connMutex.Lock()
 	conn := sock.Conn
+	sock.Conn = nil
+	sock.connMutex.Unlock()
 	if conn == nil {
 		return nil
 	}
-	sock.Conn = nil
 
 	conn.SetDeadline(time.Now().Add(-time.Second))
 	return conn.Close()
 }
 
-func (sock *UDPSocket) outputReadLog(logLevel log.Level, logType string, msgFrom string, msg string, msgSize int) {
+func (sock *UDPSocket) outputReadLog(logLevel log.Level, logType string, msgFrom fmt.Stringer, msg logFrame, msgSize int) {
+	if !isSocketLogEnabled(logLevel) {
+		return
+	}
 	msgTo, _ := sock.IPAddr()
-	outputSocketLog(logLevel, logType, logSocketDirectionRead, msgFrom, msgTo, msg, msgSize)
+	outputSocketLog(logLevel, logType, logSocketDirectionRead, msgFrom.String(), msgTo, msg, msgSize)
 }
 
-func (sock *UDPSocket) outputWriteLog(logLevel log.Level, msgTo string, msg string, msgSize int) {
+func (sock *UDPSocket) outputWriteLog(logLevel log.Level, msgTo fmt.Stringer, b []byte, msgSize int) {
+	if !isSocketLogEnabled(logLevel) {
+		return
+	}
 	msgFrom, _ := sock.IPAddr()
-	outputSocketLog(logLevel, logSocketTypeUDPUnicast, logSocketDirectionWrite, msgFrom, msgTo, msg, msgSize)
+	outputSocketLog(logLevel, logSocketTypeUDPUnicast, logSocketDirectionWrite, msgFrom, msgTo.String(), hexBytes(b), msgSize)
 }
 
 // SendBytes sends the specified bytes.
@@ -77,13 +117,16 @@ func (sock *UDPSocket) SendBytes(addr string, port int, b []byte) (int, error) {
 
 	// Send from binding port
 
-	if sock.Conn != nil {
-		n, err := sock.Conn.WriteToUDP(b, toAddr)
-		sock.outputWriteLog(log.LevelTrace, toAddr.String(), hex.EncodeToString(b), n)
+	if conn := sock.conn(); conn != nil {
+		n, err := conn.WriteToUDP(b, toAddr)
+		sock.outputWriteLog(log.LevelTrace, toAddr, b, n)
 		if err != nil {
 			log.Error(err)
+			return n, err
 		}
-		return n, err
+		sock.metrics.countSent(udpSocketTypeOf(toAddr), n)
+		sock.observers.notify(MessageSent, NetworkUDP, conn.LocalAddr(), toAddr, b)
+		return n, nil
 	}
 
 	// Send from no binding port
@@ -95,9 +138,12 @@ func (sock *UDPSocket) SendBytes(addr string, port int, b []byte) (int, error) {
 	}
 
 	n, err := conn.Write(b)
-	sock.outputWriteLog(log.LevelTrace, toAddr.String(), hex.EncodeToString(b), n)
+	sock.outputWriteLog(log.LevelTrace, toAddr, b, n)
 	if err != nil {
 		log.Error(err)
+	} else {
+		sock.metrics.countSent(udpSocketTypeOf(toAddr), n)
+		sock.observers.notify(MessageSent, NetworkUDP, conn.LocalAddr(), toAddr, b)
 	}
 	conn.Close()
 
@@ -106,7 +152,9 @@ func (sock *UDPSocket) SendBytes(addr string, port int, b []byte) (int, error) {
 
 // SendMessage send a message to the destination address.
 func (sock *UDPSocket) SendMessage(addr string, port int, msg *protocol.Message) (int, error) {
-	return sock.SendBytes(addr, port, msg.Bytes())
+	buf := newMessageBuffer(msg)
+	defer buf.Release()
+	return sock.SendBytes(addr, port, buf.Bytes())
 }
 
 // AnnounceMessage announces the message to the bound multicast address.
@@ -115,34 +163,66 @@ func (sock *UDPSocket) AnnounceMessage(msg *protocol.Message) error {
 	if err != nil {
 		return err
 	}
-	maddr := MulticastIPv4Address
+	maddr := sock.multicastConf.MulticastAddress(ifaddr)
 	if IsIPv6Address(ifaddr) {
-		maddr = MulticastIPv6Address
+		// The IPv6 multicast address is link-local scope, so the message is announced to the bound interface.
+		if sock.interfac != nil {
+			maddr += "%" + sock.interfac.Name
+		}
 	}
-	_, err = sock.SendMessage(maddr, Port, msg)
+	_, err = sock.SendMessage(maddr, sock.multicastConf.MulticastPort(), msg)
 	return err
 }
 
+// localAddr returns the destination address of the received messages.
+func (sock *UDPSocket) localAddr() net.Addr {
+	if sock.multicastAddr != nil {
+		return sock.multicastAddr
+	}
+	conn := sock.conn()
+	if conn == nil {
+		return nil
+	}
+	return conn.LocalAddr()
+}
+
+// isSocketClosedError returns true when the specified read error means the socket is closed, and false when the next message can be read.
+func isSocketClosedError(err error) bool {
+	return errors.Is(err, errSocketClosed) || errors.Is(err, net.ErrClosed)
+}
+
 // ReadMessage reads a message from the current opened socket.
+// The read buffer is reused, but the received message is newly allocated and is not returned to any pool,
+// because the handlers may keep it after they return, such as the responses returned by PostMessage,
+// the requests of the deferred responses, and the messages passed to the listeners.
 func (sock *UDPSocket) ReadMessage() (*protocol.Message, error) {
-	if sock.Conn == nil {
+	conn := sock.conn()
+	if conn == nil {
 		return nil, errSocketClosed
 	}
 
-	n, from, err := sock.Conn.ReadFromUDP(sock.readBuffer)
+	n, from, err := conn.ReadFromUDP(sock.readBuffer)
 	if err != nil {
 		return nil, err
 	}
 
-	msg, err := protocol.NewMessageWithBytes(sock.readBuffer[:n])
+	socketType := udpSocketTypeOf(sock.localAddr())
+	sock.metrics.countReceived(socketType, n)
+	sock.observers.notify(MessageReceived, NetworkUDP, from, sock.localAddr(), sock.readBuffer[:n])
+
+	msg := protocol.NewMessage()
+	msg.SetParseMode(sock.parseMode)
+	err = msg.ParseBytes(sock.readBuffer[:n])
 	if err != nil {
-		sock.outputReadLog(log.LevelError, logSocketTypeUDPUnicast, (*from).String(), hex.EncodeToString(sock.readBuffer[:n]), n)
+		sock.metrics.countParseError(socketType)
+		sock.outputReadLog(log.LevelError, logSocketTypeUDPUnicast, from, hexBytes(sock.readBuffer[:n]), n)
 		log.Error(err)
 		return nil, err
 	}
 
 	msg.From.IP = from.IP
 	msg.From.Port = from.Port
+	msg.From.Zone = from.Zone
 	msg.Interface = sock.Socket.interfac
 
 	return msg, nil
@@ -0,0 +1,42 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"net"
+	"testing"
+)
+
+func BenchmarkUDPSocketReadMessage(b *testing.B) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		b.Skip(err)
+	}
+	sock := NewUDPSocket()
+	sock.setConn(conn)
+	defer sock.Close()
+
+	sender, err := net.DialUDP("udp", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer sender.Close()
+
+	msg, err := newTestMessage(1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	msgBytes := msg.Bytes()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := sender.Write(msgBytes); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := sock.ReadMessage(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
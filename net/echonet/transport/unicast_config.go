@@ -16,6 +16,8 @@ type UnicastConfig struct {
 	requestTimeout    time.Duration
 	bindRetryCount    uint
 	bindRetryWaitTime time.Duration
+	tcpIdleTimeout    time.Duration
+	tcpKeepAlive      time.Duration
 }
 
 // NewDefaultUnicastConfig returns a default configuration.
@@ -26,6 +28,8 @@ func NewDefaultUnicastConfig() *UnicastConfig {
 		requestTimeout:    DefaultRequestTimeout,
 		bindRetryCount:    DefaultBindRetryCount,
 		bindRetryWaitTime: 0,
+		tcpIdleTimeout:    DefaultTCPIdleTimeout,
+		tcpKeepAlive:      DefaultTCPKeepAlive,
 	}
 	return conf
 }
@@ -34,6 +38,8 @@ func NewDefaultUnicastConfig() *UnicastConfig {
 func (conf *UnicastConfig) SetConfig(newConfig *UnicastConfig) {
 	conf.tcpEnabled = newConfig.tcpEnabled
 	conf.connectionTimeout = newConfig.connectionTimeout
+	conf.tcpIdleTimeout = newConfig.tcpIdleTimeout
+	conf.tcpKeepAlive = newConfig.tcpKeepAlive
 }
 
 // SetTCPEnabled sets a flag for TCP functions.
@@ -56,6 +62,27 @@ func (conf *UnicastConfig) ConnectionTimeout() time.Duration {
 	return conf.connectionTimeout
 }
 
+// SetTCPIdleTimeout sets a timeout to close the idle persistent TCP connections.
+// The TCP connections are closed after each message when the timeout is zero.
+func (conf *UnicastConfig) SetTCPIdleTimeout(d time.Duration) {
+	conf.tcpIdleTimeout = d
+}
+
+// TCPIdleTimeout returns the timeout to close the idle persistent TCP connections.
+func (conf *UnicastConfig) TCPIdleTimeout() time.Duration {
+	return conf.tcpIdleTimeout
+}
+
+// SetTCPKeepAlive sets a keep-alive period of the TCP connections. The keep-alive is disabled when the period is zero.
+func (conf *UnicastConfig) SetTCPKeepAlive(d time.Duration) {
+	conf.tcpKeepAlive = d
+}
+
+// TCPKeepAlive returns the keep-alive period of the TCP connections.
+func (conf *UnicastConfig) TCPKeepAlive() time.Duration {
+	return conf.tcpKeepAlive
+}
+
 // SetRequestTimeout sets a request timeout.
 func (conf *Config) SetRequestTimeout(d time.Duration) {
 	conf.requestTimeout = d
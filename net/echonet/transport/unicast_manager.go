@@ -5,7 +5,9 @@
 package transport
 
 import (
+	"fmt"
 	"net"
+	"slices"
 	"time"
 
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
@@ -15,18 +17,22 @@ import (
 type UnicastManager struct {
 	*Config
 
-	port    int
-	Servers []*UnicastServer
-	Handler UnicastHandler
+	port      int
+	Servers   []*UnicastServer
+	Handler   UnicastHandler
+	observers *messageObservers
+	metrics   *transportMetrics
 }
 
 // NewUnicastManager returns a new UnicastManager.
 func NewUnicastManager() *UnicastManager {
 	mgr := &UnicastManager{
-		Config:  NewDefaultConfig(),
-		port:    UDPPort,
-		Servers: make([]*UnicastServer, 0),
-		Handler: nil,
+		Config:    NewDefaultConfig(),
+		port:      UDPPort,
+		Servers:   make([]*UnicastServer, 0),
+		Handler:   nil,
+		observers: nil,
+		metrics:   nil,
 	}
 	return mgr
 }
@@ -50,6 +56,10 @@ func (mgr *UnicastManager) SetHandler(l UnicastHandler) {
 func (mgr *UnicastManager) StartWithInterfaceAndPort(ifi *net.Interface, ifaddr string, port int) (*UnicastServer, error) {
 	server := NewUnicastServer()
 	server.SetConfig(mgr.Config.UnicastConfig)
+	server.SetMulticastConfig(mgr.Config.MulticastConfig)
+	server.SetParseMode(mgr.ParseMode())
+	server.setObservers(mgr.observers)
+	server.setMetrics(mgr.metrics)
 	server.Handler = mgr.Handler
 	if err := server.Start(ifi, ifaddr, port); err != nil {
 		return nil, err
@@ -64,7 +74,7 @@ func (mgr *UnicastManager) Start() error {
 		return err
 	}
 
-	ifis, err := GetAvailableInterfaces()
+	ifis, err := getSelectedInterfaceAddresses(mgr.AddressFamily(), mgr.InterfaceFilter())
 	if err != nil {
 		return err
 	}
@@ -85,12 +95,8 @@ func (mgr *UnicastManager) Start() error {
 
 		for n := uint(0); n <= bindRetryCount; n++ {
 			for _, ifi := range ifis {
-				ifaddrs, err := GetInterfaceAddresses(ifi)
-				if err != nil {
-					continue
-				}
-				for _, ifaddr := range ifaddrs {
-					_, lastErr = mgr.StartWithInterfaceAndPort(ifi, ifaddr, port)
+				for _, ifaddr := range ifi.addrs {
+					_, lastErr = mgr.StartWithInterfaceAndPort(ifi.ifi, ifaddr, port)
 					if lastErr != nil {
 						break
 					}
@@ -128,22 +134,102 @@ func (mgr *UnicastManager) Stop() error {
 	return lastErr
 }
 
-// Stop stops this server.
-func (mgr *UnicastManager) getAppropriateServerForInterface(ifi *net.Interface) (*UnicastServer, error) {
+// getAppropriateServerForInterface returns the unicast server which is bound to the specified address, or the interface with the same address family.
+func (mgr *UnicastManager) getAppropriateServerForInterface(ifi *net.Interface, ifaddr string) (*UnicastServer, error) {
 	if len(mgr.Servers) == 0 {
 		return nil, errUnicastServerNotRunning
 	}
 
+	family := AddressFamilyOf(ifaddr)
+	var ifiServer, familyServer *UnicastServer
 	for _, server := range mgr.Servers {
 		if server == nil {
 			continue
 		}
-		if server.UDPSocket.interfac == ifi {
+		if server.UDPSocket.address == ifaddr {
 			return server, nil
 		}
+		if AddressFamilyOf(server.UDPSocket.address) != family {
+			continue
+		}
+		if ifiServer == nil && ifi != nil && server.UDPSocket.interfac != nil && server.UDPSocket.interfac.Name == ifi.Name {
+			ifiServer = server
+		}
+		if familyServer == nil {
+			familyServer = server
+		}
 	}
 
-	return mgr.Servers[0], nil
+	if ifiServer != nil {
+		return ifiServer, nil
+	}
+	if familyServer != nil {
+		return familyServer, nil
+	}
+
+	return nil, fmt.Errorf("%w (%s)", errAddressFamilyNotBound, ifaddr)
+}
+
+// getServersForAddress returns the unicast servers which can send messages to the specified destination address in the order of preference.
+// The servers are bound to the same address family, and the servers bound to the interface of the IPv6 scoped addressing zone,
+// the specified interface which the request message arrived on, and the subnet of the destination address come first.
+func (mgr *UnicastManager) getServersForAddress(addr string, ifi *net.Interface) ([]*UnicastServer, error) {
+	if len(mgr.Servers) == 0 {
+		return nil, errUnicastServerNotRunning
+	}
+
+	family := AddressFamilyOf(addr)
+	host, zone := SplitAddressZone(addr)
+	ip := net.ParseIP(host)
+
+	serverRank := func(server *UnicastServer) int {
+		sock := server.UDPSocket
+		switch {
+		case 0 < len(zone) && sock.interfac != nil && sock.interfac.Name == zone:
+			return 0
+		case ifi != nil && sock.interfac != nil && sock.interfac.Name == ifi.Name:
+			return 1
+		case ip != nil && sock.subnet != nil && sock.subnet.Contains(ip):
+			return 2
+		}
+		return 3
+	}
+
+	servers := []*UnicastServer{}
+	for _, server := range mgr.Servers {
+		if server == nil {
+			continue
+		}
+		if AddressFamilyOf(server.UDPSocket.address) != family {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("%w (%s)", errAddressFamilyNotBound, addr)
+	}
+
+	slices.SortStableFunc(servers, func(a, b *UnicastServer) int {
+		return serverRank(a) - serverRank(b)
+	})
+
+	return servers, nil
+}
+
+// InterfaceAddresses returns the bound addresses for each interface name.
+func (mgr *UnicastManager) InterfaceAddresses() map[string][]string {
+	ifaddrs := map[string][]string{}
+	for _, server := range mgr.Servers {
+		if server == nil {
+			continue
+		}
+		name := ""
+		if ifi := server.UDPSocket.interfac; ifi != nil {
+			name = ifi.Name
+		}
+		ifaddrs[name] = append(ifaddrs[name], server.UDPSocket.address)
+	}
+	return ifaddrs
 }
 
 // IsRunning returns true whether the local servers are running, otherwise false.
@@ -152,9 +238,15 @@ func (mgr *UnicastManager) IsRunning() bool {
 }
 
 // SendMessage sends a message to the destination address.
+// The message is sent from the server bound to the interface of the message such as the response message, or the subnet of the destination address.
 func (mgr *UnicastManager) SendMessage(addr string, port int, msg *protocol.Message) (int, error) {
+	servers, err := mgr.getServersForAddress(addr, msg.Interface)
+	if err != nil {
+		return 0, err
+	}
+
 	var lastErr error
-	for _, server := range mgr.Servers {
+	for _, server := range servers {
 		n, err := server.SendMessage(addr, port, msg)
 		if err == nil {
 			return n, nil
@@ -168,14 +260,27 @@ func (mgr *UnicastManager) SendMessage(addr string, port int, msg *protocol.Mess
 }
 
 // AnnounceMessage sends a message to the multicast address.
+// The message is announced once for each address family, and on each interface for IPv6 because the IPv6 multicast address is link-local scope.
 func (mgr *UnicastManager) AnnounceMessage(msg *protocol.Message) error {
 	var lastErr error
+	announced := map[string]bool{}
 	for _, server := range mgr.Servers {
+		scope := AddressFamilyOf(server.UDPSocket.address).String()
+		if IsIPv6Address(server.UDPSocket.address) && server.UDPSocket.interfac != nil {
+			scope += "%" + server.UDPSocket.interfac.Name
+		}
+		if announced[scope] {
+			continue
+		}
 		err := server.AnnounceMessage(msg)
-		if err == nil {
-			return nil
+		if err != nil {
+			lastErr = err
+			continue
 		}
-		lastErr = err
+		announced[scope] = true
+	}
+	if 0 < len(announced) {
+		return nil
 	}
 	if lastErr != nil {
 		return lastErr
@@ -183,14 +288,30 @@ func (mgr *UnicastManager) AnnounceMessage(msg *protocol.Message) error {
 	return errUnicastServerNotRunning
 }
 
+// AnnounceMessageFrom sends a message to the multicast address from the server bound to the specified local address.
+func (mgr *UnicastManager) AnnounceMessageFrom(addr string, msg *protocol.Message) error {
+	for _, server := range mgr.Servers {
+		if server == nil || server.UDPSocket.address != addr {
+			continue
+		}
+		return server.AnnounceMessage(msg)
+	}
+	return fmt.Errorf("%w (%s)", errAddressNotBound, addr)
+}
+
 // PostMessage posts a message to the destination address and gets the response message.
 func (mgr *UnicastManager) PostMessage(addr string, port int, reqMsg *protocol.Message) (*protocol.Message, error) {
 	if !mgr.TCPEnabled() {
 		return nil, errTCPSocketDisabled
 	}
 
+	servers, err := mgr.getServersForAddress(addr, reqMsg.Interface)
+	if err != nil {
+		return nil, err
+	}
+
 	var lastErr error
-	for _, server := range mgr.Servers {
+	for _, server := range servers {
 		resMsg, err := server.TCPSocket.PostMessage(addr, port, reqMsg, mgr.ConnectionTimeout())
 		if err == nil {
 			return resMsg, nil
@@ -204,3 +325,42 @@ func (mgr *UnicastManager) PostMessage(addr string, port int, reqMsg *protocol.M
 
 	return nil, errUnicastServerNotRunning
 }
+
+// updateInterfaces stops the servers which are bound to the unavailable addresses, and starts the servers on the new addresses with the current port.
+// It returns the errors to start the servers for each address.
+func (mgr *UnicastManager) updateInterfaces(ifis []*interfaceAddresses) map[string]error {
+	servers := make([]*UnicastServer, 0)
+	for _, server := range mgr.Servers {
+		ifi := server.UDPSocket.interfac
+		if ifi != nil && hasInterfaceAddress(ifis, ifi.Name, server.UDPSocket.address) {
+			servers = append(servers, server)
+			continue
+		}
+		server.Stop()
+	}
+	mgr.Servers = servers
+
+	errs := map[string]error{}
+	for _, ifi := range ifis {
+		for _, ifaddr := range ifi.addrs {
+			if mgr.hasServerForInterface(ifi.ifi.Name, ifaddr) {
+				continue
+			}
+			if _, err := mgr.StartWithInterfaceAndPort(ifi.ifi, ifaddr, mgr.Port()); err != nil {
+				errs[ifaddr] = err
+			}
+		}
+	}
+	return errs
+}
+
+// hasServerForInterface returns true whether a server is bound to the specified address of the interface, otherwise false.
+func (mgr *UnicastManager) hasServerForInterface(name string, ifaddr string) bool {
+	for _, server := range mgr.Servers {
+		ifi := server.UDPSocket.interfac
+		if ifi != nil && ifi.Name == name && server.UDPSocket.address == ifaddr {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,9 @@
 package transport
 
 import (
+	"errors"
+	"net"
+	"reflect"
 	"testing"
 )
 
@@ -45,3 +48,102 @@ func TestUnicastManagerWithTCPConfig(t *testing.T) {
 	conf.SetTCPEnabled(true)
 	testUnicastManagerBinding(t, conf)
 }
+
+func newTestRoutingUnicastServer(ifname string, addr string, cidr string) *UnicastServer {
+	server := NewUnicastServer()
+	server.UDPSocket.interfac = &net.Interface{Name: ifname}
+	server.UDPSocket.address = addr
+	if _, subnet, err := net.ParseCIDR(cidr); err == nil {
+		server.UDPSocket.subnet = subnet
+	}
+	return server
+}
+
+func TestUnicastManagerRouting(t *testing.T) {
+	eth0 := newTestRoutingUnicastServer("eth0", "192.168.0.10", "192.168.0.0/24")
+	eth1 := newTestRoutingUnicastServer("eth1", "10.0.0.10", "10.0.0.0/8")
+	eth2 := newTestRoutingUnicastServer("eth2", "172.16.0.10", "172.16.0.0/16")
+	eth0v6 := newTestRoutingUnicastServer("eth0", "fe80::10", "fe80::/64")
+	eth1v6 := newTestRoutingUnicastServer("eth1", "fe80::20", "fe80::/64")
+
+	mgr := NewUnicastManager()
+	mgr.Servers = []*UnicastServer{eth0, eth1, eth2, eth0v6, eth1v6}
+
+	t.Run("Servers", func(t *testing.T) {
+		tests := []struct {
+			addr     string
+			ifi      *net.Interface
+			expected *UnicastServer
+		}{
+			{"192.168.0.20", nil, eth0},
+			{"10.1.2.3", nil, eth1},
+			{"172.16.1.2", nil, eth2},
+			{"172.16.1.2", &net.Interface{Name: "eth1"}, eth1},
+			{"8.8.8.8", nil, eth0},
+			{"fe80::30%eth1", nil, eth1v6},
+			{"fe80::30%eth1", &net.Interface{Name: "eth0"}, eth1v6},
+			{"fe80::30", &net.Interface{Name: "eth1"}, eth1v6},
+		}
+		for _, test := range tests {
+			servers, err := mgr.getServersForAddress(test.addr, test.ifi)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if servers[0] != test.expected {
+				t.Errorf("%s : %s != %s", test.addr, servers[0].UDPSocket.address, test.expected.UDPSocket.address)
+			}
+			for _, server := range servers {
+				if AddressFamilyOf(server.UDPSocket.address) != AddressFamilyOf(test.addr) {
+					t.Errorf("%s : %s", test.addr, server.UDPSocket.address)
+				}
+			}
+		}
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		tests := []struct {
+			ifi      *net.Interface
+			ifaddr   string
+			expected *UnicastServer
+		}{
+			{nil, "10.0.0.10", eth1},
+			{&net.Interface{Name: "eth2"}, "172.16.0.20", eth2},
+			{&net.Interface{Name: "eth1"}, "fe80::30", eth1v6},
+			{nil, "192.168.1.1", eth0},
+		}
+		for _, test := range tests {
+			server, err := mgr.getAppropriateServerForInterface(test.ifi, test.ifaddr)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if server != test.expected {
+				t.Errorf("%s : %s != %s", test.ifaddr, server.UDPSocket.address, test.expected.UDPSocket.address)
+			}
+		}
+	})
+
+	t.Run("AddressFamilyNotBound", func(t *testing.T) {
+		mgr := NewUnicastManager()
+		mgr.Servers = []*UnicastServer{eth0, eth1}
+		if _, err := mgr.getAppropriateServerForInterface(&net.Interface{Name: "eth0"}, "fe80::10"); !errors.Is(err, errAddressFamilyNotBound) {
+			t.Errorf("%v != %v", err, errAddressFamilyNotBound)
+		}
+		if _, err := mgr.getServersForAddress("fe80::30", nil); !errors.Is(err, errAddressFamilyNotBound) {
+			t.Errorf("%v != %v", err, errAddressFamilyNotBound)
+		}
+	})
+
+	t.Run("InterfaceAddresses", func(t *testing.T) {
+		ifaddrs := mgr.InterfaceAddresses()
+		expected := map[string][]string{
+			"eth0": {"192.168.0.10", "fe80::10"},
+			"eth1": {"10.0.0.10", "fe80::20"},
+			"eth2": {"172.16.0.10"},
+		}
+		if !reflect.DeepEqual(ifaddrs, expected) {
+			t.Errorf("%v != %v", ifaddrs, expected)
+		}
+	})
+}
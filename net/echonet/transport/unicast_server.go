@@ -6,6 +6,7 @@ package transport
 
 import (
 	"net"
+	"time"
 
 	"github.com/cybergarage/go-logger/log"
 	"github.com/cybergarage/uecho-go/net/echonet/protocol"
@@ -42,6 +43,29 @@ func (server *UnicastServer) SetHandler(l UnicastHandler) {
 	server.Handler = l
 }
 
+// SetParseMode sets the specified mode to parse the received messages.
+func (server *UnicastServer) SetParseMode(mode protocol.ParseMode) {
+	server.TCPSocket.SetParseMode(mode)
+	server.UDPSocket.SetParseMode(mode)
+}
+
+// SetMulticastConfig sets the specified multicast group and options to announce the messages.
+func (server *UnicastServer) SetMulticastConfig(conf *MulticastConfig) {
+	server.UDPSocket.SetMulticastConfig(conf)
+}
+
+// setObservers sets the specified observers to notify the sent and received messages.
+func (server *UnicastServer) setObservers(obs *messageObservers) {
+	server.TCPSocket.setObservers(obs)
+	server.UDPSocket.setObservers(obs)
+}
+
+// setMetrics sets the specified metrics to count the sent and received messages.
+func (server *UnicastServer) setMetrics(m *transportMetrics) {
+	server.TCPSocket.setMetrics(m)
+	server.UDPSocket.setMetrics(m)
+}
+
 // SendMessage send a message to the destination address.
 func (server *UnicastServer) SendMessage(addr string, port int, msg *protocol.Message) (int, error) {
 	if server.TCPEnabled() {
@@ -65,21 +89,29 @@ func (server *UnicastServer) Start(ifi *net.Interface, ifaddr string, port int)
 		server.TCPSocket.Close()
 		return err
 	}
-	server.UDPChannel = make(chan any)
-	go handleUnicastUDPConnection(server, server.UDPChannel)
 
 	if server.TCPEnabled() {
+		server.TCPSocket.SetIdleTimeout(server.TCPIdleTimeout())
+		server.TCPSocket.SetKeepAlive(server.TCPKeepAlive())
 		err := server.TCPSocket.Bind(ifi, ifaddr, port)
 		if err != nil {
+			server.UDPSocket.Close()
 			return err
 		}
-		server.TCPChannel = make(chan any)
-		go handleUnicastTCPListener(server, server.TCPChannel)
 	}
 
+	// Sets the bound status before reading messages because the received messages refer to the bound interface.
 	server.TCPSocket.SetBoundStatus(ifi, ifaddr, port)
 	server.UDPSocket.SetBoundStatus(ifi, ifaddr, port)
 
+	server.UDPChannel = make(chan any)
+	go handleUnicastUDPConnection(server, server.UDPChannel)
+
+	if server.TCPEnabled() {
+		server.TCPChannel = make(chan any)
+		go handleUnicastTCPListener(server, server.TCPChannel)
+	}
+
 	return nil
 }
 
@@ -105,7 +137,7 @@ func (server *UnicastServer) Stop() error {
 }
 
 func handleUnicastUDPRequestMessage(server *UnicastServer, reqMsg *protocol.Message) {
-	server.UDPSocket.outputReadLog(log.LevelTrace, logSocketTypeUDPUnicast, reqMsg.From.String(), reqMsg.String(), reqMsg.Size())
+	server.UDPSocket.outputReadLog(log.LevelTrace, logSocketTypeUDPUnicast, reqMsg.From, reqMsg, reqMsg.Size())
 
 	if server.Handler == nil {
 		return
@@ -127,7 +159,11 @@ func handleUnicastUDPConnection(server *UnicastServer, cancel chan any) {
 		default:
 			reqMsg, err := server.UDPSocket.ReadMessage()
 			if err != nil {
-				return
+				// The invalid frame is already logged and counted, and the next frame is read unless the socket is closed.
+				if isSocketClosedError(err) {
+					return
+				}
+				continue
 			}
 			reqMsg.SetPacketType(protocol.UDPUnicastPacket)
 
@@ -136,25 +172,43 @@ func handleUnicastUDPConnection(server *UnicastServer, cancel chan any) {
 	}
 }
 
+// handleUnicastTCPConnection reads the successive request frames from the persistent connection, and responses them in order.
+// The connection is closed when the peer closes it, or it is idle for the idle timeout. The connection is closed after the first request when the idle timeout is zero.
 func handleUnicastTCPConnection(server *UnicastServer, conn *net.TCPConn) {
-	defer conn.Close()
+	defer server.TCPSocket.CloseConnection(conn)
 
-	reqMsg, err := server.TCPSocket.ReadMessage(conn)
-	if err != nil {
-		return
-	}
-	reqMsg.SetPacketType(protocol.TCPUnicastPacket)
+	reader := newBufferedReader(conn)
+	defer releaseBufferedReader(reader)
 
-	if server.Handler == nil {
-		return
-	}
+	idleTimeout := server.TCPSocket.IdleTimeout()
 
-	resMsg, err := server.Handler.ProtocolMessageReceived(reqMsg)
-	if err != nil || resMsg == nil {
-		return
-	}
+	for {
+		if 0 < idleTimeout {
+			if err := conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+				return
+			}
+		}
 
-	server.TCPSocket.ResponseMessageToConnection(conn, resMsg)
+		reqMsg, err := server.TCPSocket.ReadStreamMessage(conn, reader)
+		if err != nil {
+			return
+		}
+		reqMsg.SetPacketType(protocol.TCPUnicastPacket)
+
+		if server.Handler != nil {
+			resMsg, err := server.Handler.ProtocolMessageReceived(reqMsg)
+			if err == nil && resMsg != nil {
+				if err := server.TCPSocket.responseMessageToConnection(conn, resMsg, server.ConnectionTimeout()); err != nil {
+					return
+				}
+			}
+		}
+
+		// The Format 2 message has no length field, so that the stream ends with the message.
+		if idleTimeout <= 0 || reqMsg.IsFormat2() {
+			return
+		}
+	}
 }
 
 func handleUnicastTCPListener(server *UnicastServer, cancel chan any) {
@@ -163,7 +217,7 @@ func handleUnicastTCPListener(server *UnicastServer, cancel chan any) {
 		case <-cancel:
 			return
 		default:
-			tcpConn, err := server.TCPSocket.Listener.AcceptTCP()
+			tcpConn, err := server.TCPSocket.AcceptConnection()
 			if err != nil {
 				return
 			}
@@ -5,9 +5,84 @@
 package transport
 
 import (
+	"net"
 	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
 )
 
 func TestNewUnicastServer(t *testing.T) {
 	NewUnicastServer()
 }
+
+func TestUnicastServerAfterInvalidMessage(t *testing.T) {
+	ifis, err := GetAvailableInterfaces()
+	if err != nil || len(ifis) == 0 {
+		t.Skip("available interface is not found")
+	}
+	ifaddrs, err := GetInterfaceAddresses(ifis[0])
+	if err != nil || len(ifaddrs) == 0 {
+		t.Skip("available address is not found")
+	}
+	ifaddr := ifaddrs[0]
+
+	server := NewUnicastServer()
+	server.SetHandler(&testTCPResponder{})
+	err = server.Start(ifis[0], ifaddr, testUnicastUDPSocketPort)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer server.Stop()
+
+	client, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP(ifaddr), Port: 0}) // nolint: exhaustruct
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer client.Close()
+
+	serverAddr := &net.UDPAddr{IP: net.ParseIP(ifaddr), Port: testUnicastUDPSocketPort} // nolint: exhaustruct
+
+	reqMsg, err := newTestMessage(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reqMsg.SetESV(protocol.ESVReadRequest)
+
+	// The strict parse mode rejects the frame with a trailing byte, and the server must keep reading the next frames.
+
+	invalidBytes := append(reqMsg.Bytes(), 0x00)
+	if _, err := client.WriteToUDP(invalidBytes, serverAddr); err != nil {
+		t.Error(err)
+		return
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if _, err := client.WriteToUDP(reqMsg.Bytes(), serverAddr); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := client.SetReadDeadline(time.Now().Add(time.Second * 3)); err != nil {
+		t.Error(err)
+		return
+	}
+	buf := make([]byte, MaxPacketSize)
+	n, _, err := client.ReadFromUDP(buf)
+	if err != nil {
+		t.Errorf("no response after the invalid message: %s", err)
+		return
+	}
+	resMsg, err := protocol.NewMessageWithBytes(buf[:n])
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if resMsg.TID() != reqMsg.TID() {
+		t.Errorf("%d != %d", resMsg.TID(), reqMsg.TID())
+	}
+}
@@ -38,12 +38,13 @@ func (sock *UnicastUDPSocket) Bind(ifi *net.Interface, ifaddr string, port int)
 		return err
 	}
 
-	sock.Conn, err = net.ListenUDP("udp", boundAddr)
+	conn, err := net.ListenUDP("udp", boundAddr)
 	if err != nil {
 		return err
 	}
+	sock.setConn(conn)
 
-	rawConn, err := sock.Conn.SyscallConn()
+	rawConn, err := conn.SyscallConn()
 	if err != nil {
 		sock.Close()
 		return err
@@ -55,6 +56,20 @@ func (sock *UnicastUDPSocket) Bind(ifi *net.Interface, ifaddr string, port int)
 		return err
 	}
 
+	// The multicast options are applied to the unicast socket which announces the multicast messages.
+
+	err = sock.SetMulticastTTL(rawConn, ifaddr, sock.multicastConf.MulticastTTL())
+	if err != nil {
+		sock.Close()
+		return err
+	}
+
+	err = sock.SetMulticastLoop(rawConn, ifaddr, sock.multicastConf.MulticastLoopbackEnabled())
+	if err != nil {
+		sock.Close()
+		return err
+	}
+
 	sock.SetBoundStatus(ifi, ifaddr, port)
 
 	return nil
@@ -62,7 +77,7 @@ func (sock *UnicastUDPSocket) Bind(ifi *net.Interface, ifaddr string, port int)
 
 // ResponseMessageForRequestMessage sends a specified response message to the request node.
 func (sock *UnicastUDPSocket) ResponseMessageForRequestMessage(reqMsg *protocol.Message, resMsg *protocol.Message) error {
-	dstAddr := reqMsg.From.IP.String()
+	dstAddr := reqMsg.SourceAddress()
 	dstPort := reqMsg.From.Port
 	_, err := sock.SendMessage(dstAddr, dstPort, resMsg)
 	return err
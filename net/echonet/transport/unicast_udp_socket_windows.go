@@ -38,10 +38,40 @@ func (sock *UnicastUDPSocket) Bind(ifi *net.Interface, ifaddr string, port int)
 		return err
 	}
 
-	sock.Conn, err = net.ListenUDP("udp", boundAddr)
+	conn, err := net.ListenUDP("udp", boundAddr)
 	if err != nil {
 		return err
 	}
+	sock.setConn(conn)
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		sock.Close()
+		return err
+	}
+	fdCh := make(chan uintptr, 1)
+	err = rawConn.Control(func(fd uintptr) {
+		fdCh <- fd
+	})
+	if err != nil {
+		sock.Close()
+		return err
+	}
+	fd := <-fdCh
+
+	// The multicast options are applied to the unicast socket which announces the multicast messages.
+
+	err = sock.SetMulticastTTL(fd, ifaddr, sock.multicastConf.MulticastTTL())
+	if err != nil {
+		sock.Close()
+		return err
+	}
+
+	err = sock.SetMulticastLoop(fd, ifaddr, sock.multicastConf.MulticastLoopbackEnabled())
+	if err != nil {
+		sock.Close()
+		return err
+	}
 
 	sock.SetBoundStatus(ifi, ifaddr, port)
 
@@ -50,7 +80,7 @@ func (sock *UnicastUDPSocket) Bind(ifi *net.Interface, ifaddr string, port int)
 
 // ResponseMessageForRequestMessage sends a specified response message to the request node.
 func (sock *UnicastUDPSocket) ResponseMessageForRequestMessage(reqMsg *protocol.Message, resMsg *protocol.Message) error {
-	dstAddr := reqMsg.From.IP.String()
+	dstAddr := reqMsg.SourceAddress()
 	dstPort := reqMsg.From.Port
 	_, err := sock.SendMessage(dstAddr, dstPort, resMsg)
 	return err
@@ -0,0 +1,116 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+const (
+	// VirtualNetworkAddress is the first address which is assigned to the transports of a virtual network.
+	VirtualNetworkAddress = "10.0.0.1"
+	// VirtualInterfaceName is the interface name of the transports of a virtual network.
+	VirtualInterfaceName = "vnet0"
+)
+
+// VirtualNetwork represents an in-memory virtual LAN which delivers the unicast and multicast messages between the virtual transports in the same process without any socket.
+type VirtualNetwork struct {
+	sync.RWMutex
+	nextAddr   uint32
+	transports []*VirtualTransport
+}
+
+// NewVirtualNetwork returns a new empty virtual network.
+func NewVirtualNetwork() *VirtualNetwork {
+	vnet := &VirtualNetwork{
+		RWMutex:    sync.RWMutex{},
+		nextAddr:   binary.BigEndian.Uint32(net.ParseIP(VirtualNetworkAddress).To4()),
+		transports: []*VirtualTransport{},
+	}
+	return vnet
+}
+
+// NewTransport returns a new transport on the virtual network with the next unused address.
+func (vnet *VirtualNetwork) NewTransport() *VirtualTransport {
+	vnet.Lock()
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, vnet.nextAddr)
+	vnet.nextAddr++
+	vnet.Unlock()
+	return newVirtualTransport(vnet, ip.String())
+}
+
+// NewTransportWithAddress returns a new transport on the virtual network with the specified address.
+func (vnet *VirtualNetwork) NewTransportWithAddress(addr string) *VirtualTransport {
+	return newVirtualTransport(vnet, addr)
+}
+
+// Transports returns the running transports on the virtual network.
+func (vnet *VirtualNetwork) Transports() []*VirtualTransport {
+	vnet.RLock()
+	defer vnet.RUnlock()
+	return slices.Clone(vnet.transports)
+}
+
+// join binds the specified transport to the address and the port on the virtual network.
+// The next port is bound when the port is already in use and the automatic port binding is enabled.
+func (vnet *VirtualNetwork) join(t *VirtualTransport, autoPortBinding bool) error {
+	vnet.Lock()
+	defer vnet.Unlock()
+
+	startPort := t.port
+	endPort := startPort
+	if autoPortBinding {
+		endPort = startPort + UDPPortRange
+	}
+
+	for port := startPort; port <= endPort; port++ {
+		if vnet.lookupTransport(t.addr, port) != nil {
+			continue
+		}
+		t.port = port
+		vnet.transports = append(vnet.transports, t)
+		return nil
+	}
+
+	return fmt.Errorf("%w (%s)", errVirtualAddressInUse, net.JoinHostPort(t.addr, strconv.Itoa(startPort)))
+}
+
+// leave unbinds the specified transport from the virtual network.
+func (vnet *VirtualNetwork) leave(t *VirtualTransport) {
+	vnet.Lock()
+	defer vnet.Unlock()
+	vnet.transports = slices.DeleteFunc(vnet.transports, func(other *VirtualTransport) bool {
+		return other == t
+	})
+}
+
+// lookup returns the running transport which is bound to the specified address and port.
+func (vnet *VirtualNetwork) lookup(addr string, port int) *VirtualTransport {
+	vnet.RLock()
+	defer vnet.RUnlock()
+	return vnet.lookupTransport(addr, port)
+}
+
+func (vnet *VirtualNetwork) lookupTransport(addr string, port int) *VirtualTransport {
+	ip := net.ParseIP(addr)
+	for _, t := range vnet.transports {
+		if t.port != port {
+			continue
+		}
+		if ip != nil && ip.Equal(net.ParseIP(t.addr)) {
+			return t
+		}
+		if t.addr == addr {
+			return t
+		}
+	}
+	return nil
+}
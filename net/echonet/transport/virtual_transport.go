@@ -0,0 +1,342 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/cybergarage/go-logger/log"
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+// VirtualTransport represents a transport on an in-memory virtual network.
+// The unicast messages are delivered to the transport which is bound to the destination address and port,
+// and the multicast messages are delivered to all running transports of the same multicast group and port including itself unless the loopback is disabled.
+type VirtualTransport struct {
+	sync.Mutex
+	network   *VirtualNetwork
+	conf      *Config
+	addr      string
+	port      int
+	handler   protocol.MessageHandler
+	observers *messageObservers
+	metrics   *transportMetrics
+	running   bool
+}
+
+// newVirtualTransport returns a new transport with the specified address on the virtual network.
+func newVirtualTransport(vnet *VirtualNetwork, addr string) *VirtualTransport {
+	t := &VirtualTransport{
+		Mutex:     sync.Mutex{},
+		network:   vnet,
+		conf:      NewDefaultConfig(),
+		addr:      addr,
+		port:      UDPPort,
+		handler:   nil,
+		observers: newMessageObservers(),
+		metrics:   newTransportMetrics(),
+		running:   false,
+	}
+	return t
+}
+
+// Network returns the virtual network of the transport.
+func (t *VirtualTransport) Network() *VirtualNetwork {
+	return t.network
+}
+
+// SetConfig sets all configuration flags.
+func (t *VirtualTransport) SetConfig(newConfig *Config) {
+	t.conf.SetConfig(newConfig)
+}
+
+// Config returns all current configurations.
+func (t *VirtualTransport) Config() *Config {
+	return t.conf
+}
+
+// SetPort sets a listen port.
+func (t *VirtualTransport) SetPort(port int) {
+	t.Lock()
+	defer t.Unlock()
+	t.port = port
+}
+
+// Port returns the listen port.
+func (t *VirtualTransport) Port() int {
+	t.Lock()
+	defer t.Unlock()
+	return t.port
+}
+
+// Address returns the virtual address of the transport.
+func (t *VirtualTransport) Address() string {
+	return t.addr
+}
+
+// Addresses return the bound virtual address.
+func (t *VirtualTransport) Addresses() []string {
+	if !t.IsRunning() {
+		return []string{}
+	}
+	return []string{t.addr}
+}
+
+// InterfaceAddresses returns the bound virtual address for the virtual interface name.
+func (t *VirtualTransport) InterfaceAddresses() map[string][]string {
+	if !t.IsRunning() {
+		return map[string][]string{}
+	}
+	return map[string][]string{VirtualInterfaceName: {t.addr}}
+}
+
+// SetMessageHandler sets a handler to receive the messages.
+func (t *VirtualTransport) SetMessageHandler(h protocol.MessageHandler) {
+	t.handler = h
+}
+
+// MessageHandler returns the handler to receive the messages.
+func (t *VirtualTransport) MessageHandler() protocol.MessageHandler {
+	return t.handler
+}
+
+// AddMessageObserver adds the specified observer to observe the message frames which are sent and received by the transport.
+func (t *VirtualTransport) AddMessageObserver(o MessageObserver) {
+	t.observers.Add(o)
+}
+
+// RemoveMessageObserver removes the specified observer.
+func (t *VirtualTransport) RemoveMessageObserver(o MessageObserver) {
+	t.observers.Remove(o)
+}
+
+// Metrics returns a snapshot of the traffic counters of the transport.
+func (t *VirtualTransport) Metrics() *Metrics {
+	return t.metrics.Snapshot()
+}
+
+// SendMessage sends a unicast message to the transport which is bound to the destination address and port.
+// The message is handled asynchronously by the destination as a UDP unicast message.
+func (t *VirtualTransport) SendMessage(addr string, port int, msg *protocol.Message) (int, error) {
+	dst, err := t.lookupDestination(addr, port)
+	if err != nil {
+		return 0, err
+	}
+
+	b := msg.Bytes()
+	t.outputWriteLog(logSocketTypeUDPUnicast, dst, b)
+	t.metrics.countSent(SocketUDP, len(b))
+	t.observers.notify(MessageSent, NetworkUDP, t.udpAddr(), dst.udpAddr(), b)
+
+	go dst.handleMessage(t.udpAddr(), b, protocol.UDPUnicastPacket)
+
+	return len(b), nil
+}
+
+// AnnounceMessage sends a multicast message to all running transports of the same multicast group and port on the virtual network.
+func (t *VirtualTransport) AnnounceMessage(msg *protocol.Message) error {
+	if !t.IsRunning() {
+		return errUnicastServerNotRunning
+	}
+
+	b := msg.Bytes()
+	to := t.multicastAddr()
+	outputSocketLog(log.LevelTrace, logSocketTypeUDPMulticast, logSocketDirectionWrite, t.String(), to.String(), hexBytes(b), len(b))
+	t.metrics.countSent(SocketMulticast, len(b))
+	t.observers.notify(MessageSent, NetworkUDP, t.udpAddr(), to, b)
+
+	// The multicast messages are delivered only to the transports which join the same group and port.
+
+	for _, dst := range t.network.Transports() {
+		if dst == t && !t.conf.MulticastLoopbackEnabled() {
+			continue
+		}
+		if !dst.multicastAddr().IP.Equal(to.IP) || dst.multicastAddr().Port != to.Port {
+			continue
+		}
+		go dst.handleMessage(t.udpAddr(), b, protocol.MulticastPacket)
+	}
+
+	return nil
+}
+
+// PostMessage posts a unicast message to the transport which is bound to the destination address and port, and gets the response message.
+// The message is handled synchronously by the destination as a TCP unicast message.
+func (t *VirtualTransport) PostMessage(addr string, port int, msg *protocol.Message) (*protocol.Message, error) {
+	if !t.conf.TCPEnabled() {
+		return nil, errTCPSocketDisabled
+	}
+
+	dst, err := t.lookupDestination(addr, port)
+	if err != nil {
+		return nil, err
+	}
+
+	b := msg.Bytes()
+	t.outputWriteLog(logSocketTypeTCPUnicast, dst, b)
+	t.metrics.countSent(SocketTCP, len(b))
+	t.observers.notify(MessageSent, NetworkTCP, t.tcpAddr(), dst.tcpAddr(), b)
+
+	resMsg, err := dst.receiveMessage(t.tcpAddr(), b, protocol.TCPUnicastPacket)
+	if err != nil {
+		return nil, err
+	}
+	if resMsg == nil {
+		return nil, fmt.Errorf("%w (%s)", errResponseMessageNotFound, dst.String())
+	}
+
+	resBytes := resMsg.Bytes()
+	dst.metrics.countSent(SocketTCP, len(resBytes))
+	dst.observers.notify(MessageSent, NetworkTCP, dst.tcpAddr(), t.tcpAddr(), resBytes)
+	t.metrics.countReceived(SocketTCP, len(resBytes))
+
+	return t.parseMessage(dst.tcpAddr(), resBytes, protocol.TCPUnicastPacket)
+}
+
+// Start binds the transport to the address and port on the virtual network.
+func (t *VirtualTransport) Start() error {
+	if err := t.Stop(); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if err := t.conf.ValidateMulticastConfig(); err != nil {
+		return err
+	}
+
+	if err := t.network.join(t, t.conf.AutoPortBindingEnabled()); err != nil {
+		return err
+	}
+	t.running = true
+
+	return nil
+}
+
+// Stop unbinds the transport from the virtual network.
+func (t *VirtualTransport) Stop() error {
+	t.Lock()
+	defer t.Unlock()
+
+	t.network.leave(t)
+	t.running = false
+
+	return nil
+}
+
+// IsRunning returns true whether the transport is bound to the virtual network, otherwise false.
+func (t *VirtualTransport) IsRunning() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.running
+}
+
+// String returns the bound address and port of the transport.
+func (t *VirtualTransport) String() string {
+	return net.JoinHostPort(t.addr, strconv.Itoa(t.Port()))
+}
+
+// lookupDestination returns the running transport which is bound to the specified address and port.
+func (t *VirtualTransport) lookupDestination(addr string, port int) (*VirtualTransport, error) {
+	if !t.IsRunning() {
+		return nil, errUnicastServerNotRunning
+	}
+	dst := t.network.lookup(addr, port)
+	if dst == nil {
+		return nil, fmt.Errorf("%w (%s)", errVirtualHostUnreachable, net.JoinHostPort(addr, strconv.Itoa(port)))
+	}
+	return dst, nil
+}
+
+// handleMessage handles the received message frame, and sends the response message to the source as a UDP unicast message.
+func (t *VirtualTransport) handleMessage(from *net.UDPAddr, b []byte, pktType int) {
+	resMsg, err := t.receiveMessage(from, b, pktType)
+	if err != nil || resMsg == nil {
+		return
+	}
+	t.SendMessage(from.IP.String(), from.Port, resMsg)
+}
+
+// receiveMessage parses the received message frame, and returns the response message of the handler.
+func (t *VirtualTransport) receiveMessage(from net.Addr, b []byte, pktType int) (*protocol.Message, error) {
+	if !t.IsRunning() {
+		return nil, errUnicastServerNotRunning
+	}
+
+	network := NetworkUDP
+	to := net.Addr(t.udpAddr())
+	if pktType == protocol.TCPUnicastPacket {
+		network = NetworkTCP
+		to = t.tcpAddr()
+	}
+	t.metrics.countReceived(virtualSocketTypeOf(pktType), len(b))
+	t.observers.notify(MessageReceived, network, from, to, b)
+
+	msg, err := t.parseMessage(from, b, pktType)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.handler == nil {
+		return nil, nil
+	}
+
+	return t.handler.ProtocolMessageReceived(msg)
+}
+
+// parseMessage parses the message frame which is received from the specified address.
+func (t *VirtualTransport) parseMessage(from net.Addr, b []byte, pktType int) (*protocol.Message, error) {
+	msg := protocol.NewMessage()
+	msg.SetParseMode(t.conf.ParseMode())
+	if err := msg.ParseBytes(bytes.Clone(b)); err != nil {
+		t.metrics.countParseError(virtualSocketTypeOf(pktType))
+		log.Error(err)
+		return nil, err
+	}
+
+	switch addr := from.(type) {
+	case *net.UDPAddr:
+		msg.From.IP = addr.IP
+		msg.From.Port = addr.Port
+	case *net.TCPAddr:
+		msg.From.IP = addr.IP
+		msg.From.Port = addr.Port
+	}
+	msg.SetPacketType(pktType)
+
+	return msg, nil
+}
+
+// virtualSocketTypeOf returns the socket type which the specified packet type is counted for.
+func virtualSocketTypeOf(pktType int) SocketType {
+	switch pktType {
+	case protocol.MulticastPacket:
+		return SocketMulticast
+	case protocol.TCPUnicastPacket:
+		return SocketTCP
+	}
+	return SocketUDP
+}
+
+func (t *VirtualTransport) outputWriteLog(socketType string, dst *VirtualTransport, b []byte) {
+	outputSocketLog(log.LevelTrace, socketType, logSocketDirectionWrite, t.String(), dst.String(), hexBytes(b), len(b))
+}
+
+func (t *VirtualTransport) multicastAddr() *net.UDPAddr {
+	return &net.UDPAddr{IP: net.ParseIP(t.conf.MulticastIPv4Address()), Port: t.conf.MulticastPort(), Zone: ""}
+}
+
+func (t *VirtualTransport) udpAddr() *net.UDPAddr {
+	return &net.UDPAddr{IP: net.ParseIP(t.addr), Port: t.Port(), Zone: ""}
+}
+
+func (t *VirtualTransport) tcpAddr() *net.TCPAddr {
+	return &net.TCPAddr{IP: net.ParseIP(t.addr), Port: t.Port(), Zone: ""}
+}
@@ -0,0 +1,301 @@
+// Copyright 2018 The uecho-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transport
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/uecho-go/net/echonet/protocol"
+)
+
+type testVirtualHandler struct {
+	sync.Mutex
+	msgs []*protocol.Message
+}
+
+func (h *testVirtualHandler) ProtocolMessageReceived(msg *protocol.Message) (*protocol.Message, error) {
+	h.Lock()
+	defer h.Unlock()
+	h.msgs = append(h.msgs, msg)
+	if !isTestMessage(msg) {
+		return nil, nil
+	}
+	return protocol.NewResponseMessageWithMessage(msg), nil
+}
+
+func (h *testVirtualHandler) messages() []*protocol.Message {
+	h.Lock()
+	defer h.Unlock()
+	return append([]*protocol.Message{}, h.msgs...)
+}
+
+func (h *testVirtualHandler) waitMessages(n int) []*protocol.Message {
+	for range 100 {
+		if msgs := h.messages(); n <= len(msgs) {
+			return msgs
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	return h.messages()
+}
+
+func (h *testVirtualHandler) waitMessage(f func(*protocol.Message) bool) bool {
+	for range 100 {
+		for _, msg := range h.messages() {
+			if f(msg) {
+				return true
+			}
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	return false
+}
+
+type testMessageObserver struct {
+	sync.Mutex
+	msgs []ObservedMessage
+}
+
+func (obs *testMessageObserver) MessageObserved(msg *ObservedMessage) {
+	obs.Lock()
+	defer obs.Unlock()
+	obs.msgs = append(obs.msgs, *msg)
+}
+
+func (obs *testMessageObserver) messages() []ObservedMessage {
+	obs.Lock()
+	defer obs.Unlock()
+	return append([]ObservedMessage{}, obs.msgs...)
+}
+
+func newTestVirtualTransports(t *testing.T, vnet *VirtualNetwork, n int) ([]*VirtualTransport, []*testVirtualHandler) {
+	t.Helper()
+	transports := make([]*VirtualTransport, n)
+	handlers := make([]*testVirtualHandler, n)
+	for i := range n {
+		handlers[i] = &testVirtualHandler{} // nolint:exhaustruct
+		transports[i] = vnet.NewTransport()
+		transports[i].SetMessageHandler(handlers[i])
+		if err := transports[i].Start(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { transports[i].Stop() })
+	}
+	return transports, handlers
+}
+
+func TestVirtualNetworkAddresses(t *testing.T) {
+	vnet := NewVirtualNetwork()
+	transports, _ := newTestVirtualTransports(t, vnet, 3)
+
+	expectedAddrs := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	for n, tp := range transports {
+		addrs := tp.Addresses()
+		if len(addrs) != 1 || addrs[0] != expectedAddrs[n] {
+			t.Errorf("%v != %s", addrs, expectedAddrs[n])
+		}
+	}
+
+	if len(vnet.Transports()) != len(transports) {
+		t.Errorf("%d != %d", len(vnet.Transports()), len(transports))
+	}
+
+	transports[0].Stop()
+	if len(transports[0].Addresses()) != 0 {
+		t.Errorf("stopped transport has addresses")
+	}
+	if len(vnet.Transports()) != (len(transports) - 1) {
+		t.Errorf("%d != %d", len(vnet.Transports()), len(transports)-1)
+	}
+}
+
+func TestVirtualNetworkPortBinding(t *testing.T) {
+	vnet := NewVirtualNetwork()
+
+	tp1 := vnet.NewTransportWithAddress("10.1.0.1")
+	if err := tp1.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer tp1.Stop()
+
+	tp2 := vnet.NewTransportWithAddress("10.1.0.1")
+	if err := tp2.Start(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("same address and port is bound (%v)", err)
+	}
+
+	tp2.Config().SetAutoPortBindingEnabled(true)
+	if err := tp2.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer tp2.Stop()
+	if tp2.Port() != (tp1.Port() + 1) {
+		t.Errorf("%d != %d", tp2.Port(), tp1.Port()+1)
+	}
+}
+
+func TestVirtualTransportMessaging(t *testing.T) {
+	vnet := NewVirtualNetwork()
+	transports, handlers := newTestVirtualTransports(t, vnet, 3)
+	src := transports[0]
+	dst := transports[1]
+
+	t.Run("Unicast", func(t *testing.T) {
+		msg, err := newTestMessage(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = src.SendMessage(dst.Address(), dst.Port(), msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs := handlers[1].waitMessages(1)
+		if len(msgs) != 1 {
+			t.Fatalf("%d != %d", len(msgs), 1)
+		}
+		if msgs[0].SourceAddress() != src.Address() || msgs[0].SourcePort() != src.Port() {
+			t.Errorf("%s:%d != %s", msgs[0].SourceAddress(), msgs[0].SourcePort(), src.String())
+		}
+		if !msgs[0].IsPacketType(protocol.UDPUnicastPacket) {
+			t.Errorf("%d != %d", msgs[0].PacketType(), protocol.UDPUnicastPacket)
+		}
+		// The response message is sent back to the source.
+		isReceived := handlers[0].waitMessage(func(resMsg *protocol.Message) bool {
+			return resMsg.TID() == msg.TID()
+		})
+		if !isReceived {
+			t.Errorf("response message is not received")
+		}
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		msg, err := newTestMessage(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = src.SendMessage("10.9.9.9", Port, msg)
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("unreachable message is sent (%v)", err)
+		}
+	})
+
+	t.Run("Multicast", func(t *testing.T) {
+		msg, err := newTestMessage(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = src.AnnounceMessage(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The multicast messages are received by all transports including the sender.
+		for n, h := range handlers {
+			isReceived := h.waitMessage(func(msg *protocol.Message) bool {
+				return msg.TID() == 3 && msg.IsPacketType(protocol.MulticastPacket)
+			})
+			if !isReceived {
+				t.Errorf("[%d] multicast message is not received", n)
+			}
+		}
+	})
+
+	t.Run("Post", func(t *testing.T) {
+		msg, err := newTestMessage(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = src.PostMessage(dst.Address(), dst.Port(), msg)
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("message is posted without TCP (%v)", err)
+		}
+		src.Config().SetTCPEnabled(true)
+		defer src.Config().SetTCPEnabled(false)
+		resMsg, err := src.PostMessage(dst.Address(), dst.Port(), msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resMsg.TID() != msg.TID() {
+			t.Errorf("%d != %d", resMsg.TID(), msg.TID())
+		}
+		if !resMsg.IsPacketType(protocol.TCPUnicastPacket) {
+			t.Errorf("%d != %d", resMsg.PacketType(), protocol.TCPUnicastPacket)
+		}
+	})
+}
+
+func TestVirtualTransportObserver(t *testing.T) {
+	vnet := NewVirtualNetwork()
+	transports, handlers := newTestVirtualTransports(t, vnet, 2)
+
+	obs := &testMessageObserver{} // nolint:exhaustruct
+	transports[1].AddMessageObserver(obs)
+	defer transports[1].RemoveMessageObserver(obs)
+
+	msg, err := newTestMessage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = transports[0].SendMessage(transports[1].Address(), transports[1].Port(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlers[1].waitMessages(1)
+	handlers[0].waitMessages(1)
+
+	msgs := obs.messages()
+	if len(msgs) != 2 {
+		t.Fatalf("%d != %d", len(msgs), 2)
+	}
+	if msgs[0].Direction != MessageReceived || msgs[1].Direction != MessageSent {
+		t.Errorf("%s, %s", msgs[0].Direction, msgs[1].Direction)
+	}
+}
+
+func TestVirtualTransportMulticastGroup(t *testing.T) {
+	vnet := NewVirtualNetwork()
+	transports, handlers := newTestVirtualTransports(t, vnet, 3)
+
+	// The first transport joins another group, and the second transport disables the loopback.
+
+	for _, tp := range transports[:2] {
+		tp.Stop()
+	}
+	transports[0].Config().SetMulticastIPv4Address(testMulticastIPv4Address)
+	transports[0].Config().SetMulticastPort(testMulticastPort)
+	transports[1].Config().SetMulticastLoopbackEnabled(false)
+	for _, tp := range transports[:2] {
+		if err := tp.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msg, err := newTestMessage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.SetESV(protocol.ESVNotification)
+	if err := transports[1].AnnounceMessage(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	isMulticastMessage := func(msg *protocol.Message) bool {
+		return msg.IsPacketType(protocol.MulticastPacket)
+	}
+	if !handlers[2].waitMessage(isMulticastMessage) {
+		t.Errorf("multicast message is not received in the same group")
+	}
+	for _, n := range []int{0, 1} {
+		if msgs := handlers[n].messages(); len(msgs) != 0 {
+			t.Errorf("[%d] multicast message is received (%v)", n, msgs)
+		}
+	}
+
+	transports[2].Config().SetMulticastTTL(-1)
+	if err := transports[2].Start(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("invalid multicast TTL is accepted (%v)", err)
+	}
+}